@@ -0,0 +1,8 @@
+module relayer/appchains/adapter/adapterpb
+
+go 1.14
+
+require (
+	github.com/golang/protobuf v1.4.3
+	google.golang.org/grpc v1.35.0
+)
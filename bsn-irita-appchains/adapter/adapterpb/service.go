@@ -0,0 +1,231 @@
+package adapterpb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	ServiceName = "relayer.adapter.v1.AppChainAdapter" // full name of the adapter service
+
+	methodStart        = "/" + ServiceName + "/Start"
+	methodSendResponse = "/" + ServiceName + "/SendResponse"
+	methodGetHeight    = "/" + ServiceName + "/GetHeight"
+	methodHealth       = "/" + ServiceName + "/Health"
+	methodCallContract = "/" + ServiceName + "/CallContract"
+)
+
+// AppChainAdapterClient defines the client of the adapter service
+type AppChainAdapterClient interface {
+	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (AppChainAdapter_StartClient, error)
+	SendResponse(ctx context.Context, in *SendResponseRequest, opts ...grpc.CallOption) (*SendResponseReply, error)
+	GetHeight(ctx context.Context, in *GetHeightRequest, opts ...grpc.CallOption) (*GetHeightReply, error)
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthReply, error)
+	CallContract(ctx context.Context, in *CallContractRequest, opts ...grpc.CallOption) (*CallContractReply, error)
+}
+
+// AppChainAdapter_StartClient receives the interchain requests streamed by the adapter
+type AppChainAdapter_StartClient interface {
+	Recv() (*InterchainRequest, error)
+	grpc.ClientStream
+}
+
+type appChainAdapterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewAppChainAdapterClient constructs a new client of the adapter service on the given connection
+func NewAppChainAdapterClient(cc grpc.ClientConnInterface) AppChainAdapterClient {
+	return &appChainAdapterClient{cc}
+}
+
+func (c *appChainAdapterClient) Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (AppChainAdapter_StartClient, error) {
+	stream, err := c.cc.NewStream(ctx, &serviceDesc.Streams[0], methodStart, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	x := &appChainAdapterStartClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	return x, nil
+}
+
+type appChainAdapterStartClient struct {
+	grpc.ClientStream
+}
+
+func (x *appChainAdapterStartClient) Recv() (*InterchainRequest, error) {
+	m := new(InterchainRequest)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (c *appChainAdapterClient) SendResponse(ctx context.Context, in *SendResponseRequest, opts ...grpc.CallOption) (*SendResponseReply, error) {
+	out := new(SendResponseReply)
+	if err := c.cc.Invoke(ctx, methodSendResponse, in, out, opts...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (c *appChainAdapterClient) GetHeight(ctx context.Context, in *GetHeightRequest, opts ...grpc.CallOption) (*GetHeightReply, error) {
+	out := new(GetHeightReply)
+	if err := c.cc.Invoke(ctx, methodGetHeight, in, out, opts...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (c *appChainAdapterClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthReply, error) {
+	out := new(HealthReply)
+	if err := c.cc.Invoke(ctx, methodHealth, in, out, opts...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (c *appChainAdapterClient) CallContract(ctx context.Context, in *CallContractRequest, opts ...grpc.CallOption) (*CallContractReply, error) {
+	out := new(CallContractReply)
+	if err := c.cc.Invoke(ctx, methodCallContract, in, out, opts...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// AppChainAdapterServer defines the adapter service implemented by the adapter written in Go
+type AppChainAdapterServer interface {
+	Start(*StartRequest, AppChainAdapter_StartServer) error
+	SendResponse(context.Context, *SendResponseRequest) (*SendResponseReply, error)
+	GetHeight(context.Context, *GetHeightRequest) (*GetHeightReply, error)
+	Health(context.Context, *HealthRequest) (*HealthReply, error)
+	CallContract(context.Context, *CallContractRequest) (*CallContractReply, error)
+}
+
+// AppChainAdapter_StartServer streams the interchain requests to the relayer
+type AppChainAdapter_StartServer interface {
+	Send(*InterchainRequest) error
+	grpc.ServerStream
+}
+
+type appChainAdapterStartServer struct {
+	grpc.ServerStream
+}
+
+func (x *appChainAdapterStartServer) Send(m *InterchainRequest) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// RegisterAppChainAdapterServer registers the adapter service on the given gRPC server
+func RegisterAppChainAdapterServer(s *grpc.Server, srv AppChainAdapterServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+func startHandler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StartRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return srv.(AppChainAdapterServer).Start(m, &appChainAdapterStartServer{stream})
+}
+
+func sendResponseHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendResponseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AppChainAdapterServer).SendResponse(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodSendResponse}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppChainAdapterServer).SendResponse(ctx, req.(*SendResponseRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func getHeightHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AppChainAdapterServer).GetHeight(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodGetHeight}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppChainAdapterServer).GetHeight(ctx, req.(*GetHeightRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func healthHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AppChainAdapterServer).Health(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodHealth}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppChainAdapterServer).Health(ctx, req.(*HealthRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func callContractHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallContractRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AppChainAdapterServer).CallContract(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodCallContract}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppChainAdapterServer).CallContract(ctx, req.(*CallContractRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*AppChainAdapterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "SendResponse", Handler: sendResponseHandler},
+		{MethodName: "GetHeight", Handler: getHeightHandler},
+		{MethodName: "Health", Handler: healthHandler},
+		{MethodName: "CallContract", Handler: callContractHandler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "Start", Handler: startHandler, ServerStreams: true},
+	},
+	Metadata: "adapter.proto",
+}
@@ -0,0 +1,130 @@
+package adapterpb
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+// The messages of the adapter protocol defined in adapter.proto
+// They are encoded in the protobuf wire format by the struct tags
+
+// Chain identifies the app chain served by the adapter
+type Chain struct {
+	ChainId string `protobuf:"bytes,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	Params  []byte `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+}
+
+func (m *Chain) Reset()         { *m = Chain{} }
+func (m *Chain) String() string { return proto.CompactTextString(m) }
+func (*Chain) ProtoMessage()    {}
+
+// StartRequest defines the request to start streaming the interchain requests
+type StartRequest struct {
+	Chain       *Chain `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	StartHeight int64  `protobuf:"varint,2,opt,name=start_height,json=startHeight,proto3" json:"start_height,omitempty"`
+}
+
+func (m *StartRequest) Reset()         { *m = StartRequest{} }
+func (m *StartRequest) String() string { return proto.CompactTextString(m) }
+func (*StartRequest) ProtoMessage()    {}
+
+// InterchainRequest defines the interchain request detected on the chain
+type InterchainRequest struct {
+	Id              string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DestChainId     string `protobuf:"bytes,2,opt,name=dest_chain_id,json=destChainId,proto3" json:"dest_chain_id,omitempty"`
+	DestSubChainId  string `protobuf:"bytes,3,opt,name=dest_sub_chain_id,json=destSubChainId,proto3" json:"dest_sub_chain_id,omitempty"`
+	DestChainType   string `protobuf:"bytes,4,opt,name=dest_chain_type,json=destChainType,proto3" json:"dest_chain_type,omitempty"`
+	EndpointAddress string `protobuf:"bytes,5,opt,name=endpoint_address,json=endpointAddress,proto3" json:"endpoint_address,omitempty"`
+	EndpointType    string `protobuf:"bytes,6,opt,name=endpoint_type,json=endpointType,proto3" json:"endpoint_type,omitempty"`
+	Method          string `protobuf:"bytes,7,opt,name=method,proto3" json:"method,omitempty"`
+	CallData        []byte `protobuf:"bytes,8,opt,name=call_data,json=callData,proto3" json:"call_data,omitempty"`
+	TxHash          string `protobuf:"bytes,9,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Sender          string `protobuf:"bytes,10,opt,name=sender,proto3" json:"sender,omitempty"`
+	Timeout         int64  `protobuf:"varint,11,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Timestamp       int64  `protobuf:"varint,12,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Height          int64  `protobuf:"varint,13,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (m *InterchainRequest) Reset()         { *m = InterchainRequest{} }
+func (m *InterchainRequest) String() string { return proto.CompactTextString(m) }
+func (*InterchainRequest) ProtoMessage()    {}
+
+// SendResponseRequest defines the request to send the response to the chain
+type SendResponseRequest struct {
+	Chain     *Chain `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	RequestId string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ErrMsg    string `protobuf:"bytes,3,opt,name=err_msg,json=errMsg,proto3" json:"err_msg,omitempty"`
+	Output    string `protobuf:"bytes,4,opt,name=output,proto3" json:"output,omitempty"`
+}
+
+func (m *SendResponseRequest) Reset()         { *m = SendResponseRequest{} }
+func (m *SendResponseRequest) String() string { return proto.CompactTextString(m) }
+func (*SendResponseRequest) ProtoMessage()    {}
+
+// SendResponseReply defines the reply of SendResponse
+type SendResponseReply struct {
+	TxHash string `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+}
+
+func (m *SendResponseReply) Reset()         { *m = SendResponseReply{} }
+func (m *SendResponseReply) String() string { return proto.CompactTextString(m) }
+func (*SendResponseReply) ProtoMessage()    {}
+
+// GetHeightRequest defines the request to get the latest height
+type GetHeightRequest struct {
+	Chain *Chain `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+}
+
+func (m *GetHeightRequest) Reset()         { *m = GetHeightRequest{} }
+func (m *GetHeightRequest) String() string { return proto.CompactTextString(m) }
+func (*GetHeightRequest) ProtoMessage()    {}
+
+// GetHeightReply defines the reply of GetHeight
+type GetHeightReply struct {
+	Height int64  `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Node   string `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+}
+
+func (m *GetHeightReply) Reset()         { *m = GetHeightReply{} }
+func (m *GetHeightReply) String() string { return proto.CompactTextString(m) }
+func (*GetHeightReply) ProtoMessage()    {}
+
+// HealthRequest defines the request to check the health of the adapter
+type HealthRequest struct {
+	Chain *Chain `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+}
+
+func (m *HealthRequest) Reset()         { *m = HealthRequest{} }
+func (m *HealthRequest) String() string { return proto.CompactTextString(m) }
+func (*HealthRequest) ProtoMessage()    {}
+
+// HealthReply defines the reply of Health
+type HealthReply struct {
+	Healthy bool   `protobuf:"varint,1,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	Error   string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *HealthReply) Reset()         { *m = HealthReply{} }
+func (m *HealthReply) String() string { return proto.CompactTextString(m) }
+func (*HealthReply) ProtoMessage()    {}
+
+// CallContractRequest defines the request to call the contract on the chain
+type CallContractRequest struct {
+	Chain           *Chain `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	EndpointAddress string `protobuf:"bytes,2,opt,name=endpoint_address,json=endpointAddress,proto3" json:"endpoint_address,omitempty"`
+	Method          string `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	CallData        []byte `protobuf:"bytes,4,opt,name=call_data,json=callData,proto3" json:"call_data,omitempty"`
+}
+
+func (m *CallContractRequest) Reset()         { *m = CallContractRequest{} }
+func (m *CallContractRequest) String() string { return proto.CompactTextString(m) }
+func (*CallContractRequest) ProtoMessage()    {}
+
+// CallContractReply defines the reply of CallContract
+type CallContractReply struct {
+	Output string `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	TxHash string `protobuf:"bytes,2,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+}
+
+func (m *CallContractReply) Reset()         { *m = CallContractReply{} }
+func (m *CallContractReply) String() string { return proto.CompactTextString(m) }
+func (*CallContractReply) ProtoMessage()    {}
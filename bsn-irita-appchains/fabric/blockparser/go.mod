@@ -0,0 +1,8 @@
+module relayer/appchains/fabric/blockparser
+
+go 1.14
+
+require (
+	github.com/golang/protobuf v1.4.3
+	github.com/hyperledger/fabric-sdk-go v1.0.0-alpha5
+)
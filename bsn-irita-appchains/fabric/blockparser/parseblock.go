@@ -1,4 +1,4 @@
-package fabric
+package blockparser
 
 import (
 	"crypto/sha256"
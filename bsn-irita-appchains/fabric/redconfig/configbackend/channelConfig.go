@@ -5,7 +5,7 @@ type ChannelConfig struct {
 	PeerName  string
 }
 
-////此方法用于设置config配置文件的channels部分的节点配置
+// //此方法用于设置config配置文件的channels部分的节点配置
 func (o *ChannelConfig) SetChannelConfig(m *map[string]interface{}) {
 
 	p := *m
@@ -22,7 +22,7 @@ func (o *ChannelConfig) SetChannelConfig(m *map[string]interface{}) {
 
 }
 
-//TODO：缺少注释
+// TODO：缺少注释
 func GetPoliciesConfig() map[string]interface{} {
 	m := make(map[string]interface{})
 
@@ -47,7 +47,7 @@ type ChannelPeerConfig struct {
 	PeerName string
 }
 
-//此方法用于设置config配置文件的channels-通道名-peers-节点部分的配置
+// 此方法用于设置config配置文件的channels-通道名-peers-节点部分的配置
 func (o *ChannelPeerConfig) GetChannelPeerConfig(m *map[string]interface{}) map[string]interface{} {
 	p := *m
 	peerparameters := make(map[string]interface{})
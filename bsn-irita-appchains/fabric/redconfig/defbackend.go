@@ -21,7 +21,7 @@ func (c *defConfigBackend) Lookup(key string) (interface{}, bool) {
 	return value, true
 }
 
-//新增Set方法，设置配置
+// 新增Set方法，设置配置
 func (c *defConfigBackend) Set(key string, value interface{}) {
 	c.configViper.Set(key, value)
 }
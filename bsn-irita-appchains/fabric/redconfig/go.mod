@@ -0,0 +1,9 @@
+module relayer/appchains/fabric/redconfig
+
+go 1.14
+
+require (
+	github.com/hyperledger/fabric-sdk-go v1.0.0-alpha5
+	github.com/pkg/errors v0.9.1
+	github.com/spf13/viper v1.7.1
+)
@@ -1,7 +1,7 @@
 package redconfig
 
 import (
-	config "relayer/appchains/fabric/config/redconfig/configbackend"
+	config "relayer/appchains/fabric/redconfig/configbackend"
 )
 
 const (
@@ -12,7 +12,8 @@ const (
 
 // CompactBlock represents the compact block with tx hashes
 type CompactBlock struct {
-	Txs []string `json:"transactions"`
+	Txs       []string `json:"transactions"`
+	Timestamp string   `json:"timestamp"` // hex unix time in milliseconds
 }
 
 // ChainParams defines the params for the specific chain
@@ -21,6 +22,10 @@ type ChainParams struct {
 	GroupID          int      `json:"groupId"`
 	ChainID          int64    `json:"chainId"`
 	IServiceCoreAddr string   `json:"iserviceCoreAddr"`
+	StartHeight      int64    `json:"startHeight,omitempty"` // height to start scanning from when it is beyond the persisted height
+	FromLatest       bool     `json:"fromLatest,omitempty"`  // whether to skip the missed blocks and start from the latest block
+	Timeout          int64    `json:"timeout,omitempty"`     // service timeout in blocks on the Hub for the requests of the chain
+	Signer           string   `json:"signer,omitempty"`      // name of the configured signer, the key in the base config is used if empty
 }
 
 type EndpointInfo struct {
@@ -5,33 +5,40 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
 
 	"github.com/FISCO-BCOS/go-sdk/abi"
-	fiscoclient "github.com/FISCO-BCOS/go-sdk/client"
+	"github.com/FISCO-BCOS/go-sdk/abi/bind"
 	"github.com/FISCO-BCOS/go-sdk/core/types"
 
 	"relayer/appchains/fisco/iservice"
-	txstore "relayer/appchains/fisco/store"
+	txstore "relayer/appchains/store"
 	"relayer/core"
 	"relayer/logging"
+	"relayer/metrics"
+	"relayer/secrets"
+	"relayer/signer"
 	"relayer/store"
 )
 
 // FISCOChain defines the FISCO chain
 type FISCOChain struct {
 	Config  Config
-	Client  *fiscoclient.Client
 	ChainID string // unique chain ID
 
-	IServiceCoreSession *iservice.IServiceCoreExSession // iService Core Extension contract session
-	IServiceCoreABI     abi.ABI                         // parsed iService Core Extension ABI
+	IServiceCoreABI abi.ABI // parsed iService Core Extension ABI
 
-	store      *store.Store // store backend instance
-	lastHeight int64        // last height
+	nodes        *NodeClient       // client of the active node
+	callOpts     bind.CallOpts     // call options of the iService Core Extension contract session
+	transactOpts bind.TransactOpts // transact options of the iService Core Extension contract session
+
+	store       *store.Store // store backend instance
+	lastHeight  int64        // last height
+	startHeight int64        // height to start scanning from, 0 for the latest block
 
 	done    bool                          // indicates if the chain monitor is done
 	handler core.InterchainRequestHandler // handler for the interchain request
@@ -42,37 +49,50 @@ func NewFISCOChain(
 	config Config,
 	store *store.Store,
 ) (*FISCOChain, error) {
-	clientConfig := BuildClientConfig(config)
-
-	client, err := fiscoclient.Dial(clientConfig)
+	nodes, err := NewNodeClient(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to fisco node: %s", err)
+		return nil, err
 	}
+	client := nodes.Client()
 
 	iServiceCoreABI, err := abi.JSON(strings.NewReader(iservice.IServiceCoreExABI))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse iService Core Extension ABI: %s", err)
 	}
 
-	iServiceCore, err := iservice.NewIServiceCoreEx(ethcmn.HexToAddress(config.IServiceCoreAddr), client)
-	if err != nil {
-		return nil, fmt.Errorf("failed to instantiate the iService Core Extension contract: %s", err)
-	}
-
 	if config.MonitorInterval == 0 {
 		config.MonitorInterval = DefaultMonitorInterval
 	}
 
 	chainID := GetChainID(config.ChainParams)
 
+	callOpts := *client.GetCallOpts()
+	transactOpts := *client.GetTransactOpts()
+
+	if len(config.ChainParams.Signer) > 0 {
+		if config.IsSMCrypto {
+			return nil, fmt.Errorf("signer %s is not supported with sm crypto", config.ChainParams.Signer)
+		}
+
+		keySigner, err := signer.Select(config.Signers, config.ChainParams.Signer)
+		if err != nil {
+			return nil, err
+		}
+
+		callOpts.From = keySigner.Address()
+		transactOpts.From = keySigner.Address()
+		transactOpts.Signer = buildSignerFn(keySigner)
+	}
+
 	fisco := &FISCOChain{
-		Config:              config,
-		Client:              client,
-		ChainID:             chainID,
-		IServiceCoreSession: &iservice.IServiceCoreExSession{Contract: iServiceCore, CallOpts: *client.GetCallOpts(), TransactOpts: *client.GetTransactOpts()},
-		IServiceCoreABI:     iServiceCoreABI,
-		store:               store,
-		done:                true,
+		Config:          config,
+		ChainID:         chainID,
+		IServiceCoreABI: iServiceCoreABI,
+		nodes:           nodes,
+		callOpts:        callOpts,
+		transactOpts:    transactOpts,
+		store:           store,
+		done:            true,
 	}
 
 	err = fisco.storeChainParams()
@@ -88,13 +108,34 @@ func NewFISCOChain(
 	return fisco, nil
 }
 
+// buildSignerFn builds the tx signing function with the given signer
+func buildSignerFn(keySigner signer.Signer) bind.SignerFn {
+	return func(txSigner types.Signer, address ethcmn.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != keySigner.Address() {
+			return nil, fmt.Errorf("not authorized to sign for %s", address.Hex())
+		}
+
+		sig, err := keySigner.Sign(txSigner.Hash(tx).Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		return tx.WithSignature(txSigner, sig)
+	}
+}
+
 // BuildFISCOChain builds a FISCOChain instance from the given chain params and store
 func BuildFISCOChain(
 	chainParams []byte,
 	store *store.Store,
 ) (*FISCOChain, error) {
+	chainParams, err := secrets.Open(chainParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the chain params: %s", err)
+	}
+
 	var params ChainParams
-	err := json.Unmarshal(chainParams, &params)
+	err = json.Unmarshal(chainParams, &params)
 	if err != nil {
 		return nil, err
 	}
@@ -104,6 +145,11 @@ func BuildFISCOChain(
 		return nil, err
 	}
 
+	baseCfgBz, err = secrets.Open(baseCfgBz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the base config: %s", err)
+	}
+
 	var baseConfig BaseConfig
 	err = json.Unmarshal(baseCfgBz, &baseConfig)
 	if err != nil {
@@ -129,6 +175,13 @@ func (f *FISCOChain) Start(handler core.InterchainRequestHandler) error {
 		return fmt.Errorf("chain %s has been started", f.ChainID)
 	}
 
+	if f.lastHeight == 0 {
+		err := f.loadStartHeight()
+		if err != nil {
+			return err
+		}
+	}
+
 	f.done = false
 	f.handler = handler
 
@@ -147,8 +200,8 @@ func (f *FISCOChain) Stop() error {
 	return nil
 }
 
-func (f *FISCOChain) Close(){
-	f.Client.Close()
+func (f *FISCOChain) Close() {
+	f.nodes.Close()
 }
 
 // GetHeight implements AppChainI
@@ -156,6 +209,30 @@ func (f *FISCOChain) GetHeight() int64 {
 	return f.lastHeight
 }
 
+// GetActiveNode implements AppChainI
+func (f *FISCOChain) GetActiveNode() string {
+	return f.nodes.ActiveNode()
+}
+
+// session builds the iService Core Extension contract session on the active node
+func (f *FISCOChain) session() (*iservice.IServiceCoreExSession, error) {
+	iServiceCore, err := iservice.NewIServiceCoreEx(ethcmn.HexToAddress(f.Config.IServiceCoreAddr), f.nodes.Client())
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate the iService Core Extension contract: %s", err)
+	}
+
+	return &iservice.IServiceCoreExSession{Contract: iServiceCore, CallOpts: f.callOpts, TransactOpts: f.transactOpts}, nil
+}
+
+// failover switches to the next node if the given node is still active
+func (f *FISCOChain) failover(node string, cause error) {
+	logging.Logger.Errorf("node %s of chain %s failed: %s", node, f.ChainID, cause)
+
+	if err := f.nodes.Failover(node, cause); err != nil {
+		logging.Logger.Errorf("failed to fail over on %s: %s", f.ChainID, err)
+	}
+}
+
 // SendResponse implements AppChainI
 func (f *FISCOChain) SendResponse(requestID string, response core.ResponseI) error {
 	requestIDBytes, err := hex.DecodeString(requestID)
@@ -176,7 +253,15 @@ func (f *FISCOChain) SendResponse(requestID string, response core.ResponseI) err
 	var requestID32Bytes [32]byte
 	copy(requestID32Bytes[:], requestIDBytes)
 
-	tx, _, err := f.IServiceCoreSession.SetResponse(requestID32Bytes, response.GetErrMsg(), response.GetOutput())
+	session, err := f.session()
+	if err != nil {
+		data.TxStatus = txstore.TxStatus_Error
+		data.ErrMsg = err.Error()
+
+		return err
+	}
+
+	tx, _, err := session.SetResponse(requestID32Bytes, response.GetErrMsg(), response.GetOutput())
 	if err != nil {
 		data.TxStatus = txstore.TxStatus_Error
 		data.ErrMsg = fmt.Sprintf("call fisco setResponse failed :%s", err)
@@ -186,8 +271,7 @@ func (f *FISCOChain) SendResponse(requestID string, response core.ResponseI) err
 
 	data.FromResTxId = tx.Hash().Hex()
 
-	// TODO
-	//mysql.OnInterchainRequestResponseSent(requestID, tx.Hash().Hex())
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseSent, data.FromResTxId, "")
 
 	err = f.waitForReceipt(tx, "SetResponse")
 	if err != nil {
@@ -197,8 +281,7 @@ func (f *FISCOChain) SendResponse(requestID string, response core.ResponseI) err
 		return err
 	}
 
-	// TODO
-	//mysql.OnInterchainRequestSucceeded(requestID)
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseConfirmed, data.FromResTxId, "")
 
 	return nil
 }
@@ -221,6 +304,7 @@ func (f *FISCOChain) buildInterchainRequest(e *iservice.IServiceCoreExCrossChain
 		Method:          e.Method,
 		CallData:        e.CallData,
 		Sender:          e.Sender.String(),
+		Timeout:         f.Config.Timeout,
 	}
 }
 
@@ -228,7 +312,7 @@ func (f *FISCOChain) buildInterchainRequest(e *iservice.IServiceCoreExCrossChain
 func (f *FISCOChain) waitForReceipt(tx *types.Transaction, name string) error {
 	logging.Logger.Infof("%s: transaction sent to %s, hash: %s", name, f.GetChainID(), tx.Hash().Hex())
 
-	receipt, err := f.Client.WaitMined(tx)
+	receipt, err := f.nodes.Client().WaitMined(tx)
 	if err != nil {
 		return fmt.Errorf("failed to mint the transaction %s: %s", tx.Hash().Hex(), err)
 	}
@@ -244,9 +328,16 @@ func (f *FISCOChain) waitForReceipt(tx *types.Transaction, name string) error {
 
 // monitor is responsible for monitoring the chain
 func (f *FISCOChain) monitor() {
+	lastCheck := time.Now()
+
 	for {
 		f.scan()
 
+		if time.Since(lastCheck) >= DefaultHealthCheckInterval*time.Second {
+			f.nodes.CheckHealth()
+			lastCheck = time.Now()
+		}
+
 		if f.done {
 			return
 		}
@@ -257,14 +348,22 @@ func (f *FISCOChain) monitor() {
 
 // scan performs chain scanning
 func (f *FISCOChain) scan() {
+	node := f.nodes.ActiveNode()
+
 	currentHeight, err := f.getBlockNumber()
 	if err != nil {
-		logging.Logger.Errorf("failed to get the current block height: %s", err)
+		f.failover(node, fmt.Errorf("failed to get the current block height: %s", err))
 		return
 	}
 
+	metrics.SetHeadHeight(f.ChainID, currentHeight)
+
 	if f.lastHeight == 0 {
-		f.lastHeight = currentHeight - 1
+		if f.startHeight > 0 {
+			f.lastHeight = f.startHeight - 1
+		} else {
+			f.lastHeight = currentHeight - 1
+		}
 	}
 
 	if currentHeight <= f.lastHeight {
@@ -277,10 +376,16 @@ func (f *FISCOChain) scan() {
 // scanBlocks scans the blocks of the specified range
 func (f *FISCOChain) scanBlocks(startHeight int64, endHeight int64) {
 	for h := startHeight; h <= endHeight; {
+		if f.done {
+			return
+		}
+
 		logging.Logger.Infof("scanBlock Height is %d", h)
+		node := f.nodes.ActiveNode()
+
 		block, err := f.getBlock(h)
 		if err != nil {
-			logging.Logger.Errorf(err.Error())
+			f.failover(node, err)
 			continue
 		}
 
@@ -297,7 +402,7 @@ func (f *FISCOChain) scanBlocks(startHeight int64, endHeight int64) {
 
 // getBlockNumber retrieves the current block number
 func (f *FISCOChain) getBlockNumber() (int64, error) {
-	blockNumber, err := f.Client.GetBlockNumber(context.Background())
+	blockNumber, err := f.nodes.Client().GetBlockNumber(context.Background())
 
 	return blockNumber, err
 	//if err != nil {
@@ -311,7 +416,7 @@ func (f *FISCOChain) getBlockNumber() (int64, error) {
 
 // getBlock gets the block in the given height
 func (f *FISCOChain) getBlock(height int64) (block CompactBlock, err error) {
-	blockBz, err := f.Client.GetBlockByNumber(context.Background(), height, false)
+	blockBz, err := f.nodes.Client().GetBlockByNumber(context.Background(), height, false)
 	if err != nil {
 		return block, fmt.Errorf("failed to retrieve the block, height: %d, err: %s", height, err)
 	}
@@ -327,7 +432,7 @@ func (f *FISCOChain) getBlock(height int64) (block CompactBlock, err error) {
 // parseInterchainEventsFromBlock parses the interchain events from the block
 func (f *FISCOChain) parseInterchainEventsFromBlock(block CompactBlock) {
 	for _, txHash := range block.Txs {
-		receipt, err := f.Client.GetTransactionReceipt(context.Background(), ethcmn.HexToHash(txHash))
+		receipt, err := f.nodes.Client().GetTransactionReceipt(context.Background(), ethcmn.HexToHash(txHash))
 		if err != nil {
 			logging.Logger.Errorf("failed to get the receipt, tx: %s, err: %s", txHash, err)
 			continue
@@ -337,12 +442,12 @@ func (f *FISCOChain) parseInterchainEventsFromBlock(block CompactBlock) {
 			continue
 		}
 
-		f.parseCrossChaiRequestSentEvents(receipt)
+		f.parseCrossChaiRequestSentEvents(receipt, block)
 	}
 }
 
 // parseServiceInvokedEvents parses the ServiceInvoked events from the receipt
-func (f *FISCOChain) parseCrossChaiRequestSentEvents(receipt *types.Receipt) {
+func (f *FISCOChain) parseCrossChaiRequestSentEvents(receipt *types.Receipt, block CompactBlock) {
 	for _, log := range receipt.Logs {
 		if !strings.EqualFold(log.Address, f.Config.IServiceCoreAddr) {
 			continue
@@ -362,6 +467,8 @@ func (f *FISCOChain) parseCrossChaiRequestSentEvents(receipt *types.Receipt) {
 		}
 
 		request := f.buildInterchainRequest(&event)
+		request.Timestamp, _ = strconv.ParseInt(strings.TrimPrefix(block.Timestamp, "0x"), 16, 64)
+
 		_ = f.handler(f.ChainID, request, receipt.TransactionHash)
 	}
 }
@@ -373,6 +480,11 @@ func (f *FISCOChain) storeChainParams() error {
 		return err
 	}
 
+	bz, err = secrets.Seal(bz)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the chain params: %s", err)
+	}
+
 	return f.store.Set(ChainParamsKey(f.ChainID), bz)
 }
 
@@ -391,8 +503,41 @@ func (f *FISCOChain) storeChainID() error {
 	return f.store.Set([]byte("chainIDs"), bz)
 }
 
+// loadStartHeight determines the height to start scanning from
+// The scanning resumes from the persisted height unless the chain params specify
+// a greater start height or to start from the latest block
+func (f *FISCOChain) loadStartHeight() error {
+	f.startHeight = 0
+
+	if f.Config.FromLatest {
+		logging.Logger.Infof("chain %s starts scanning from the latest block", f.ChainID)
+		return nil
+	}
+
+	height, err := f.store.GetInt64(HeightKey(f.ChainID))
+	if err != nil && err != store.ErrNotFound {
+		return fmt.Errorf("failed to load the height of chain %s: %s", f.ChainID, err)
+	}
+
+	if err == nil {
+		f.startHeight = height + 1
+	}
+
+	if f.Config.StartHeight > f.startHeight {
+		f.startHeight = f.Config.StartHeight
+	}
+
+	if f.startHeight > 0 {
+		logging.Logger.Infof("chain %s starts scanning from height %d", f.ChainID, f.startHeight)
+	}
+
+	return nil
+}
+
 // updateHeight updates the height
 func (f *FISCOChain) updateHeight(height int64) error {
 	f.lastHeight = height
+	metrics.SetScannedHeight(f.ChainID, height)
+
 	return f.store.SetInt64(HeightKey(f.ChainID), height)
 }
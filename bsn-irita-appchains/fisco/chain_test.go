@@ -0,0 +1,66 @@
+package fisco
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"relayer/store"
+)
+
+func TestLoadStartHeight(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fisco-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := &FISCOChain{ChainID: "1", store: s}
+
+	if err := chain.loadStartHeight(); err != nil {
+		t.Fatal(err)
+	}
+	if chain.startHeight != 0 {
+		t.Fatalf("expected to start from the latest block, got %d", chain.startHeight)
+	}
+
+	if err := s.SetInt64(HeightKey(chain.ChainID), 100); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.loadStartHeight(); err != nil {
+		t.Fatal(err)
+	}
+	if chain.startHeight != 101 {
+		t.Fatalf("expected to resume from 101, got %d", chain.startHeight)
+	}
+
+	chain.Config.StartHeight = 50
+	if err := chain.loadStartHeight(); err != nil {
+		t.Fatal(err)
+	}
+	if chain.startHeight != 101 {
+		t.Fatalf("expected the persisted height to take precedence, got %d", chain.startHeight)
+	}
+
+	chain.Config.StartHeight = 200
+	if err := chain.loadStartHeight(); err != nil {
+		t.Fatal(err)
+	}
+	if chain.startHeight != 200 {
+		t.Fatalf("expected to start from 200, got %d", chain.startHeight)
+	}
+
+	chain.Config.FromLatest = true
+	if err := chain.loadStartHeight(); err != nil {
+		t.Fatal(err)
+	}
+	if chain.startHeight != 0 {
+		t.Fatalf("expected to start from the latest block, got %d", chain.startHeight)
+	}
+}
@@ -3,7 +3,6 @@ package fisco
 import (
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -12,6 +11,7 @@ import (
 	"github.com/FISCO-BCOS/go-sdk/conf"
 
 	cfg "relayer/config"
+	"relayer/signer"
 )
 
 const (
@@ -27,6 +27,7 @@ const (
 	PrivateKeyFile  = "priv_key_file"
 	MonitorInterval = "monitor_interval"
 	Nodes           = "nodes"
+	Signers         = "signers"
 )
 
 // BaseConfig defines the base config
@@ -40,9 +41,10 @@ type BaseConfig struct {
 	MonitorInterval uint64
 	NodesMap        map[string]string
 	ChainId         int64
+	Signers         map[string]signer.Config // named signers selectable by the chains
 }
 
-func (bc *BaseConfig) PrintConfig(){
+func (bc *BaseConfig) PrintConfig() {
 }
 
 // Config defines the specific chain config
@@ -96,31 +98,14 @@ func NewBaseConfig(v *viper.Viper) (*BaseConfig, error) {
 	config.MonitorInterval = monitorInterval
 
 	config.NodesMap = v.GetStringMapString(cfg.GetConfigKey(Prefix, Nodes))
+	config.Signers = signer.LoadConfigs(v, cfg.GetConfigKey(Prefix, Signers))
 	logging.Logger.Infof("config fisco nods : %v", config.NodesMap)
 
 	return config, nil
 }
-func randURL(m []string) string {
-	if len(m) == 0 {
-		return ""
-	}
-	for _, index := range rand.Perm(len(m)) {
-		return m[index]
-	}
-	return ""
-}
-
-// BuildClientConfig builds the FISCO client config from the given Config
-func BuildClientConfig(config Config) *conf.Config {
-	//将接口传递的节点名称通过配置转换为 节点地址，如果不在配置中，不转换
-	//随机取一个传入的node
-	nodeName := randURL(config.NodeURLs)
-	//获取配置的nodeURL
-	nodeUrl, ok := config.NodesMap[nodeName]
-	if ok {
-		nodeName = nodeUrl
-	}
 
+// BuildClientConfig builds the FISCO client config for the given node from the given Config
+func BuildClientConfig(config Config, nodeURL string) *conf.Config {
 	return &conf.Config{
 		IsHTTP:     config.IsHTTP,
 		CAFile:     config.CAFile,
@@ -130,7 +115,7 @@ func BuildClientConfig(config Config) *conf.Config {
 		IsSMCrypto: config.IsSMCrypto,
 		GroupID:    config.GroupID,
 		ChainID:    config.BaseConfig.ChainId,
-		NodeURL:    nodeName,
+		NodeURL:    nodeURL,
 	}
 }
 
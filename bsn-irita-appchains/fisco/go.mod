@@ -0,0 +1,9 @@
+module relayer/appchains/fisco
+
+go 1.14
+
+require (
+	github.com/FISCO-BCOS/go-sdk v0.11.0
+	github.com/ethereum/go-ethereum v1.9.18
+	github.com/spf13/viper v1.7.1
+)
@@ -0,0 +1,97 @@
+package fisco
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+
+	"github.com/FISCO-BCOS/go-sdk/core/types"
+
+	"relayer/core"
+	"relayer/logging"
+)
+
+const (
+	DefaultGasLimit = 30000000 // gas limit of the contract call, same as the default of the SDK
+	DefaultGasPrice = 30000000 // gas price of the contract call, same as the default of the SDK
+)
+
+// CallContract implements ContractCallerI
+// The call data is sent as the tx data to the contract, which includes the method selector
+func (f *FISCOChain) CallContract(endpointAddress string, method string, callData []byte) (output string, txHash string, err error) {
+	if !ethcmn.IsHexAddress(endpointAddress) {
+		return "", "", fmt.Errorf("invalid contract address %s", endpointAddress)
+	}
+
+	// nothing is sent if the tx is failed to build, while the sent tx can not be resent as the nonce is random
+	tx, err := f.buildSignedTx(ethcmn.HexToAddress(endpointAddress), callData)
+	if err != nil {
+		return "", "", core.NewTransientError(fmt.Errorf("failed to build the tx calling %s of %s: %s", method, endpointAddress, err))
+	}
+
+	txHash = tx.Hash().Hex()
+	logging.Logger.Infof("contract %s called on %s, method: %s, tx: %s", endpointAddress, f.ChainID, method, txHash)
+
+	receipt, err := f.nodes.Client().SendTransaction(context.Background(), tx)
+	if err != nil {
+		f.failover(f.nodes.ActiveNode(), err)
+		return "", txHash, fmt.Errorf("failed to send the tx %s: %s", txHash, err)
+	}
+
+	if receipt.Status != types.Success {
+		return receipt.Output, txHash, fmt.Errorf("tx %s of calling %s failed: %s", txHash, method, receipt.GetErrorMessage())
+	}
+
+	return receipt.Output, txHash, nil
+}
+
+// buildSignedTx builds the tx to the given contract with the call data, signed by the transact options
+func (f *FISCOChain) buildSignedTx(contract ethcmn.Address, callData []byte) (*types.Transaction, error) {
+	client := f.nodes.Client()
+	ctx := context.Background()
+
+	// the nonce is random between 0 and 2^250 - 1 as the SDK does
+	max := new(big.Int).Sub(new(big.Int).Exp(big.NewInt(2), big.NewInt(250), nil), big.NewInt(1))
+	nonce, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the nonce: %s", err)
+	}
+
+	blockLimit, err := client.GetBlockLimit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := client.GetChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groupID := client.GetGroupID()
+	if groupID == nil {
+		return nil, fmt.Errorf("failed to get the group ID")
+	}
+
+	rawTx := types.NewTransaction(
+		nonce,
+		contract,
+		new(big.Int),
+		big.NewInt(DefaultGasLimit),
+		big.NewInt(DefaultGasPrice),
+		blockLimit,
+		callData,
+		chainID,
+		groupID,
+		[]byte{},
+		client.SMCrypto(),
+	)
+
+	if f.transactOpts.Signer == nil {
+		return nil, fmt.Errorf("no signer to authorize the transaction with")
+	}
+
+	return f.transactOpts.Signer(types.HomesteadSigner{}, f.transactOpts.From, rawTx)
+}
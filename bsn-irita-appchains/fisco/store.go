@@ -3,6 +3,7 @@ package fisco
 import (
 	"fmt"
 
+	"relayer/secrets"
 	"relayer/store"
 )
 
@@ -36,5 +37,10 @@ func StoreBaseConfig(store *store.Store, baseConfig []byte) error {
 		return err
 	}
 
-	return store.Set(BaseConfigKey(), baseConfig)
+	bz, err := secrets.Seal(baseConfig)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the base config: %s", err)
+	}
+
+	return store.Set(BaseConfigKey(), bz)
 }
@@ -0,0 +1,38 @@
+package adapter
+
+import (
+	"encoding/json"
+)
+
+const (
+	ChainType              = "adapter"
+	DefaultMonitorInterval = 1  // 1 second by default
+	DefaultDialTimeout     = 10 // 10 seconds by default
+	DefaultCallTimeout     = 30 // timeout in seconds of the unary calls except SendResponse
+)
+
+// ChainParams defines the params for the specific chain
+type ChainParams struct {
+	ChainID     string          `json:"chainId"`
+	AdapterAddr string          `json:"adapterAddr"`           // gRPC address of the adapter process
+	Params      json.RawMessage `json:"params,omitempty"`      // adapter specific params passed through to the adapter
+	StartHeight int64           `json:"startHeight,omitempty"` // height to start from when it is beyond the persisted height
+	FromLatest  bool            `json:"fromLatest,omitempty"`  // whether to skip the missed blocks and start from the latest block
+	Timeout     int64           `json:"timeout,omitempty"`     // service timeout in blocks on the Hub for the requests of the chain
+}
+
+// GetChainID returns the unique chain id from the specified chain params
+func GetChainID(params ChainParams) string {
+	return params.ChainID
+}
+
+// GetChainIDFromBytes returns the unique chain id from the given chain params bytes
+func GetChainIDFromBytes(params []byte) (string, error) {
+	var chainParams ChainParams
+	err := json.Unmarshal(params, &chainParams)
+	if err != nil {
+		return "", err
+	}
+
+	return GetChainID(chainParams), nil
+}
@@ -0,0 +1,528 @@
+package adapter
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"relayer/appchains/adapter/adapterpb"
+	txstore "relayer/appchains/store"
+	"relayer/core"
+	"relayer/logging"
+	"relayer/metrics"
+	"relayer/secrets"
+	"relayer/store"
+)
+
+const (
+	DefaultHealthCheckInterval = 30 // interval in seconds to check the health of the adapter
+)
+
+// AdapterChain defines the app chain served by an external adapter process
+type AdapterChain struct {
+	Config    Config
+	ChainID   string // unique chain ID
+	ChainType string // type the chain is registered as, the adapter type or a type served by an adapter
+
+	conn    *grpc.ClientConn      // connection to the adapter
+	client  adapterpb.AppChainAdapterClient // client of the adapter service
+	store   *store.Store          // store backend instance
+	handler core.InterchainRequestHandler
+
+	mtx        sync.Mutex
+	lastHeight int64              // height of the last relayed request
+	node       string             // node connected by the adapter
+	done       bool               // whether the chain is stopped
+	cancel     context.CancelFunc // cancels the request stream
+}
+
+// NewAdapterChain constructs a new AdapterChain instance of the given chain type
+func NewAdapterChain(
+	chainType string,
+	config Config,
+	store *store.Store,
+) (*AdapterChain, error) {
+	chainID := GetChainID(config.ChainParams)
+	if len(chainID) == 0 {
+		return nil, fmt.Errorf("chain ID can not be empty")
+	}
+
+	if len(config.AdapterAddr) == 0 {
+		return nil, fmt.Errorf("adapter address of chain %s can not be empty", chainID)
+	}
+
+	conn, err := dial(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the adapter %s: %s", config.AdapterAddr, err)
+	}
+
+	ac := &AdapterChain{
+		Config:    config,
+		ChainID:   chainID,
+		ChainType: chainType,
+		conn:      conn,
+		client:    adapterpb.NewAppChainAdapterClient(conn),
+		store:     store,
+		done:      true,
+	}
+
+	err = ac.storeChainParams()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	err = ac.storeChainID()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return ac, nil
+}
+
+// BuildAdapterChain builds an AdapterChain instance of the given chain type from the given chain params and store
+func BuildAdapterChain(
+	chainType string,
+	chainParams []byte,
+	store *store.Store,
+) (*AdapterChain, error) {
+	chainParams, err := secrets.Open(chainParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the chain params: %s", err)
+	}
+
+	var params ChainParams
+	err = json.Unmarshal(chainParams, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	baseCfgBz, err := store.Get(BaseConfigKey(chainType))
+	if err != nil {
+		return nil, err
+	}
+
+	baseCfgBz, err = secrets.Open(baseCfgBz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the base config: %s", err)
+	}
+
+	var baseConfig BaseConfig
+	err = json.Unmarshal(baseCfgBz, &baseConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	config := Config{
+		BaseConfig:  baseConfig,
+		ChainParams: params,
+	}
+
+	return NewAdapterChain(chainType, config, store)
+}
+
+// dial connects to the adapter, with TLS if the CA is configured
+func dial(config Config) (*grpc.ClientConn, error) {
+	timeout := config.DialTimeout
+	if timeout == 0 {
+		timeout = DefaultDialTimeout
+	}
+
+	opts := []grpc.DialOption{grpc.WithBlock()}
+
+	if len(config.CAFile) > 0 {
+		tlsConfig, err := loadTLSConfig(config.BaseConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	return grpc.DialContext(ctx, config.AdapterAddr, opts...)
+}
+
+// loadTLSConfig loads the TLS config to verify the adapter and authenticate the relayer
+func loadTLSConfig(config BaseConfig) (*tls.Config, error) {
+	ca, err := ioutil.ReadFile(config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CA file: %s", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no certificate found in the CA file %s", config.CAFile)
+	}
+
+	tlsConfig := &tls.Config{RootCAs: pool}
+
+	if len(config.CertFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %s", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// GetChainID implements AppChainI
+func (ac *AdapterChain) GetChainID() string {
+	return ac.ChainID
+}
+
+// Start implements AppChainI
+func (ac *AdapterChain) Start(handler core.InterchainRequestHandler) error {
+	ac.mtx.Lock()
+	defer ac.mtx.Unlock()
+
+	if !ac.done {
+		return fmt.Errorf("chain %s has been started", ac.ChainID)
+	}
+
+	startHeight, err := ac.loadStartHeight()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stream, err := ac.client.Start(ctx, &adapterpb.StartRequest{Chain: ac.chain(), StartHeight: startHeight})
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to start chain %s on the adapter: %s", ac.ChainID, err)
+	}
+
+	ac.done = false
+	ac.handler = handler
+	ac.cancel = cancel
+
+	go ac.listen(ctx, stream)
+	go ac.healthCheck(ctx)
+
+	logging.Logger.Infof("chain %s started on the adapter %s from height %d", ac.ChainID, ac.Config.AdapterAddr, startHeight)
+
+	return nil
+}
+
+// Stop implements AppChainI
+func (ac *AdapterChain) Stop() error {
+	ac.mtx.Lock()
+	defer ac.mtx.Unlock()
+
+	logging.Logger.Infof("stopping chain %s", ac.ChainID)
+	if !ac.done {
+		ac.cancel()
+	}
+	ac.done = true
+
+	return nil
+}
+
+// Close implements AppChainI
+func (ac *AdapterChain) Close() {
+	ac.conn.Close()
+}
+
+// GetActiveNode implements AppChainI
+func (ac *AdapterChain) GetActiveNode() string {
+	ac.mtx.Lock()
+	defer ac.mtx.Unlock()
+
+	if len(ac.node) > 0 {
+		return ac.node
+	}
+
+	return ac.Config.AdapterAddr
+}
+
+// GetHeight implements AppChainI
+func (ac *AdapterChain) GetHeight() int64 {
+	ac.mtx.Lock()
+	defer ac.mtx.Unlock()
+
+	return ac.lastHeight
+}
+
+// SendResponse implements AppChainI
+func (ac *AdapterChain) SendResponse(requestID string, response core.ResponseI) error {
+	data := &txstore.RelayerResInfo{
+		RequestId: requestID,
+		TxStatus:  txstore.TxStatus_Success,
+		ErrMsg:    "",
+	}
+
+	defer func(d *txstore.RelayerResInfo) {
+		txstore.RelayerResponeRecord(d)
+	}(data)
+
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseSent, "", "")
+
+	reply, err := ac.client.SendResponse(context.Background(), &adapterpb.SendResponseRequest{
+		Chain:     ac.chain(),
+		RequestId: requestID,
+		ErrMsg:    response.GetErrMsg(),
+		Output:    response.GetOutput(),
+	})
+	if err != nil {
+		data.TxStatus = txstore.TxStatus_Error
+		data.ErrMsg = fmt.Sprintf("call adapter SendResponse failed :%s", err)
+
+		return err
+	}
+
+	data.FromResTxId = reply.TxHash
+
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseConfirmed, data.FromResTxId, "")
+
+	return nil
+}
+
+// chain returns the chain identity sent to the adapter
+func (ac *AdapterChain) chain() *adapterpb.Chain {
+	return &adapterpb.Chain{
+		ChainId: ac.ChainID,
+		Params:  ac.Config.Params,
+	}
+}
+
+// listen handles the interchain requests streamed by the adapter
+// On any stream error, the stream is reopened from the height of the last relayed request
+func (ac *AdapterChain) listen(ctx context.Context, stream adapterpb.AppChainAdapter_StartClient) {
+	for {
+		req, err := stream.Recv()
+		if err == nil {
+			ac.handleRequest(req)
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		logging.Logger.Errorf("request stream of chain %s broken: %s", ac.ChainID, err)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(ac.retryInterval()):
+			}
+
+			stream, err = ac.client.Start(ctx, &adapterpb.StartRequest{Chain: ac.chain(), StartHeight: ac.GetHeight()})
+			if err == nil {
+				logging.Logger.Infof("request stream of chain %s reopened from height %d", ac.ChainID, ac.GetHeight())
+				break
+			}
+
+			logging.Logger.Errorf("failed to reopen the request stream of chain %s: %s", ac.ChainID, err)
+		}
+	}
+}
+
+// handleRequest relays the interchain request streamed by the adapter
+func (ac *AdapterChain) handleRequest(req *adapterpb.InterchainRequest) {
+	timeout := req.Timeout
+	if timeout == 0 {
+		timeout = ac.Config.Timeout
+	}
+
+	request := core.InterchainRequest{
+		ID:              req.Id,
+		SourceChainID:   ac.ChainID,
+		DestChainID:     req.DestChainId,
+		DestSubChainID:  req.DestSubChainId,
+		DestChainType:   req.DestChainType,
+		EndpointAddress: req.EndpointAddress,
+		EndpointType:    req.EndpointType,
+		Method:          req.Method,
+		CallData:        req.CallData,
+		Sender:          req.Sender,
+		Timeout:         timeout,
+		Timestamp:       req.Timestamp,
+	}
+
+	_ = ac.handler(ac.ChainID, request, req.TxHash)
+
+	if req.Height > ac.GetHeight() {
+		if err := ac.updateHeight(req.Height); err != nil {
+			logging.Logger.Errorf("failed to update the height of chain %s: %s", ac.ChainID, err)
+		}
+	}
+}
+
+// healthCheck checks the health of the adapter periodically until the chain is stopped
+func (ac *AdapterChain) healthCheck(ctx context.Context) {
+	ticker := time.NewTicker(DefaultHealthCheckInterval * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ac.checkHealth(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkHealth checks the health of the adapter and refreshes the head height and the node connected
+func (ac *AdapterChain) checkHealth(ctx context.Context) {
+	callCtx, cancel := context.WithTimeout(ctx, DefaultCallTimeout*time.Second)
+	defer cancel()
+
+	health, err := ac.client.Health(callCtx, &adapterpb.HealthRequest{Chain: ac.chain()})
+	if err != nil {
+		logging.Logger.Warnf("failed to check the health of the adapter of chain %s: %s", ac.ChainID, err)
+		return
+	}
+
+	if !health.Healthy {
+		logging.Logger.Warnf("adapter of chain %s unhealthy: %s", ac.ChainID, health.Error)
+		return
+	}
+
+	height, err := ac.client.GetHeight(callCtx, &adapterpb.GetHeightRequest{Chain: ac.chain()})
+	if err != nil {
+		logging.Logger.Warnf("failed to get the height of chain %s: %s", ac.ChainID, err)
+		return
+	}
+
+	metrics.SetHeadHeight(ac.ChainID, height.Height)
+
+	ac.mtx.Lock()
+	ac.node = height.Node
+	ac.mtx.Unlock()
+}
+
+// retryInterval returns the interval to reopen the request stream
+func (ac *AdapterChain) retryInterval() time.Duration {
+	interval := ac.Config.MonitorInterval
+	if interval == 0 {
+		interval = DefaultMonitorInterval
+	}
+
+	return time.Duration(interval) * time.Second
+}
+
+// storeChainParams stores the chain params
+func (ac *AdapterChain) storeChainParams() error {
+	bz, err := json.Marshal(ac.Config.ChainParams)
+	if err != nil {
+		return err
+	}
+
+	bz, err = secrets.Seal(bz)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the chain params: %s", err)
+	}
+
+	return ac.store.Set(ChainParamsKey(ac.ChainType, ac.ChainID), bz)
+}
+
+func (ac *AdapterChain) storeChainID() error {
+	chainIDsbz, err := ac.store.Get([]byte("chainIDs"))
+	if err != nil {
+		return err
+	}
+	chainIDs := map[string]string{}
+	err = json.Unmarshal(chainIDsbz, &chainIDs)
+	if err != nil {
+		return err
+	}
+	chainIDs[ac.ChainID] = ac.ChainType
+	bz, _ := json.Marshal(chainIDs)
+	return ac.store.Set([]byte("chainIDs"), bz)
+}
+
+// loadStartHeight determines the height to start streaming from
+// The streaming resumes from the persisted height, since the requests already relayed
+// in the height are skipped by the relayer, unless the chain params specify a greater
+// start height or to start from the latest block
+func (ac *AdapterChain) loadStartHeight() (int64, error) {
+	var startHeight int64
+
+	if !ac.Config.FromLatest {
+		height, err := ac.store.GetInt64(HeightKey(ac.ChainType, ac.ChainID))
+		if err != nil && err != store.ErrNotFound {
+			return 0, fmt.Errorf("failed to load the height of chain %s: %s", ac.ChainID, err)
+		}
+
+		if err == nil {
+			startHeight = height
+		}
+
+		if ac.Config.StartHeight > startHeight {
+			startHeight = ac.Config.StartHeight
+		}
+
+		if startHeight > 0 {
+			ac.lastHeight = startHeight
+			return startHeight, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultCallTimeout*time.Second)
+	defer cancel()
+
+	reply, err := ac.client.GetHeight(ctx, &adapterpb.GetHeightRequest{Chain: ac.chain()})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the latest height of chain %s: %s", ac.ChainID, err)
+	}
+
+	ac.lastHeight = reply.Height
+	ac.node = reply.Node
+
+	return reply.Height, nil
+}
+
+// updateHeight updates the height
+func (ac *AdapterChain) updateHeight(height int64) error {
+	ac.mtx.Lock()
+	ac.lastHeight = height
+	ac.mtx.Unlock()
+
+	metrics.SetScannedHeight(ac.ChainID, height)
+
+	return ac.store.SetInt64(HeightKey(ac.ChainType, ac.ChainID), height)
+}
+
+// CallContract implements ContractCallerI
+func (ac *AdapterChain) CallContract(endpointAddress string, method string, callData []byte) (output string, txHash string, err error) {
+	reply, err := ac.client.CallContract(context.Background(), &adapterpb.CallContractRequest{
+		Chain:           ac.chain(),
+		EndpointAddress: endpointAddress,
+		Method:          method,
+		CallData:        callData,
+	})
+	if err != nil {
+		callErr := fmt.Errorf("failed to call %s of %s on the adapter: %s", method, endpointAddress, err)
+
+		// the adapter is unreachable or reports that the call is not executed
+		if status.Code(err) == codes.Unavailable {
+			return "", "", core.NewTransientError(callErr)
+		}
+
+		return "", "", callErr
+	}
+
+	return reply.Output, reply.TxHash, nil
+}
@@ -0,0 +1,238 @@
+package adapter
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"relayer/appchains/adapter/adapterpb"
+	"relayer/core"
+	"relayer/store"
+)
+
+// mockAdapter serves a chain with the given requests
+type mockAdapter struct {
+	requests    []*adapterpb.InterchainRequest
+	startHeight chan int64
+	responses   chan *adapterpb.SendResponseRequest
+}
+
+func (m *mockAdapter) Start(req *adapterpb.StartRequest, stream adapterpb.AppChainAdapter_StartServer) error {
+	m.startHeight <- req.StartHeight
+
+	for _, r := range m.requests {
+		if r.Height < req.StartHeight {
+			continue
+		}
+
+		if err := stream.Send(r); err != nil {
+			return err
+		}
+	}
+
+	<-stream.Context().Done()
+
+	return nil
+}
+
+func (m *mockAdapter) SendResponse(ctx context.Context, req *adapterpb.SendResponseRequest) (*adapterpb.SendResponseReply, error) {
+	m.responses <- req
+	return &adapterpb.SendResponseReply{TxHash: "0xresponse"}, nil
+}
+
+func (m *mockAdapter) GetHeight(ctx context.Context, req *adapterpb.GetHeightRequest) (*adapterpb.GetHeightReply, error) {
+	return &adapterpb.GetHeightReply{Height: 10, Node: "node1"}, nil
+}
+
+func (m *mockAdapter) Health(ctx context.Context, req *adapterpb.HealthRequest) (*adapterpb.HealthReply, error) {
+	return &adapterpb.HealthReply{Healthy: true}, nil
+}
+
+func (m *mockAdapter) CallContract(ctx context.Context, req *adapterpb.CallContractRequest) (*adapterpb.CallContractReply, error) {
+	return &adapterpb.CallContractReply{Output: req.Method + ":" + string(req.CallData), TxHash: "0xcall"}, nil
+}
+
+func TestAdapterChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "adapter-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set([]byte("chainIDs"), []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock := &mockAdapter{
+		requests: []*adapterpb.InterchainRequest{
+			{Id: "req1", DestChainId: "dest", Method: "hello", CallData: []byte("data"), TxHash: "0xtx1", Height: 5},
+			{Id: "req2", DestChainId: "dest", Method: "hello", TxHash: "0xtx2", Height: 6, Timeout: 50},
+		},
+		startHeight: make(chan int64, 1),
+		responses:   make(chan *adapterpb.SendResponseRequest, 1),
+	}
+
+	srv := grpc.NewServer()
+	adapterpb.RegisterAppChainAdapterServer(srv, mock)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	chain, err := NewAdapterChain(ChainType, Config{
+		ChainParams: ChainParams{
+			ChainID:     "besu1",
+			AdapterAddr: lis.Addr().String(),
+			Params:      []byte(`{"url":"http://127.0.0.1:8545"}`),
+			StartHeight: 5,
+			Timeout:     100,
+		},
+	}, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Close()
+
+	requests := make(chan core.InterchainRequest, 2)
+	handler := func(chainID string, request core.InterchainRequest, txHash string) error {
+		request.TxHash = txHash
+		requests <- request
+		return nil
+	}
+
+	if err := chain.Start(handler); err != nil {
+		t.Fatal(err)
+	}
+
+	if height := <-mock.startHeight; height != 5 {
+		t.Fatalf("expected to start from 5, got %d", height)
+	}
+
+	for i, expected := range []core.InterchainRequest{
+		{ID: "req1", SourceChainID: "besu1", DestChainID: "dest", Method: "hello", CallData: []byte("data"), TxHash: "0xtx1", Timeout: 100},
+		{ID: "req2", SourceChainID: "besu1", DestChainID: "dest", Method: "hello", TxHash: "0xtx2", Timeout: 50},
+	} {
+		select {
+		case request := <-requests:
+			if request.ID != expected.ID || request.SourceChainID != expected.SourceChainID || request.DestChainID != expected.DestChainID ||
+				request.Method != expected.Method || string(request.CallData) != string(expected.CallData) ||
+				request.TxHash != expected.TxHash || request.Timeout != expected.Timeout {
+				t.Fatalf("unexpected request %d: %+v", i, request)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("request %d not relayed", i)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for chain.GetHeight() != 6 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if chain.GetHeight() != 6 {
+		t.Fatalf("expected the height 6, got %d", chain.GetHeight())
+	}
+
+	if height, err := s.GetInt64(HeightKey(ChainType, "besu1")); err != nil || height != 6 {
+		t.Fatalf("expected the persisted height 6, got %d: %v", height, err)
+	}
+
+	if bz, _ := s.Get([]byte("chainIDs")); string(bz) != `{"besu1":"adapter"}` {
+		t.Fatalf("expected the chain to be registered as the adapter type, got %s", bz)
+	}
+
+	err = chain.SendResponse("req1", core.ResponseAdaptor{StatusCode: 200, Output: "world"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := <-mock.responses
+	if response.Chain.ChainId != "besu1" || string(response.Chain.Params) != `{"url":"http://127.0.0.1:8545"}` ||
+		response.RequestId != "req1" || response.Output != "world" || response.ErrMsg != "" {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+
+	output, txHash, err := chain.CallContract("0xcontract", "hello", []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "hello:data" || txHash != "0xcall" {
+		t.Fatalf("unexpected contract call result: %s, %s", output, txHash)
+	}
+
+	if err := chain.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.Start(handler); err != nil {
+		t.Fatal(err)
+	}
+	if height := <-mock.startHeight; height != 6 {
+		t.Fatalf("expected to resume from 6, got %d", height)
+	}
+
+	if err := chain.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdapterChainType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "adapter-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set([]byte("chainIDs"), []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := grpc.NewServer()
+	adapterpb.RegisterAppChainAdapterServer(srv, &mockAdapter{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	// the chain served by an adapter is registered as its own type
+	chain, err := NewAdapterChain("opb", Config{
+		ChainParams: ChainParams{ChainID: "opb1", AdapterAddr: lis.Addr().String()},
+	}, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Close()
+
+	if bz, _ := s.Get([]byte("chainIDs")); string(bz) != `{"opb1":"opb"}` {
+		t.Fatalf("expected the chain to be registered as the opb type, got %s", bz)
+	}
+
+	if _, err := s.Get(ChainParamsKey("opb", "opb1")); err != nil {
+		t.Fatalf("expected the params to be stored under the opb type: %v", err)
+	}
+
+	if err := chain.updateHeight(3); err != nil {
+		t.Fatal(err)
+	}
+	if height, err := s.GetInt64([]byte("opb:height:opb1")); err != nil || height != 3 {
+		t.Fatalf("expected the height to be stored under the opb type, got %d: %v", height, err)
+	}
+}
@@ -0,0 +1,52 @@
+package adapter
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/viper"
+
+	cfg "relayer/config"
+)
+
+const (
+	MonitorInterval = "monitor_interval"
+	DialTimeout     = "dial_timeout"
+	CAFile          = "ca_file"
+	CertFile        = "cert_file"
+	KeyFile         = "key_file"
+)
+
+// BaseConfig defines the base config
+type BaseConfig struct {
+	MonitorInterval uint64 `yaml:"monitor_interval"` // interval in seconds to reopen the request stream
+	DialTimeout     uint64 `yaml:"dial_timeout"`     // timeout in seconds to connect to the adapter
+	CAFile          string `yaml:"ca_file"`          // CA to verify the adapter, plaintext if empty
+	CertFile        string `yaml:"cert_file"`        // client certificate for the mutual TLS
+	KeyFile         string `yaml:"key_file"`         // client key for the mutual TLS
+}
+
+func (bc *BaseConfig) PrintConfig() {
+}
+
+// Config defines the specific chain config
+type Config struct {
+	BaseConfig
+	ChainParams
+}
+
+// NewBaseConfig constructs a new BaseConfig instance from the config section of the given chain type
+func NewBaseConfig(v *viper.Viper, chainType string) *BaseConfig {
+	return &BaseConfig{
+		MonitorInterval: v.GetUint64(cfg.GetConfigKey(chainType, MonitorInterval)),
+		DialTimeout:     v.GetUint64(cfg.GetConfigKey(chainType, DialTimeout)),
+		CAFile:          v.GetString(cfg.GetConfigKey(chainType, CAFile)),
+		CertFile:        v.GetString(cfg.GetConfigKey(chainType, CertFile)),
+		KeyFile:         v.GetString(cfg.GetConfigKey(chainType, KeyFile)),
+	}
+}
+
+// ValidateBaseConfig validates if the given bytes is valid BaseConfig
+func ValidateBaseConfig(baseCfg []byte) error {
+	var baseConfig BaseConfig
+	return json.Unmarshal(baseCfg, &baseConfig)
+}
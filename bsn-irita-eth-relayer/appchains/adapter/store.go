@@ -0,0 +1,46 @@
+package adapter
+
+import (
+	"fmt"
+
+	"relayer/secrets"
+	"relayer/store"
+)
+
+// The keys are prefixed by the chain type the chains are registered as, which is
+// the adapter type or a type served by an adapter, e.g. opb
+const (
+	KeyBaseConfig        = "baseconfig"
+	KeyPrefixChainParams = "params"
+	KeyPrefixHeight      = "height"
+)
+
+// BaseConfigKey returns the key for the base config of the given chain type
+func BaseConfigKey(chainType string) []byte {
+	return []byte(fmt.Sprintf("%s:%s", chainType, KeyBaseConfig))
+}
+
+// ChainParamsKey returns the key for the params of the given chain
+func ChainParamsKey(chainType string, chainID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s", chainType, KeyPrefixChainParams, chainID))
+}
+
+// HeightKey returns the key for the height of the specified chain
+func HeightKey(chainType string, chainID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s", chainType, KeyPrefixHeight, chainID))
+}
+
+// StoreBaseConfig stores the base config of the given chain type
+func StoreBaseConfig(store *store.Store, chainType string, baseConfig []byte) error {
+	err := ValidateBaseConfig(baseConfig)
+	if err != nil {
+		return err
+	}
+
+	bz, err := secrets.Seal(baseConfig)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the base config: %s", err)
+	}
+
+	return store.Set(BaseConfigKey(chainType), bz)
+}
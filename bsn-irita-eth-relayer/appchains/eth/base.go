@@ -6,7 +6,8 @@ import (
 
 const (
 	ChainType              = "eth"
-	DefaultMonitorInterval = 1 // 1 second by default
+	DefaultMonitorInterval = 1    // 1 second by default
+	DefaultFilterRange     = 1000 // maximum number of blocks queried by FilterLogs at a time
 )
 
 // CompactBlock represents the compact block with tx hashes
@@ -19,6 +20,26 @@ type ChainParams struct {
 	NodeURLs         []string `json:"nodes"`
 	ChainID          string    `json:"chainId"`
 	IServiceCoreAddr string   `json:"iserviceCoreAddr"`
+	StartHeight      int64    `json:"startHeight,omitempty"`   // height to start scanning from when it is beyond the persisted height
+	FromLatest       bool     `json:"fromLatest,omitempty"`    // whether to skip the missed blocks and start from the latest block
+	Confirmations    uint64   `json:"confirmations,omitempty"` // number of blocks a log must be buried under before it is relayed
+	Timeout          int64    `json:"timeout,omitempty"`       // service timeout in blocks on the Hub for the requests of the chain
+	Signer           string   `json:"signer,omitempty"`        // name of the configured signer, the key in the base config is used if empty
+}
+
+// LogCursor defines the position of the last processed log
+type LogCursor struct {
+	Height uint64 `json:"height"` // block number of the last processed log
+	Index  int64  `json:"index"`  // log index in the block, -1 if the whole block is processed
+}
+
+// After returns true if the given log is beyond the cursor
+func (c LogCursor) After(height uint64, index uint) bool {
+	if c.Index < 0 {
+		return height > c.Height
+	}
+
+	return height > c.Height || (height == c.Height && int64(index) > c.Index)
 }
 
 type EndpointInfo struct {
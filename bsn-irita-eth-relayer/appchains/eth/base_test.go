@@ -0,0 +1,167 @@
+package eth
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"relayer/appchains/eth/iservice"
+	"relayer/core"
+	"relayer/store"
+)
+
+func TestLogCursorAfter(t *testing.T) {
+	cursor := LogCursor{Height: 100, Index: 2}
+
+	testCases := []struct {
+		height   uint64
+		index    uint
+		expected bool
+	}{
+		{99, 5, false},
+		{100, 1, false},
+		{100, 2, false},
+		{100, 3, true},
+		{101, 0, true},
+	}
+
+	for _, tc := range testCases {
+		if cursor.After(tc.height, tc.index) != tc.expected {
+			t.Fatalf("log (%d, %d): expected %v", tc.height, tc.index, tc.expected)
+		}
+	}
+
+	cursor = LogCursor{Height: 100, Index: -1}
+	if cursor.After(100, 0) {
+		t.Fatal("expected the logs of the processed height not to be after the cursor")
+	}
+	if !cursor.After(101, 0) {
+		t.Fatal("expected the first log of the next height to be after the cursor")
+	}
+}
+
+func TestLogCursorConcurrentAccess(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relayer-eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ec := &EthChain{ChainID: "eth1", store: s}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		// the log listener advances the cursor and the confirmed height
+		for i := uint64(1); i <= 100; i++ {
+			ec.updateLogCursor(LogCursor{Height: i, Index: -1})
+			ec.releaseConfirmed(i)
+		}
+	}()
+
+	// the HTTP handlers read the height meanwhile
+	for i := 0; i < 100; i++ {
+		_ = ec.GetHeight()
+		_ = ec.logCursor()
+	}
+
+	<-done
+
+	if ec.GetHeight() != 100 || ec.logCursor().Height != 100 {
+		t.Fatalf("unexpected height %d and cursor %+v", ec.GetHeight(), ec.logCursor())
+	}
+}
+
+func TestReleaseConfirmedRetriesFailedLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relayer-eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := newTestNode(10)
+	defer node.Close()
+
+	nodes, err := NewNodeClient("eth1", []string{node.URL}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nodes.Close()
+
+	iServiceCoreABI, err := abi.JSON(strings.NewReader(iservice.IServiceCoreExABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := iServiceCoreABI.Events["CrossChainRequestSent"].Inputs.Pack(
+		[32]byte{1}, `{"dest_chain_id":"fisco"}`, "hello", []byte("data"), ethcmn.Address{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failing := true
+	handled := 0
+
+	ec := &EthChain{ChainID: "eth1", store: s, nodes: nodes, IServiceCoreABI: iServiceCoreABI}
+	ec.Config.IServiceEventName = "CrossChainRequestSent"
+	ec.handler = func(chainID string, request core.InterchainRequest, txHash string) error {
+		if failing {
+			return fmt.Errorf("queue unavailable")
+		}
+
+		handled++
+
+		return nil
+	}
+
+	ec.bufferLog(ethtypes.Log{BlockNumber: 10, Index: 1, Data: data})
+	ec.releaseConfirmed(10)
+
+	if ec.pending.Len() != 1 || ec.logCursor() != (LogCursor{}) || ec.GetHeight() != 0 {
+		t.Fatalf("expected the failed log to be retried, got cursor %+v and %d pending", ec.logCursor(), ec.pending.Len())
+	}
+
+	failing = false
+	ec.releaseConfirmed(10)
+
+	if handled != 1 || ec.pending.Len() != 0 || ec.logCursor() != (LogCursor{Height: 10, Index: 1}) || ec.GetHeight() != 10 {
+		t.Fatalf("expected the log to be handled, got cursor %+v and %d handled", ec.logCursor(), handled)
+	}
+}
+
+func TestStopConcurrently(t *testing.T) {
+	ec := &EthChain{ChainID: "eth1", stop: make(chan struct{})}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = ec.Stop()
+		}()
+	}
+	wg.Wait()
+
+	if !ec.done || !ec.isStopped(ec.stop) {
+		t.Fatal("expected the chain to be stopped once")
+	}
+}
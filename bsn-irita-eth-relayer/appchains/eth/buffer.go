@@ -0,0 +1,77 @@
+package eth
+
+import (
+	"sort"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// logBuffer holds the logs waiting for enough confirmations
+type logBuffer struct {
+	logs []ethtypes.Log
+}
+
+// Add adds the given log to the buffer
+// False is returned if the log is already buffered
+func (b *logBuffer) Add(log ethtypes.Log) bool {
+	for _, l := range b.logs {
+		if sameLog(l, log) {
+			return false
+		}
+	}
+
+	b.logs = append(b.logs, log)
+
+	return true
+}
+
+// Remove removes the given log from the buffer
+// False is returned if the log is not buffered
+func (b *logBuffer) Remove(log ethtypes.Log) bool {
+	for i, l := range b.logs {
+		if sameLog(l, log) {
+			b.logs = append(b.logs[:i], b.logs[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// PopConfirmed removes and returns the logs not higher than the given height in order
+func (b *logBuffer) PopConfirmed(height uint64) []ethtypes.Log {
+	sort.SliceStable(b.logs, func(i, j int) bool {
+		if b.logs[i].BlockNumber != b.logs[j].BlockNumber {
+			return b.logs[i].BlockNumber < b.logs[j].BlockNumber
+		}
+
+		return b.logs[i].Index < b.logs[j].Index
+	})
+
+	n := 0
+	for n < len(b.logs) && b.logs[n].BlockNumber <= height {
+		n++
+	}
+
+	confirmed := make([]ethtypes.Log, n)
+	copy(confirmed, b.logs[:n])
+
+	b.logs = b.logs[n:]
+
+	return confirmed
+}
+
+// Len returns the number of the buffered logs
+func (b *logBuffer) Len() int {
+	return len(b.logs)
+}
+
+// Reset clears the buffer
+func (b *logBuffer) Reset() {
+	b.logs = nil
+}
+
+// sameLog returns true if the given logs are emitted by the same tx at the same position of the same block
+func sameLog(a, b ethtypes.Log) bool {
+	return a.BlockHash == b.BlockHash && a.TxHash == b.TxHash && a.Index == b.Index
+}
@@ -0,0 +1,45 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestLogBuffer(t *testing.T) {
+	var b logBuffer
+
+	log1 := ethtypes.Log{BlockNumber: 10, BlockHash: common.HexToHash("0x0a"), Index: 1}
+	log2 := ethtypes.Log{BlockNumber: 10, BlockHash: common.HexToHash("0x0a"), Index: 0}
+	log3 := ethtypes.Log{BlockNumber: 12, BlockHash: common.HexToHash("0x0c"), Index: 0}
+
+	for _, log := range []ethtypes.Log{log3, log1, log2} {
+		if !b.Add(log) {
+			t.Fatalf("expected the log to be added: %v", log)
+		}
+	}
+	if b.Add(log1) {
+		t.Fatal("expected the duplicate log to be ignored")
+	}
+
+	confirmed := b.PopConfirmed(11)
+	if len(confirmed) != 2 || confirmed[0].Index != 0 || confirmed[1].Index != 1 {
+		t.Fatalf("unexpected confirmed logs: %v", confirmed)
+	}
+	if b.Len() != 1 {
+		t.Fatalf("expected 1 pending log, got %d", b.Len())
+	}
+
+	removed := log3
+	removed.Removed = true
+	if !b.Remove(removed) {
+		t.Fatal("expected the removed log to be cancelled")
+	}
+	if b.Remove(removed) {
+		t.Fatal("expected the log to be absent")
+	}
+	if len(b.PopConfirmed(100)) != 0 {
+		t.Fatal("expected no confirmed logs")
+	}
+}
@@ -7,34 +7,44 @@ import (
 	"fmt"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"math/big"
 	"strings"
+	"sync"
 	"time"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
 
 	"relayer/appchains/eth/iservice"
-	txstore "relayer/appchains/eth/store"
+	txstore "relayer/appchains/store"
 	"relayer/core"
 	"relayer/logging"
+	"relayer/metrics"
+	"relayer/secrets"
+	"relayer/signer"
 	"relayer/store"
 )
 
 // EthChain defines the Eth chain
 type EthChain struct {
 	Config  Config
-	Client  *ethclient.Client
 	ChainID string // unique chain ID
 
-	IServiceCoreContract *iservice.IServiceCoreEx // iService Core Extension contract
-	IServiceCoreABI      abi.ABI                  // parsed iService Core Extension ABI
-
-	store              *store.Store // store backend instance
-	done    bool
+	IServiceCoreABI abi.ABI // parsed iService Core Extension ABI
+
+	nodes              *NodeClient    // client of the active node
+	store              *store.Store   // store backend instance
+	txManager          *TxManager     // manager of the txs sent to the chain
+	cursor             LogCursor      // position of the last processed log
+	lastHeight         uint64         // last height whose logs are confirmed
+	mtx                sync.RWMutex   // guards the cursor and last height read outside the log listener
+	pending            logBuffer      // logs waiting for confirmations
+	stateMtx           sync.Mutex     // guards the started state
+	listener           sync.WaitGroup // tracks the running log listener
+	done               bool
+	stop               chan struct{}                 // closed to stop the log listener
+	handler            core.InterchainRequestHandler // handler for the interchain request
 	ClientSubscription ethereum.Subscription
 }
 
@@ -44,16 +54,12 @@ func NewEthChain(
 	store *store.Store,
 ) (*EthChain, error) {
 
-	nodeName := randURL(config.NodeURLs)
-	//获取配置的nodeURL
-	nodeUrl, ok := config.NodesMap[nodeName]
-	if ok {
-		nodeName = nodeUrl
-	}
+	chainID := GetChainID(config.ChainParams)
 
-	client, err := ethclient.Dial(nodeUrl)
+	// connect to the first available node and fail over among the nodes
+	nodes, err := NewNodeClient(chainID, config.NodeURLs, config.NodesMap)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to eth node: %s", err)
+		return nil, err
 	}
 
 	iServiceCoreABI, err := abi.JSON(strings.NewReader(iservice.IServiceCoreExABI))
@@ -61,20 +67,21 @@ func NewEthChain(
 		return nil, fmt.Errorf("failed to parse iService Core Extension ABI: %s", err)
 	}
 
-	iServiceCore, err := iservice.NewIServiceCoreEx(ethcmn.HexToAddress(config.IServiceCoreAddr), client)
+	keySigner, err := loadSigner(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to instantiate the iService Core Extension contract: %s", err)
+		return nil, err
 	}
 
-	chainID := GetChainID(config.ChainParams)
+	txManager := NewTxManager(chainID, config.BaseConfig, nodes, keySigner, store)
 
 	eth := &EthChain{
-		Config:               config,
-		Client:               client,
-		ChainID:              chainID,
-		IServiceCoreContract: iServiceCore,
-		IServiceCoreABI:      iServiceCoreABI,
-		store:                store,
+		Config:          config,
+		ChainID:         chainID,
+		IServiceCoreABI: iServiceCoreABI,
+		nodes:           nodes,
+		store:           store,
+		txManager:       txManager,
+		done:            true,
 	}
 
 	err = eth.storeChainParams()
@@ -87,16 +94,36 @@ func NewEthChain(
 		return nil, err
 	}
 
+	err = txManager.Recover()
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover the pending txs: %s", err)
+	}
+
 	return eth, nil
 }
 
+// loadSigner loads the signer selected by the chain params
+// The key in the base config is used if no signer is selected
+func loadSigner(config Config) (signer.Signer, error) {
+	if len(config.ChainParams.Signer) == 0 {
+		return signer.NewHexKeySigner(config.Key)
+	}
+
+	return signer.Select(config.Signers, config.ChainParams.Signer)
+}
+
 // BuildEthChain builds a EthChain instance from the given chain params and store
 func BuildEthChain(
 	chainParams []byte,
 	store *store.Store,
 ) (*EthChain, error) {
+	chainParams, err := secrets.Open(chainParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the chain params: %s", err)
+	}
+
 	var params ChainParams
-	err := json.Unmarshal(chainParams, &params)
+	err = json.Unmarshal(chainParams, &params)
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +133,11 @@ func BuildEthChain(
 		return nil, err
 	}
 
+	baseCfgBz, err = secrets.Open(baseCfgBz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the base config: %s", err)
+	}
+
 	var baseConfig BaseConfig
 	err = json.Unmarshal(baseCfgBz, &baseConfig)
 	if err != nil {
@@ -127,44 +159,57 @@ func (ec *EthChain) GetChainID() string {
 
 // Start implements AppChainI
 func (ec *EthChain) Start(handler core.InterchainRequestHandler) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	ec.stateMtx.Lock()
+	defer ec.stateMtx.Unlock()
 
-	filterQuery := ethereum.FilterQuery{
-		Addresses: []ethcmn.Address{ethcmn.HexToAddress(ec.Config.IServiceCoreAddr)},
-		Topics:    [][]ethcmn.Hash{{crypto.Keccak256Hash([]byte(ec.Config.IServiceEventSig))}},
+	if !ec.done {
+		return fmt.Errorf("chain %s has been started", ec.ChainID)
 	}
 
-	ch := make(chan ethtypes.Log)
+	// the listener of the previous run must exit before the pending logs are reset
+	ec.listener.Wait()
+	ec.pending.Reset()
 
-	sub, err := ec.Client.SubscribeFilterLogs(ctx, filterQuery, ch)
+	if ec.logCursor() == (LogCursor{}) {
+		err := ec.loadLogCursor()
+		if err != nil {
+			return err
+		}
+	}
+
+	node := ec.nodes.ActiveNode()
+
+	sub, ch, err := ec.subscribe()
 	if err != nil {
+		ec.failover(node, err)
 		return err
 	}
 
-	ec.ClientSubscription = sub
 	ec.done = false
+	ec.handler = handler
+	ec.stop = make(chan struct{})
 
-	logHandler := func(log ethtypes.Log) {
-		iServiceRequestEvent, err := ec.parseLog(log)
-		if err != nil {
-			logging.Logger.Errorf("failed to parse log %+v: %s", log, err)
-		} else {
-			request := ec.buildInterchainRequest(&iServiceRequestEvent)
-			handler(ec.ChainID, request, log.TxHash.String())
-		}
-	}
+	ec.listener.Add(1)
+	go func(stop chan struct{}) {
+		defer ec.listener.Done()
+		ec.logListener(node, sub, ch, stop)
+	}(ec.stop)
+
+	go ec.healthCheck(ec.stop)
 
-	go ec.logListener(sub, ch, logHandler)
+	logging.Logger.Infof("chain %s started", ec.ChainID)
 
 	return nil
 }
 
 // Stop implements AppChainI
 func (ec *EthChain) Stop() error {
+	ec.stateMtx.Lock()
+	defer ec.stateMtx.Unlock()
+
 	logging.Logger.Infof("stopping chain %s", ec.ChainID)
-	if ec.ClientSubscription != nil{
-		ec.ClientSubscription.Unsubscribe()
+	if !ec.done {
+		close(ec.stop)
 	}
 	ec.done = true
 
@@ -172,20 +217,24 @@ func (ec *EthChain) Stop() error {
 }
 
 func (ec *EthChain) Close() {
-	ec.Client.Close()
+	ec.nodes.Close()
+}
+
+// GetActiveNode implements AppChainI
+func (ec *EthChain) GetActiveNode() string {
+	return ec.nodes.ActiveNode()
 }
 
 // GetHeight implements AppChainI
 func (ec *EthChain) GetHeight() int64 {
-	return int64(1)
+	ec.mtx.RLock()
+	defer ec.mtx.RUnlock()
+
+	return int64(ec.lastHeight)
 }
 
 // SendResponse implements AppChainI
 func (ec *EthChain) SendResponse(requestID string, response core.ResponseI) error {
-	auth, err := ec.buildAuthTransactor()
-	if err != nil {
-		return err
-	}
 	requestIDBytes, err := hex.DecodeString(requestID)
 	if err != nil {
 		return err
@@ -204,7 +253,12 @@ func (ec *EthChain) SendResponse(requestID string, response core.ResponseI) erro
 	var requestID32Bytes [32]byte
 	copy(requestID32Bytes[:], requestIDBytes)
 
-	tx, err := ec.IServiceCoreContract.SetResponse(auth, requestID32Bytes, response.GetErrMsg(), response.GetOutput())
+	callData, err := ec.IServiceCoreABI.Pack("setResponse", requestID32Bytes, response.GetErrMsg(), response.GetOutput())
+	if err != nil {
+		return err
+	}
+
+	ptx, err := ec.txManager.Send(ethcmn.HexToAddress(ec.Config.IServiceCoreAddr), callData)
 	if err != nil {
 		data.TxStatus = txstore.TxStatus_Error
 		data.ErrMsg = fmt.Sprintf("call eth setResponse failed :%s", err)
@@ -212,12 +266,15 @@ func (ec *EthChain) SendResponse(requestID string, response core.ResponseI) erro
 		return err
 	}
 
-	data.FromResTxId = tx.Hash().Hex()
+	data.FromResTxId = ptx.Hash()
+
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseSent, data.FromResTxId, "")
 
-	// TODO
-	//mysql.OnInterchainRequestResponseSent(requestID, tx.Hash().Hex())
+	receipt, err := ec.txManager.Wait(ptx)
+	if receipt != nil {
+		data.FromResTxId = receipt.TxHash.Hex()
+	}
 
-	err = ec.waitForReceipt(tx, "SetResponse")
 	if err != nil {
 
 		data.TxStatus = txstore.TxStatus_Error
@@ -225,8 +282,7 @@ func (ec *EthChain) SendResponse(requestID string, response core.ResponseI) erro
 		return err
 	}
 
-	// TODO
-	//mysql.OnInterchainRequestSucceeded(requestID)
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseConfirmed, data.FromResTxId, "")
 
 	return nil
 }
@@ -249,48 +305,342 @@ func (ec *EthChain) buildInterchainRequest(e *iservice.IServiceCoreExCrossChainR
 		Method:          e.Method,
 		CallData:        e.CallData,
 		Sender:          e.Sender.String(),
+		Timeout:         ec.Config.Timeout,
 	}
 }
 
-// waitForReceipt waits for the receipt of the given tx
-func (ec *EthChain) waitForReceipt(tx *ethtypes.Transaction, name string) error {
-	logging.Logger.Infof("%s: transaction sent to %s, hash: %s", name, ec.GetChainID(), tx.Hash().Hex())
+// getBlock gets the block in the given height
+func (ec *EthChain) getBlock(height int64) (block *ethtypes.Block, err error) {
+	return ec.nodes.Client().BlockByNumber(context.Background(), big.NewInt(height))
+}
+
+// logListener backfills the missed logs and then listens to the logs sent by the subscription
+// On any subscription error, it fails over to another node, resubscribes and backfills again from the log cursor
+// The logs buffered for confirmation are kept across the resubscriptions
+func (ec *EthChain) logListener(node string, sub ethereum.Subscription, logChan chan ethtypes.Log, stop chan struct{}) {
+	for {
+		err := ec.backfill(stop)
+		if err == nil {
+			err = ec.listen(sub, logChan, stop)
+		}
 
-	receipt, err := bind.WaitMined(context.Background(), ec.Client, tx)
+		sub.Unsubscribe()
+
+		if ec.isStopped(stop) {
+			return
+		}
+
+		logging.Logger.Errorf("Error on log subscription: %s", err)
+
+		ec.failover(node, err)
+
+		for {
+			time.Sleep(ec.retryInterval())
+
+			if ec.isStopped(stop) {
+				return
+			}
+
+			node = ec.nodes.ActiveNode()
+
+			sub, logChan, err = ec.subscribe()
+			if err == nil {
+				break
+			}
+
+			logging.Logger.Errorf("failed to resubscribe logs on %s: %s", ec.ChainID, err)
+
+			ec.failover(node, err)
+		}
+	}
+}
+
+// listen handles the logs sent by the subscription until it fails or the listener is stopped
+// The buffered logs are dispatched periodically once they are confirmed
+func (ec *EthChain) listen(sub ethereum.Subscription, logChan chan ethtypes.Log, stop chan struct{}) error {
+	ticker := time.NewTicker(ec.retryInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case log := <-logChan:
+			ec.bufferLog(log)
+
+			if ec.Config.Confirmations == 0 {
+				ec.releaseConfirmed(log.BlockNumber)
+			}
+		case <-ticker.C:
+			latest, err := ec.getLatestHeight()
+			if err != nil {
+				logging.Logger.Errorf("failed to release the confirmed logs on %s: %s", ec.ChainID, err)
+				continue
+			}
+
+			ec.releaseConfirmed(latest)
+		case err := <-sub.Err():
+			if err == nil {
+				err = fmt.Errorf("subscription closed")
+			}
+			return err
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// backfill retrieves the logs from the log cursor to the latest block in bounded ranges
+// The logs are buffered and only the confirmed ones are dispatched
+func (ec *EthChain) backfill(stop chan struct{}) error {
+	latest, err := ec.getLatestHeight()
 	if err != nil {
-		return fmt.Errorf("failed to mint the transaction %s: %s", tx.Hash().Hex(), err)
+		return err
+	}
+
+	cursor := ec.logCursor()
+
+	from := cursor.Height
+	if cursor.Index < 0 {
+		from++
 	}
 
-	if receipt.Status == ethtypes.ReceiptStatusFailed {
-		return fmt.Errorf("%s: transaction %s execution failed", name, tx.Hash().Hex())
+	filterRange := ec.Config.FilterRange
+	if filterRange == 0 {
+		filterRange = DefaultFilterRange
 	}
 
-	logging.Logger.Infof("%s: transaction %s execution succeeded", name, tx.Hash().Hex())
+	for from <= latest {
+		if ec.isStopped(stop) {
+			return nil
+		}
+
+		to := from + filterRange - 1
+		if to > latest {
+			to = latest
+		}
+
+		logging.Logger.Infof("backfilling logs on %s from %d to %d", ec.ChainID, from, to)
+
+		filterQuery := ec.buildFilterQuery()
+		filterQuery.FromBlock = new(big.Int).SetUint64(from)
+		filterQuery.ToBlock = new(big.Int).SetUint64(to)
+
+		logs, err := ec.nodes.Client().FilterLogs(context.Background(), filterQuery)
+		if err != nil {
+			return fmt.Errorf("failed to filter logs from %d to %d: %s", from, to, err)
+		}
+
+		for _, log := range logs {
+			ec.bufferLog(log)
+		}
+
+		ec.releaseConfirmed(latest)
+
+		if int64(to) <= ec.GetHeight() && ec.pending.Len() == 0 {
+			ec.updateLogCursor(LogCursor{Height: to, Index: -1})
+		}
+
+		from = to + 1
+	}
 
 	return nil
 }
 
-// getBlock gets the block in the given height
-func (ec *EthChain) getBlock(height int64) (block *ethtypes.Block, err error) {
-	return ec.Client.BlockByNumber(context.Background(), big.NewInt(height))
+// bufferLog buffers the given log until it is confirmed
+// The pending log is cancelled if it is reported to be removed due to the chain reorganization
+func (ec *EthChain) bufferLog(log ethtypes.Log) {
+	if log.Removed {
+		if ec.pending.Remove(log) {
+			logging.Logger.Warnf("pending request on %s cancelled since the log is removed, tx: %s", ec.ChainID, log.TxHash.Hex())
+		} else if !ec.logCursor().After(log.BlockNumber, log.Index) {
+			logging.Logger.Errorf("request on %s already relayed but the log is removed, tx: %s", ec.ChainID, log.TxHash.Hex())
+		}
+
+		return
+	}
+
+	if !ec.logCursor().After(log.BlockNumber, log.Index) {
+		return
+	}
+
+	ec.pending.Add(log)
 }
 
-// logListener listens to the log sent by the given channel and handles it with the specified handler
-func (ec EthChain) logListener(sub ethereum.Subscription, logChan chan ethtypes.Log, handler func(log ethtypes.Log)) {
+// releaseConfirmed dispatches the buffered logs which are confirmed at the given latest height
+func (ec *EthChain) releaseConfirmed(latest uint64) {
+	confirmations := ec.Config.Confirmations
+	if latest < confirmations {
+		return
+	}
+
+	confirmedHeight := latest - confirmations
+	confirmed := ec.pending.PopConfirmed(confirmedHeight)
+
+	for i, log := range confirmed {
+		if confirmations > 0 {
+			canonical, err := ec.isCanonical(log)
+			if err != nil {
+				logging.Logger.Errorf("failed to check the block of the log on %s: %s", ec.ChainID, err)
+
+				for _, l := range confirmed[i:] {
+					ec.pending.Add(l)
+				}
+
+				return
+			}
+
+			if !canonical {
+				logging.Logger.Warnf("pending request on %s cancelled since the block %s is reorganized, tx: %s", ec.ChainID, log.BlockHash.Hex(), log.TxHash.Hex())
+				continue
+			}
+		}
+
+		if err := ec.handleLog(log); err != nil {
+			logging.Logger.Errorf("failed to handle the log on %s, retrying: %s", ec.ChainID, err)
+
+			// the log and the following ones are retried without advancing the cursor
+			for _, l := range confirmed[i:] {
+				ec.pending.Add(l)
+			}
+
+			return
+		}
+	}
+
+	ec.mtx.Lock()
+	advanced := confirmedHeight > ec.lastHeight
+	if advanced {
+		ec.lastHeight = confirmedHeight
+	}
+	ec.mtx.Unlock()
+
+	if advanced {
+		metrics.SetScannedHeight(ec.ChainID, int64(confirmedHeight))
+	}
+}
+
+// isCanonical returns true if the block of the given log is in the canonical chain
+func (ec *EthChain) isCanonical(log ethtypes.Log) (bool, error) {
+	header, err := ec.nodes.Client().HeaderByNumber(context.Background(), new(big.Int).SetUint64(log.BlockNumber))
+	if err != nil {
+		return false, err
+	}
+
+	return header.Hash() == log.BlockHash, nil
+}
+
+// handleLog handles the given log if it has not been processed
+// The log cursor is not advanced if the request fails to be handled, so that the log is retried
+func (ec *EthChain) handleLog(log ethtypes.Log) error {
+	if !ec.logCursor().After(log.BlockNumber, log.Index) {
+		return nil
+	}
+
+	iServiceRequestEvent, err := ec.parseLog(log)
+	if err != nil {
+		logging.Logger.Errorf("failed to parse log %+v: %s", log, err)
+	} else {
+		request := ec.buildInterchainRequest(&iServiceRequestEvent)
+		request.Timestamp = ec.blockTime(log)
+
+		if err := ec.handler(ec.ChainID, request, log.TxHash.String()); err != nil {
+			return fmt.Errorf("failed to handle the request of tx %s: %s", log.TxHash.Hex(), err)
+		}
+	}
+
+	ec.updateLogCursor(LogCursor{Height: log.BlockNumber, Index: int64(log.Index)})
+
+	return nil
+}
+
+// blockTime returns the unix time in milliseconds of the block of the given log, or 0 if unknown
+func (ec *EthChain) blockTime(log ethtypes.Log) int64 {
+	header, err := ec.nodes.Client().HeaderByHash(context.Background(), log.BlockHash)
+	if err != nil {
+		return 0
+	}
+
+	return int64(header.Time) * 1000
+}
+
+// subscribe subscribes to the interchain request logs
+func (ec *EthChain) subscribe() (ethereum.Subscription, chan ethtypes.Log, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	ch := make(chan ethtypes.Log)
+
+	sub, err := ec.nodes.Client().SubscribeFilterLogs(ctx, ec.buildFilterQuery(), ch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ec.ClientSubscription = sub
+
+	return sub, ch, nil
+}
+
+// buildFilterQuery builds the filter query for the interchain request logs
+func (ec *EthChain) buildFilterQuery() ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		Addresses: []ethcmn.Address{ethcmn.HexToAddress(ec.Config.IServiceCoreAddr)},
+		Topics:    [][]ethcmn.Hash{{crypto.Keccak256Hash([]byte(ec.Config.IServiceEventSig))}},
+	}
+}
+
+// getLatestHeight retrieves the latest block number
+func (ec *EthChain) getLatestHeight() (uint64, error) {
+	header, err := ec.nodes.Client().HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the latest block: %s", err)
+	}
+
+	metrics.SetHeadHeight(ec.ChainID, header.Number.Int64())
+
+	return header.Number.Uint64(), nil
+}
+
+// failover switches to another node if the given node is still active
+func (ec *EthChain) failover(node string, cause error) {
+	if err := ec.nodes.Failover(node, cause); err != nil {
+		logging.Logger.Errorf("failed to fail over on %s: %s", ec.ChainID, err)
+	}
+}
+
+// healthCheck checks the health of the nodes periodically until the chain is stopped
+func (ec *EthChain) healthCheck(stop chan struct{}) {
+	ticker := time.NewTicker(DefaultHealthCheckInterval * time.Second)
+	defer ticker.Stop()
+
 	for {
 		select {
-		case log := <-logChan:
-			handler(log)
-		case err := <-sub.Err():
-			logging.Logger.Errorf("Error on log subscription: %s", err)
-		}
-		if ec.done {
+		case <-ticker.C:
+			ec.nodes.CheckHealth()
+		case <-stop:
 			return
 		}
-		time.Sleep(time.Duration(ec.Config.MonitorInterval) * time.Second)
 	}
 }
 
+// isStopped returns true if the given stop channel is closed
+func (ec *EthChain) isStopped(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryInterval returns the interval to retry the subscription
+func (ec *EthChain) retryInterval() time.Duration {
+	interval := ec.Config.MonitorInterval
+	if interval == 0 {
+		interval = DefaultMonitorInterval
+	}
+
+	return time.Duration(interval) * time.Second
+}
+
 // parseServiceInvokedEvents parses the ServiceInvoked events from the receipt
 func (ec *EthChain) parseLog(log ethtypes.Log) (iservice.IServiceCoreExCrossChainRequestSent, error) {
 	var event iservice.IServiceCoreExCrossChainRequestSent
@@ -308,6 +658,11 @@ func (ec *EthChain) storeChainParams() error {
 		return err
 	}
 
+	bz, err = secrets.Seal(bz)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the chain params: %s", err)
+	}
+
 	return ec.store.Set(ChainParamsKey(ec.ChainID), bz)
 }
 
@@ -326,23 +681,78 @@ func (ec *EthChain) storeChainID() error {
 	return ec.store.Set([]byte("chainIDs"), bz)
 }
 
-// buildAuthTransactor builds an authenticated transactor
-func (ec *EthChain) buildAuthTransactor() (*bind.TransactOpts, error) {
-	privKey, err := crypto.HexToECDSA(ec.Config.Key)
+// loadLogCursor determines the log cursor to start from
+// The processing resumes from the persisted cursor unless the chain params specify
+// a greater start height or to start from the latest block
+func (ec *EthChain) loadLogCursor() error {
+	if !ec.Config.FromLatest {
+		bz, err := ec.store.Get(LogCursorKey(ec.ChainID))
+		if err != nil && err != store.ErrNotFound {
+			return fmt.Errorf("failed to load the log cursor of chain %s: %s", ec.ChainID, err)
+		}
+
+		var cursor LogCursor
+
+		persisted := err == nil
+		if persisted {
+			err = json.Unmarshal(bz, &cursor)
+			if err != nil {
+				return err
+			}
+		}
+
+		startHeight := uint64(ec.Config.StartHeight)
+		if startHeight > 0 && (!persisted || startHeight > cursor.Height+1) {
+			cursor = LogCursor{Height: startHeight - 1, Index: -1}
+		}
+
+		if cursor != (LogCursor{}) {
+			ec.setLogCursor(cursor)
+			logging.Logger.Infof("chain %s resumes from log cursor %+v", ec.ChainID, cursor)
+			return nil
+		}
+	}
+
+	latest, err := ec.getLatestHeight()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	auth := bind.NewKeyedTransactor(privKey)
+	ec.setLogCursor(LogCursor{Height: latest, Index: -1})
 
-	nextNonce, err := ec.Client.PendingNonceAt(context.Background(), auth.From)
+	logging.Logger.Infof("chain %s starts from the latest block %d", ec.ChainID, latest)
+
+	return nil
+}
+
+// updateLogCursor updates the log cursor
+func (ec *EthChain) updateLogCursor(cursor LogCursor) {
+	ec.setLogCursor(cursor)
+
+	bz, err := json.Marshal(cursor)
 	if err != nil {
-		return nil, err
+		logging.Logger.Errorf("failed to marshal the log cursor: %s", err)
+		return
 	}
 
-	auth.GasLimit = ec.Config.GasLimit
-	auth.GasPrice = big.NewInt(int64(ec.Config.GasPrice))
-	auth.Nonce = big.NewInt(int64(nextNonce))
+	err = ec.store.Set(LogCursorKey(ec.ChainID), bz)
+	if err != nil {
+		logging.Logger.Errorf("failed to update the log cursor: %s", err)
+	}
+}
+
+// logCursor returns the log cursor
+func (ec *EthChain) logCursor() LogCursor {
+	ec.mtx.RLock()
+	defer ec.mtx.RUnlock()
+
+	return ec.cursor
+}
+
+// setLogCursor sets the log cursor in memory
+func (ec *EthChain) setLogCursor(cursor LogCursor) {
+	ec.mtx.Lock()
+	defer ec.mtx.Unlock()
 
-	return auth, nil
+	ec.cursor = cursor
 }
@@ -3,8 +3,8 @@ package eth
 import (
 	"encoding/json"
 	"github.com/spf13/viper"
-	"math/rand"
 	cfg "relayer/config"
+	"relayer/signer"
 )
 
 const (
@@ -16,7 +16,15 @@ const (
 	Key             = "key"
 	Passphrase      = "passphrase"
 	MonitorInterval = "monitor_interval"
+	FilterRange     = "filter_range"
+	GasBumpPercent  = "gas_bump_percent"
+	ResubmitPeriod  = "resubmit_period"
+	MaxWaitPeriods  = "max_wait_periods"
+	MaxGasPrice     = "max_gas_price"
+	DynamicFee      = "dynamic_fee"
+	MaxPriorityFee  = "max_priority_fee"
 	Nodes           = "nodes"
+	Signers         = "signers"
 
 	IServiceEventName  = "iservice_event_name"
 	IServiceEventSig   = "iservice_event_sig"
@@ -31,6 +39,14 @@ type BaseConfig struct {
 	Passphrase      string            `yaml:"passphrase"`
 	NodesMap        map[string]string `yaml:"nodes"`
 	MonitorInterval uint64
+	FilterRange     uint64            `yaml:"filter_range"`
+	GasBumpPercent  uint64            `yaml:"gas_bump_percent"`  // percentage to bump the gas by on resubmission
+	ResubmitPeriod  uint64            `yaml:"resubmit_period"`   // interval in seconds to resubmit the pending tx
+	MaxWaitPeriods  uint64            `yaml:"max_wait_periods"`  // number of resubmit periods to wait for the tx to be mined
+	MaxGasPrice     uint64            `yaml:"max_gas_price"`     // cap of the gas price or the max fee per gas
+	DynamicFee      bool              `yaml:"dynamic_fee"`       // whether to send the EIP-1559 dynamic fee tx
+	MaxPriorityFee  uint64            `yaml:"max_priority_fee"`  // max priority fee per gas, suggested by the node if zero
+	Signers         map[string]signer.Config `yaml:"signers"` // named signers selectable by the chains
 	IServiceEventName  string `yaml:"iservice_event_name"`
 	IServiceEventSig   string `yaml:"iservice_event_sig"`
 }
@@ -53,20 +69,19 @@ func NewBaseConfig(v *viper.Viper) *BaseConfig {
 		Key:             v.GetString(cfg.GetConfigKey(Prefix, Key)),
 		Passphrase:      v.GetString(cfg.GetConfigKey(Prefix, Passphrase)),
 		MonitorInterval: v.GetUint64(cfg.GetConfigKey(Prefix, MonitorInterval)),
+		FilterRange:     v.GetUint64(cfg.GetConfigKey(Prefix, FilterRange)),
+		GasBumpPercent:  v.GetUint64(cfg.GetConfigKey(Prefix, GasBumpPercent)),
+		ResubmitPeriod:  v.GetUint64(cfg.GetConfigKey(Prefix, ResubmitPeriod)),
+		MaxWaitPeriods:  v.GetUint64(cfg.GetConfigKey(Prefix, MaxWaitPeriods)),
+		MaxGasPrice:     v.GetUint64(cfg.GetConfigKey(Prefix, MaxGasPrice)),
+		DynamicFee:      v.GetBool(cfg.GetConfigKey(Prefix, DynamicFee)),
+		MaxPriorityFee:  v.GetUint64(cfg.GetConfigKey(Prefix, MaxPriorityFee)),
 		NodesMap:        v.GetStringMapString(cfg.GetConfigKey(Prefix, Nodes)),
+		Signers:         signer.LoadConfigs(v, cfg.GetConfigKey(Prefix, Signers)),
 		IServiceEventName:  v.GetString(cfg.GetConfigKey(Prefix, IServiceEventName)),
 		IServiceEventSig:   v.GetString(cfg.GetConfigKey(Prefix, IServiceEventSig)),
 	}
 }
-func randURL(m []string) string {
-	if len(m) == 0 {
-		return ""
-	}
-	for _, index := range rand.Perm(len(m)) {
-		return m[index]
-	}
-	return ""
-}
 
 // ValidBaseConfig validates if the given bytes is valid BaseConfig
 func ValidateBaseConfig(baseCfg []byte) error {
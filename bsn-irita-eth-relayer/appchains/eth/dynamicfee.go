@@ -0,0 +1,107 @@
+package eth
+
+import (
+	"math/big"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"relayer/signer"
+)
+
+// DynamicFeeTxType is the EIP-2718 type of the EIP-1559 dynamic fee tx
+const DynamicFeeTxType = 0x02
+
+// accessTuple defines the EIP-2930 access list entry
+type accessTuple struct {
+	Address     ethcmn.Address
+	StorageKeys []ethcmn.Hash
+}
+
+// unsignedDynamicFeeTx defines the signing payload of the dynamic fee tx
+type unsignedDynamicFeeTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         *ethcmn.Address
+	Value      *big.Int
+	Data       []byte
+	AccessList []accessTuple
+}
+
+// signedDynamicFeeTx defines the dynamic fee tx with the signature
+type signedDynamicFeeTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         *ethcmn.Address
+	Value      *big.Int
+	Data       []byte
+	AccessList []accessTuple
+	V, R, S    *big.Int
+}
+
+// signDynamicFeeTx signs the given pending tx as an EIP-1559 dynamic fee tx
+// The encoded tx and its hash are returned
+func signDynamicFeeTx(ptx *PendingTx, chainID *big.Int, keySigner signer.Signer) ([]byte, ethcmn.Hash, error) {
+	to := ethcmn.HexToAddress(ptx.To)
+
+	unsigned := unsignedDynamicFeeTx{
+		ChainID:    chainID,
+		Nonce:      ptx.Nonce,
+		GasTipCap:  ptx.GasTipCap,
+		GasFeeCap:  ptx.GasFeeCap,
+		Gas:        ptx.GasLimit,
+		To:         &to,
+		Value:      big.NewInt(0),
+		Data:       ptx.Data,
+		AccessList: []accessTuple{},
+	}
+
+	payload, err := encodeTypedTx(unsigned)
+	if err != nil {
+		return nil, ethcmn.Hash{}, err
+	}
+
+	sig, err := keySigner.Sign(crypto.Keccak256(payload))
+	if err != nil {
+		return nil, ethcmn.Hash{}, err
+	}
+
+	signed := signedDynamicFeeTx{
+		ChainID:    unsigned.ChainID,
+		Nonce:      unsigned.Nonce,
+		GasTipCap:  unsigned.GasTipCap,
+		GasFeeCap:  unsigned.GasFeeCap,
+		Gas:        unsigned.Gas,
+		To:         unsigned.To,
+		Value:      unsigned.Value,
+		Data:       unsigned.Data,
+		AccessList: unsigned.AccessList,
+		V:          new(big.Int).SetBytes([]byte{sig[64]}),
+		R:          new(big.Int).SetBytes(sig[:32]),
+		S:          new(big.Int).SetBytes(sig[32:64]),
+	}
+
+	raw, err := encodeTypedTx(signed)
+	if err != nil {
+		return nil, ethcmn.Hash{}, err
+	}
+
+	return raw, crypto.Keccak256Hash(raw), nil
+}
+
+// encodeTypedTx encodes the given tx fields prefixed by the tx type
+func encodeTypedTx(fields interface{}) ([]byte, error) {
+	bz, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{DynamicFeeTxType}, bz...), nil
+}
@@ -0,0 +1,268 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"relayer/logging"
+)
+
+const (
+	DefaultHealthCheckInterval = 30               // interval in seconds to check the health of the nodes
+	DefaultDialTimeout         = 15 * time.Second // timeout to dial or probe a node
+)
+
+// NodeStatus defines the health status of a node
+type NodeStatus struct {
+	URL     string `json:"url"`
+	Healthy bool   `json:"healthy"`
+	Height  uint64 `json:"height"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NodeClient holds the connection to the active node of the chain
+// It fails over to the other nodes on dial or subscription errors
+type NodeClient struct {
+	chainID string
+	nodes   []NodeStatus
+	active  int
+
+	rpcClient *rpc.Client
+	client    *ethclient.Client
+
+	mtx sync.RWMutex
+}
+
+// NewNodeClient constructs a new NodeClient instance and connects to the first available node
+// The node names are resolved by the nodes map and the dialing starts from a random node
+func NewNodeClient(chainID string, nodeNames []string, nodesMap map[string]string) (*NodeClient, error) {
+	if len(nodeNames) == 0 {
+		return nil, fmt.Errorf("no node specified for chain %s", chainID)
+	}
+
+	nodes := make([]NodeStatus, len(nodeNames))
+	for i, name := range nodeNames {
+		url, ok := nodesMap[name]
+		if !ok {
+			url = name
+		}
+
+		nodes[i] = NodeStatus{URL: url, Healthy: true}
+	}
+
+	nc := &NodeClient{
+		chainID: chainID,
+		nodes:   nodes,
+		active:  rand.Intn(len(nodes)),
+	}
+
+	nc.mtx.Lock()
+	defer nc.mtx.Unlock()
+
+	if err := nc.connect(); err != nil {
+		return nil, err
+	}
+
+	return nc, nil
+}
+
+// Client returns the client of the active node
+func (nc *NodeClient) Client() *ethclient.Client {
+	nc.mtx.RLock()
+	defer nc.mtx.RUnlock()
+
+	return nc.client
+}
+
+// RPC returns the rpc client of the active node
+func (nc *NodeClient) RPC() *rpc.Client {
+	nc.mtx.RLock()
+	defer nc.mtx.RUnlock()
+
+	return nc.rpcClient
+}
+
+// ActiveNode returns the url of the active node
+func (nc *NodeClient) ActiveNode() string {
+	nc.mtx.RLock()
+	defer nc.mtx.RUnlock()
+
+	return nc.nodes[nc.active].URL
+}
+
+// Nodes returns the status of all the nodes
+func (nc *NodeClient) Nodes() []NodeStatus {
+	nc.mtx.RLock()
+	defer nc.mtx.RUnlock()
+
+	return append([]NodeStatus{}, nc.nodes...)
+}
+
+// Failover switches to the next available node if the given node is still active
+// It is a no-op if the active node has been switched by others
+func (nc *NodeClient) Failover(node string, cause error) error {
+	nc.mtx.Lock()
+	defer nc.mtx.Unlock()
+
+	if nc.nodes[nc.active].URL != node {
+		return nil
+	}
+
+	nc.markUnhealthy(nc.active, cause)
+
+	return nc.switchNode()
+}
+
+// CheckHealth probes all the nodes and fails over if the active node is unhealthy
+func (nc *NodeClient) CheckHealth() {
+	nodes := nc.Nodes()
+	active := nc.ActiveNode()
+
+	for i, node := range nodes {
+		var height uint64
+		var err error
+
+		if node.URL == active {
+			height, err = probe(nc.RPC())
+		} else {
+			height, err = probeURL(node.URL)
+		}
+
+		nc.mtx.Lock()
+		if err != nil {
+			nc.markUnhealthy(i, err)
+		} else {
+			nc.nodes[i] = NodeStatus{URL: node.URL, Healthy: true, Height: height}
+		}
+		nc.mtx.Unlock()
+	}
+
+	nc.mtx.RLock()
+	healthy := nc.nodes[nc.active].Healthy
+	nc.mtx.RUnlock()
+
+	if !healthy {
+		if err := nc.Failover(active, fmt.Errorf("health check failed")); err != nil {
+			logging.Logger.Errorf("failed to fail over on %s: %s", nc.chainID, err)
+		}
+	}
+}
+
+// Close closes the connection to the active node
+func (nc *NodeClient) Close() {
+	nc.mtx.Lock()
+	defer nc.mtx.Unlock()
+
+	if nc.rpcClient != nil {
+		nc.rpcClient.Close()
+	}
+}
+
+// switchNode closes the current connection and connects to the next available node
+// The healthy nodes are tried before the unhealthy ones
+func (nc *NodeClient) switchNode() error {
+	from := nc.nodes[nc.active].URL
+
+	if nc.rpcClient != nil {
+		nc.rpcClient.Close()
+	}
+
+	next := nc.active
+	for i := 1; i <= len(nc.nodes); i++ {
+		j := (nc.active + i) % len(nc.nodes)
+		if nc.nodes[j].Healthy {
+			next = j
+			break
+		}
+	}
+
+	if next == nc.active {
+		next = (nc.active + 1) % len(nc.nodes)
+	}
+
+	nc.active = next
+
+	if err := nc.connect(); err != nil {
+		return err
+	}
+
+	logging.Logger.Warnf("chain %s failed over from %s to %s", nc.chainID, from, nc.nodes[nc.active].URL)
+
+	return nil
+}
+
+// connect dials the active node, and the following nodes in turn on failure
+func (nc *NodeClient) connect() error {
+	var err error
+
+	for i := 0; i < len(nc.nodes); i++ {
+		url := nc.nodes[nc.active].URL
+
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultDialTimeout)
+		rpcClient, dialErr := rpc.DialContext(ctx, url)
+		cancel()
+
+		// the http connection is lazy, so the node is probed as well
+		if dialErr == nil {
+			var height uint64
+			if height, dialErr = probe(rpcClient); dialErr == nil {
+				nc.rpcClient = rpcClient
+				nc.client = ethclient.NewClient(rpcClient)
+				nc.nodes[nc.active] = NodeStatus{URL: url, Healthy: true, Height: height}
+
+				return nil
+			}
+
+			rpcClient.Close()
+		}
+
+		err = dialErr
+		nc.markUnhealthy(nc.active, dialErr)
+
+		logging.Logger.Errorf("failed to connect to node %s of %s: %s", url, nc.chainID, dialErr)
+
+		nc.active = (nc.active + 1) % len(nc.nodes)
+	}
+
+	return fmt.Errorf("failed to connect to eth node: %s", err)
+}
+
+func (nc *NodeClient) markUnhealthy(i int, cause error) {
+	nc.nodes[i].Healthy = false
+	if cause != nil {
+		nc.nodes[i].Error = cause.Error()
+	}
+}
+
+// probeURL dials the given node and retrieves the latest height
+func probeURL(url string) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDialTimeout)
+	defer cancel()
+
+	rpcClient, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+	defer rpcClient.Close()
+
+	return probe(rpcClient)
+}
+
+// probe retrieves the latest height by the given rpc client
+func probe(rpcClient *rpc.Client) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDialTimeout)
+	defer cancel()
+
+	header, err := ethclient.NewClient(rpcClient).HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return header.Number.Uint64(), nil
+}
@@ -0,0 +1,83 @@
+package eth
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// newTestNode starts a JSON-RPC node which serves the latest header at the given height
+func newTestNode(height int64) *httptest.Server {
+	header, _ := json.Marshal(&ethtypes.Header{Number: big.NewInt(height), Difficulty: big.NewInt(1)})
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, header)
+	}))
+}
+
+func TestNodeClientFailover(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	up := newTestNode(100)
+	defer up.Close()
+
+	nc, err := NewNodeClient("test", []string{"node1", "node2"}, map[string]string{"node1": down.URL, "node2": up.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+
+	if nc.ActiveNode() != up.URL {
+		t.Fatalf("expected the available node to be active, got %s", nc.ActiveNode())
+	}
+
+	// stale failover is ignored
+	if err := nc.Failover(down.URL, fmt.Errorf("stale")); err != nil || nc.ActiveNode() != up.URL {
+		t.Fatal("expected the stale failover to be ignored")
+	}
+
+	// no other node is available, so it comes back to the same node
+	if err := nc.Failover(up.URL, fmt.Errorf("subscription error")); err != nil {
+		t.Fatal(err)
+	}
+	if nc.ActiveNode() != up.URL {
+		t.Fatalf("unexpected active node %s", nc.ActiveNode())
+	}
+
+	standby := newTestNode(101)
+	defer standby.Close()
+
+	nc.nodes[0].URL = standby.URL
+	nc.CheckHealth()
+
+	for _, node := range nc.Nodes() {
+		if !node.Healthy {
+			t.Fatalf("expected node %s to be healthy", node.URL)
+		}
+	}
+
+	if err := nc.Failover(up.URL, fmt.Errorf("subscription error")); err != nil {
+		t.Fatal(err)
+	}
+	if nc.ActiveNode() != standby.URL {
+		t.Fatalf("expected to fail over to the standby node, got %s", nc.ActiveNode())
+	}
+
+	if _, err := NewNodeClient("test", []string{down.URL}, nil); err == nil {
+		t.Fatal("expected the connection to fail without available nodes")
+	}
+}
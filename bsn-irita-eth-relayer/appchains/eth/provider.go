@@ -0,0 +1,44 @@
+package eth
+
+import (
+	"fmt"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+
+	"relayer/core"
+	"relayer/logging"
+)
+
+// CallContract implements ContractCallerI
+// The call data is sent as the tx data to the contract, which includes the method selector
+func (ec *EthChain) CallContract(endpointAddress string, method string, callData []byte) (output string, txHash string, err error) {
+	if !ethcmn.IsHexAddress(endpointAddress) {
+		return "", "", fmt.Errorf("invalid contract address %s", endpointAddress)
+	}
+
+	// the pending tx of the same call is reused on retry, so the failures to send or mine are transient
+	ptx, err := ec.txManager.Send(ethcmn.HexToAddress(endpointAddress), callData)
+	if err != nil {
+		return "", "", core.NewTransientError(fmt.Errorf("failed to call %s of %s: %s", method, endpointAddress, err))
+	}
+
+	txHash = ptx.Hash()
+	logging.Logger.Infof("contract %s called on %s, method: %s, tx: %s", endpointAddress, ec.ChainID, method, txHash)
+
+	receipt, err := ec.txManager.Wait(ptx)
+	if receipt != nil {
+		txHash = receipt.TxHash.Hex()
+	}
+
+	if err != nil {
+		return "", txHash, core.NewTransientError(err)
+	}
+
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		return "", txHash, fmt.Errorf("tx %s of calling %s reverted", txHash, method)
+	}
+
+	return "", txHash, nil
+}
@@ -3,6 +3,7 @@ package eth
 import (
 	"fmt"
 
+	"relayer/secrets"
 	"relayer/store"
 )
 
@@ -12,6 +13,8 @@ const (
 	KeyBaseConfig        = "baseconfig"
 	KeyPrefixChainParams = "params"
 	KeyPrefixHeight      = "height"
+	KeyPrefixLogCursor   = "logcursor"
+	KeyPrefixPendingTx   = "pendingtx"
 )
 
 // BaseConfigKey returns the key for the FISCO base config
@@ -29,6 +32,11 @@ func HeightKey(chainID string) []byte {
 	return []byte(fmt.Sprintf("%s:%s:%s", StorePrefix, KeyPrefixHeight, chainID))
 }
 
+// LogCursorKey returns the key for the log cursor of the specified chain
+func LogCursorKey(chainID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s", StorePrefix, KeyPrefixLogCursor, chainID))
+}
+
 // StoreBaseConfig stores the base config
 func StoreBaseConfig(store *store.Store, baseConfig []byte) error {
 	err := ValidateBaseConfig(baseConfig)
@@ -36,5 +44,10 @@ func StoreBaseConfig(store *store.Store, baseConfig []byte) error {
 		return err
 	}
 
-	return store.Set(BaseConfigKey(), baseConfig)
+	bz, err := secrets.Seal(baseConfig)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the base config: %s", err)
+	}
+
+	return store.Set(BaseConfigKey(), bz)
 }
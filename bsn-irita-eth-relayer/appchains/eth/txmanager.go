@@ -0,0 +1,593 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"relayer/logging"
+	"relayer/signer"
+	"relayer/store"
+)
+
+const (
+	DefaultGasBumpPercent  = 20  // percentage to bump the gas price by on resubmission
+	DefaultResubmitPeriod  = 120 // interval in seconds to resubmit the pending tx
+	DefaultMaxWaitPeriods  = 5   // number of resubmit periods to wait for the tx to be mined
+	DefaultReceiptInterval = 2   // interval in seconds to poll the tx receipt
+)
+
+// errWatchStopped is returned when the tx manager stops watching the pending txs
+var errWatchStopped = errors.New("watch stopped")
+
+// PendingTx defines the tx which has been broadcast and is waiting to be mined
+type PendingTx struct {
+	Nonce     uint64   `json:"nonce"`
+	To        string   `json:"to"`
+	Data      []byte   `json:"data"`
+	GasLimit  uint64   `json:"gas_limit"`
+	GasPrice  *big.Int `json:"gas_price,omitempty"`   // gas price of the legacy tx
+	GasTipCap *big.Int `json:"gas_tip_cap,omitempty"` // max priority fee per gas of the dynamic fee tx
+	GasFeeCap *big.Int `json:"gas_fee_cap,omitempty"` // max fee per gas of the dynamic fee tx
+	Hashes    []string `json:"hashes"`                // hashes of all the broadcast txs, the latest last
+	SentTime  int64    `json:"sent_time"`
+}
+
+// Hash returns the hash of the latest broadcast tx
+func (ptx *PendingTx) Hash() string {
+	if len(ptx.Hashes) == 0 {
+		return ""
+	}
+
+	return ptx.Hashes[len(ptx.Hashes)-1]
+}
+
+// txWatch tracks the pending tx watched until mined
+type txWatch struct {
+	ptx      *PendingTx        // only updated by the watching goroutine
+	done     chan struct{}     // closed once the tx is mined or dropped
+	receipt  *ethtypes.Receipt // receipt of the mined tx
+	err      error             // error of the tx
+	waiters  int               // number of the callers waiting for the tx
+	timedOut bool              // whether a caller gave up waiting, in which case the result is kept for the same call
+}
+
+// TxManager allocates the nonces locally and serializes the tx submissions of the chain
+// The pending txs are persisted and resubmitted with bumped gas until mined
+// The same call is not sent again while its tx is pending, the pending tx is reused instead
+type TxManager struct {
+	chainID string
+	config  BaseConfig
+	nodes   *NodeClient
+	store   *store.Store
+
+	keySigner signer.Signer
+	from      ethcmn.Address
+	signer    ethtypes.Signer
+	netID     *big.Int
+
+	nonce       uint64
+	nonceLoaded bool
+	watches     map[string]*txWatch // pending txs being watched by the call
+	watchers    sync.WaitGroup      // tracks the watching goroutines
+	quit        chan struct{}       // closed to stop watching the pending txs
+	mtx         sync.Mutex
+}
+
+// NewTxManager constructs a new TxManager instance
+func NewTxManager(chainID string, config BaseConfig, nodes *NodeClient, keySigner signer.Signer, store *store.Store) *TxManager {
+	if config.GasBumpPercent == 0 {
+		config.GasBumpPercent = DefaultGasBumpPercent
+	}
+
+	if config.ResubmitPeriod == 0 {
+		config.ResubmitPeriod = DefaultResubmitPeriod
+	}
+
+	if config.MaxWaitPeriods == 0 {
+		config.MaxWaitPeriods = DefaultMaxWaitPeriods
+	}
+
+	return &TxManager{
+		chainID:   chainID,
+		config:    config,
+		nodes:     nodes,
+		store:     store,
+		keySigner: keySigner,
+		from:      keySigner.Address(),
+		watches:   map[string]*txWatch{},
+		quit:      make(chan struct{}),
+	}
+}
+
+// PendingTxKey returns the key of the pending tx with the given nonce
+func PendingTxKey(chainID string, nonce uint64) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%020d", KeyPrefixPendingTx, chainID, nonce))
+}
+
+// callKey returns the key of the call with the given contract and call data
+func callKey(to string, data []byte) string {
+	return fmt.Sprintf("%s:%x", strings.ToLower(to), data)
+}
+
+// Send broadcasts a tx with the given call data and the next nonce
+// The pending tx of the same call is returned instead if any, without allocating a new nonce
+func (m *TxManager) Send(to ethcmn.Address, data []byte) (*PendingTx, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if w, ok := m.watches[callKey(to.Hex(), data)]; ok {
+		logging.Logger.Infof("reusing the pending tx of the same call on %s, nonce: %d", m.chainID, w.ptx.Nonce)
+
+		return w.snapshot(), nil
+	}
+
+	if err := m.loadNonce(); err != nil {
+		return nil, err
+	}
+
+	ptx := &PendingTx{
+		Nonce:    m.nonce,
+		To:       to.Hex(),
+		Data:     data,
+		GasLimit: m.config.GasLimit,
+	}
+
+	if err := m.initFees(ptx); err != nil {
+		return nil, err
+	}
+
+	if err := m.broadcast(ptx); err != nil {
+		if strings.Contains(err.Error(), "nonce too low") {
+			// the nonce is consumed outside, resync on the next submission
+			m.nonceLoaded = false
+		}
+
+		return nil, err
+	}
+
+	m.nonce++
+
+	if err := m.savePendingTx(ptx); err != nil {
+		logging.Logger.Errorf("failed to persist the pending tx %s on %s: %s", ptx.Hash(), m.chainID, err)
+	}
+
+	m.watch(ptx)
+
+	return ptx, nil
+}
+
+// Wait waits until the given pending tx is mined
+// The tx is resubmitted with bumped gas if not mined within the resubmit interval
+// An error is returned if the tx is not mined within the max wait periods, while the tx keeps being watched
+// and is reused when the same call is sent again
+func (m *TxManager) Wait(ptx *PendingTx) (*ethtypes.Receipt, error) {
+	timeout := time.Duration(m.config.MaxWaitPeriods*m.config.ResubmitPeriod) * time.Second
+
+	m.mtx.Lock()
+	w := m.watch(ptx)
+	w.waiters++
+	m.mtx.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-w.done:
+		m.mtx.Lock()
+		w.waiters--
+		m.forget(w)
+		m.mtx.Unlock()
+
+		return w.receipt, w.err
+
+	case <-timer.C:
+		m.mtx.Lock()
+		w.waiters--
+		w.timedOut = true
+		m.mtx.Unlock()
+
+		return nil, fmt.Errorf("transaction %s not mined on %s within %d seconds", ptx.Hash(), m.chainID, m.config.MaxWaitPeriods*m.config.ResubmitPeriod)
+	}
+}
+
+// watch returns the watch of the given pending tx, which is started if not watched
+// The caller must hold the lock
+func (m *TxManager) watch(ptx *PendingTx) *txWatch {
+	key := callKey(ptx.To, ptx.Data)
+
+	if w, ok := m.watches[key]; ok {
+		return w
+	}
+
+	if m.watches == nil {
+		m.watches = map[string]*txWatch{}
+	}
+
+	watched := *ptx
+	w := &txWatch{ptx: &watched, done: make(chan struct{})}
+	m.watches[key] = w
+
+	m.watchers.Add(1)
+	go func() {
+		defer m.watchers.Done()
+
+		receipt, err := m.wait(w.ptx)
+		if err == errWatchStopped {
+			return
+		}
+
+		if err != nil {
+			logging.Logger.Errorf("tx %s on %s failed: %s", w.ptx.Hash(), m.chainID, err)
+		}
+
+		m.mtx.Lock()
+		defer m.mtx.Unlock()
+
+		w.receipt, w.err = receipt, err
+		close(w.done)
+
+		// the result is kept for the callers given up waiting
+		if w.waiters == 0 && !w.timedOut {
+			m.forget(w)
+		}
+	}()
+
+	return w
+}
+
+// forget removes the given watch once its result is taken
+// The caller must hold the lock
+func (m *TxManager) forget(w *txWatch) {
+	key := callKey(w.ptx.To, w.ptx.Data)
+
+	if m.watches[key] == w {
+		delete(m.watches, key)
+	}
+}
+
+// snapshot returns a copy of the watched pending tx
+// The caller must hold the lock
+func (w *txWatch) snapshot() *PendingTx {
+	ptx := *w.ptx
+	ptx.Hashes = append([]string{}, w.ptx.Hashes...)
+
+	return &ptx
+}
+
+// wait polls the receipt of the given pending tx until mined or the tx manager stops watching
+func (m *TxManager) wait(ptx *PendingTx) (*ethtypes.Receipt, error) {
+	ticker := time.NewTicker(DefaultReceiptInterval * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-m.quit:
+			return nil, errWatchStopped
+		}
+
+		receipt, err := m.receipt(ptx)
+		if err != nil {
+			logging.Logger.Errorf("failed to query the receipt of tx %s on %s: %s", ptx.Hash(), m.chainID, err)
+			continue
+		}
+
+		if receipt != nil {
+			m.deletePendingTx(ptx)
+
+			if receipt.Status == ethtypes.ReceiptStatusFailed {
+				return receipt, fmt.Errorf("transaction %s execution failed", receipt.TxHash.Hex())
+			}
+
+			return receipt, nil
+		}
+
+		nonce, err := m.nodes.Client().NonceAt(context.Background(), m.from, nil)
+		if err == nil && nonce > ptx.Nonce {
+			// the receipt may be queried before the nonce is updated
+			if receipt, err := m.receipt(ptx); err == nil && receipt != nil {
+				continue
+			}
+
+			m.deletePendingTx(ptx)
+
+			return nil, fmt.Errorf("nonce %d replaced by another transaction", ptx.Nonce)
+		}
+
+		if time.Now().Unix()-ptx.SentTime >= int64(m.config.ResubmitPeriod) {
+			m.resubmit(ptx)
+		}
+	}
+}
+
+// Recover resumes waiting for the pending txs persisted before the restart
+func (m *TxManager) Recover() error {
+	ptxs := make([]*PendingTx, 0)
+
+	err := m.store.Iterate([]byte(fmt.Sprintf("%s:%s:", KeyPrefixPendingTx, m.chainID)), func(key, value []byte) bool {
+		var ptx PendingTx
+		if err := json.Unmarshal(value, &ptx); err != nil {
+			logging.Logger.Errorf("failed to decode the pending tx %s: %s", key, err)
+			return true
+		}
+
+		ptxs = append(ptxs, &ptx)
+
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for _, ptx := range ptxs {
+		logging.Logger.Infof("recovering the pending tx %s with nonce %d on %s", ptx.Hash(), ptx.Nonce, m.chainID)
+
+		// resending the same call waits for the recovered tx
+		m.watch(ptx)
+	}
+
+	return nil
+}
+
+// loadNonce loads the next nonce from the chain if it is not loaded
+// The nonces of the persisted pending txs are skipped in case they are dropped by the node
+func (m *TxManager) loadNonce() error {
+	if m.nonceLoaded {
+		return nil
+	}
+
+	nonce, err := m.nodes.Client().PendingNonceAt(context.Background(), m.from)
+	if err != nil {
+		return err
+	}
+
+	err = m.store.Iterate([]byte(fmt.Sprintf("%s:%s:", KeyPrefixPendingTx, m.chainID)), func(key, value []byte) bool {
+		var ptx PendingTx
+		if err := json.Unmarshal(value, &ptx); err == nil && ptx.Nonce >= nonce {
+			nonce = ptx.Nonce + 1
+		}
+
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	m.nonce = nonce
+	m.nonceLoaded = true
+
+	return nil
+}
+
+// initFees sets the initial fees of the given tx
+func (m *TxManager) initFees(ptx *PendingTx) error {
+	if !m.config.DynamicFee {
+		ptx.GasPrice = new(big.Int).SetUint64(m.config.GasPrice)
+		return nil
+	}
+
+	tip, feeCap, err := m.suggestDynamicFees()
+	if err != nil {
+		return err
+	}
+
+	ptx.GasTipCap = tip
+	ptx.GasFeeCap = feeCap
+
+	return nil
+}
+
+// resubmit broadcasts the given tx again with bumped gas
+func (m *TxManager) resubmit(ptx *PendingTx) {
+	bumped := *ptx
+
+	if m.config.DynamicFee {
+		bumped.GasTipCap = m.bump(ptx.GasTipCap)
+		bumped.GasFeeCap = m.bump(ptx.GasFeeCap)
+
+		if bumped.GasTipCap.Cmp(bumped.GasFeeCap) > 0 {
+			bumped.GasTipCap = bumped.GasFeeCap
+		}
+
+		if bumped.GasFeeCap.Cmp(ptx.GasFeeCap) == 0 {
+			logging.Logger.Warnf("tx %s on %s stuck at the max fee per gas %s", ptx.Hash(), m.chainID, ptx.GasFeeCap)
+			return
+		}
+	} else {
+		bumped.GasPrice = m.bump(ptx.GasPrice)
+
+		if bumped.GasPrice.Cmp(ptx.GasPrice) == 0 {
+			logging.Logger.Warnf("tx %s on %s stuck at the max gas price %s", ptx.Hash(), m.chainID, ptx.GasPrice)
+			return
+		}
+	}
+
+	// the pending tx is updated under the lock as it is read by the callers of the same call
+	m.mtx.Lock()
+	err := m.broadcast(&bumped)
+	if err != nil {
+		// retry on the next interval
+		ptx.SentTime = time.Now().Unix()
+	} else {
+		*ptx = bumped
+	}
+	m.mtx.Unlock()
+
+	if err != nil {
+		logging.Logger.Errorf("failed to resubmit the tx %s on %s: %s", ptx.Hash(), m.chainID, err)
+		return
+	}
+
+	logging.Logger.Infof("tx with nonce %d resubmitted to %s with bumped gas, hash: %s", ptx.Nonce, m.chainID, ptx.Hash())
+
+	if err := m.savePendingTx(ptx); err != nil {
+		logging.Logger.Errorf("failed to persist the pending tx %s on %s: %s", ptx.Hash(), m.chainID, err)
+	}
+}
+
+// bump returns the given price increased by the bump percentage and capped by the max gas price
+func (m *TxManager) bump(price *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(price, big.NewInt(int64(100+m.config.GasBumpPercent)))
+	bumped.Div(bumped, big.NewInt(100))
+
+	if bumped.Cmp(price) <= 0 {
+		bumped.Add(price, big.NewInt(1))
+	}
+
+	if m.config.MaxGasPrice > 0 {
+		max := new(big.Int).SetUint64(m.config.MaxGasPrice)
+		if bumped.Cmp(max) > 0 {
+			bumped = max
+		}
+
+		if price.Cmp(max) >= 0 {
+			bumped = new(big.Int).Set(price)
+		}
+	}
+
+	return bumped
+}
+
+// broadcast signs and sends the given tx and records the hash
+// The caller must hold the lock
+func (m *TxManager) broadcast(ptx *PendingTx) error {
+	if m.netID == nil {
+		netID, err := m.nodes.Client().ChainID(context.Background())
+		if err != nil {
+			return err
+		}
+
+		m.netID = netID
+		m.signer = ethtypes.NewEIP155Signer(netID)
+	}
+
+	var hash ethcmn.Hash
+
+	if m.config.DynamicFee {
+		raw, txHash, err := signDynamicFeeTx(ptx, m.netID, m.keySigner)
+		if err != nil {
+			return err
+		}
+
+		err = m.nodes.RPC().CallContext(context.Background(), nil, "eth_sendRawTransaction", hexutil.Encode(raw))
+		if err != nil {
+			return err
+		}
+
+		hash = txHash
+	} else {
+		tx := ethtypes.NewTransaction(ptx.Nonce, ethcmn.HexToAddress(ptx.To), big.NewInt(0), ptx.GasLimit, ptx.GasPrice, ptx.Data)
+
+		sig, err := m.keySigner.Sign(m.signer.Hash(tx).Bytes())
+		if err != nil {
+			return err
+		}
+
+		signedTx, err := tx.WithSignature(m.signer, sig)
+		if err != nil {
+			return err
+		}
+
+		if err := m.nodes.Client().SendTransaction(context.Background(), signedTx); err != nil {
+			return err
+		}
+
+		hash = signedTx.Hash()
+	}
+
+	ptx.Hashes = append(ptx.Hashes, hash.Hex())
+	ptx.SentTime = time.Now().Unix()
+
+	logging.Logger.Infof("transaction sent to %s, nonce: %d, hash: %s", m.chainID, ptx.Nonce, hash.Hex())
+
+	return nil
+}
+
+// receipt returns the receipt of any broadcast tx of the given pending tx
+// Nil is returned if none is mined
+func (m *TxManager) receipt(ptx *PendingTx) (*ethtypes.Receipt, error) {
+	for i := len(ptx.Hashes) - 1; i >= 0; i-- {
+		receipt, err := m.nodes.Client().TransactionReceipt(context.Background(), ethcmn.HexToHash(ptx.Hashes[i]))
+		if err == nil {
+			return receipt, nil
+		}
+
+		if err != ethereum.NotFound {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// suggestDynamicFees returns the max priority fee and max fee per gas for the dynamic fee tx
+func (m *TxManager) suggestDynamicFees() (*big.Int, *big.Int, error) {
+	var head struct {
+		BaseFee *hexutil.Big `json:"baseFeePerGas"`
+	}
+
+	err := m.nodes.RPC().CallContext(context.Background(), &head, "eth_getBlockByNumber", "latest", false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if head.BaseFee == nil {
+		return nil, nil, fmt.Errorf("dynamic fee not supported by chain %s", m.chainID)
+	}
+
+	tip := new(big.Int).SetUint64(m.config.MaxPriorityFee)
+	if tip.Sign() == 0 {
+		var suggested hexutil.Big
+		if err := m.nodes.RPC().CallContext(context.Background(), &suggested, "eth_maxPriorityFeePerGas"); err != nil {
+			return nil, nil, err
+		}
+
+		tip = suggested.ToInt()
+	}
+
+	// leave room for the base fee to double
+	feeCap := new(big.Int).Mul(head.BaseFee.ToInt(), big.NewInt(2))
+	feeCap.Add(feeCap, tip)
+
+	if m.config.MaxGasPrice > 0 {
+		max := new(big.Int).SetUint64(m.config.MaxGasPrice)
+		if feeCap.Cmp(max) > 0 {
+			feeCap = max
+		}
+
+		if tip.Cmp(feeCap) > 0 {
+			tip = new(big.Int).Set(feeCap)
+		}
+	}
+
+	return tip, feeCap, nil
+}
+
+func (m *TxManager) savePendingTx(ptx *PendingTx) error {
+	bz, err := json.Marshal(ptx)
+	if err != nil {
+		return err
+	}
+
+	return m.store.Set(PendingTxKey(m.chainID, ptx.Nonce), bz)
+}
+
+func (m *TxManager) deletePendingTx(ptx *PendingTx) {
+	if err := m.store.Delete(PendingTxKey(m.chainID, ptx.Nonce)); err != nil {
+		logging.Logger.Errorf("failed to delete the pending tx %s on %s: %s", ptx.Hash(), m.chainID, err)
+	}
+}
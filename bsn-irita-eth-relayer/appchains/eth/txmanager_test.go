@@ -0,0 +1,172 @@
+package eth
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"relayer/signer"
+)
+
+func TestTxManagerBump(t *testing.T) {
+	m := &TxManager{config: BaseConfig{GasBumpPercent: 20, MaxGasPrice: 150}}
+
+	if bumped := m.bump(big.NewInt(100)); bumped.Int64() != 120 {
+		t.Fatalf("expected 120, got %s", bumped)
+	}
+	if bumped := m.bump(big.NewInt(140)); bumped.Int64() != 150 {
+		t.Fatalf("expected the bumped price to be capped at 150, got %s", bumped)
+	}
+	if bumped := m.bump(big.NewInt(200)); bumped.Int64() != 200 {
+		t.Fatalf("expected the price beyond the cap to be kept, got %s", bumped)
+	}
+	if bumped := m.bump(big.NewInt(1)); bumped.Int64() != 2 {
+		t.Fatalf("expected the price to be increased at least by 1, got %s", bumped)
+	}
+}
+
+func TestSignDynamicFeeTx(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ptx := &PendingTx{
+		Nonce:     7,
+		To:        "0x0000000000000000000000000000000000000001",
+		Data:      []byte{0x01, 0x02},
+		GasLimit:  21000,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(100),
+	}
+
+	raw, hash, err := signDynamicFeeTx(ptx, big.NewInt(5), signer.NewKeySigner(privKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw[0] != DynamicFeeTxType {
+		t.Fatalf("expected the tx type prefix 0x02, got %#x", raw[0])
+	}
+	if hash != crypto.Keccak256Hash(raw) {
+		t.Fatal("unexpected tx hash")
+	}
+
+	var signed signedDynamicFeeTx
+	if err := rlp.DecodeBytes(raw[1:], &signed); err != nil {
+		t.Fatal(err)
+	}
+	if signed.Nonce != 7 || signed.ChainID.Int64() != 5 || signed.GasFeeCap.Int64() != 100 {
+		t.Fatalf("unexpected decoded tx: %+v", signed)
+	}
+
+	payload, err := encodeTypedTx(unsignedDynamicFeeTx{
+		ChainID:    signed.ChainID,
+		Nonce:      signed.Nonce,
+		GasTipCap:  signed.GasTipCap,
+		GasFeeCap:  signed.GasFeeCap,
+		Gas:        signed.Gas,
+		To:         signed.To,
+		Value:      signed.Value,
+		Data:       signed.Data,
+		AccessList: signed.AccessList,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := make([]byte, 65)
+	signed.R.FillBytes(sig[:32])
+	signed.S.FillBytes(sig[32:64])
+	sig[64] = byte(signed.V.Uint64())
+
+	pubKey, err := crypto.SigToPub(crypto.Keccak256(payload), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*pubKey) != crypto.PubkeyToAddress(privKey.PublicKey) {
+		t.Fatal("unexpected signer")
+	}
+}
+
+func TestTxManagerWaitDeadline(t *testing.T) {
+	header, _ := json.Marshal(&ethtypes.Header{Number: big.NewInt(100), Difficulty: big.NewInt(1)})
+
+	// the node never mines the tx
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		result := "null"
+		switch req.Method {
+		case "eth_getBlockByNumber":
+			result = string(header)
+		case "eth_getTransactionCount":
+			result = `"0x0"`
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, result)
+	}))
+	defer node.Close()
+
+	nodes, err := NewNodeClient("test", []string{node.URL}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nodes.Close()
+
+	m := &TxManager{
+		chainID: "test",
+		config:  BaseConfig{ResubmitPeriod: 1, MaxWaitPeriods: 1},
+		nodes:   nodes,
+		quit:    make(chan struct{}),
+	}
+
+	// the watching goroutine is stopped when the test ends
+	defer func() {
+		close(m.quit)
+		m.watchers.Wait()
+	}()
+
+	to := ethcmn.HexToAddress("0x02")
+
+	// not due to be resubmitted during the test
+	ptx := &PendingTx{Nonce: 7, To: to.Hex(), Data: []byte{1}, Hashes: []string{"0x01"}, SentTime: time.Now().Add(time.Hour).Unix()}
+
+	start := time.Now()
+
+	_, err = m.Wait(ptx)
+	if err == nil || !strings.Contains(err.Error(), "not mined") {
+		t.Fatalf("expected the wait to time out, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("expected the wait to return after the deadline, took %s", elapsed)
+	}
+
+	// the same call resent reuses the watched tx without a new nonce
+	resent, err := m.Send(to, []byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resent.Nonce != 7 || resent.Hash() != "0x01" {
+		t.Fatalf("expected the pending tx to be reused, got nonce %d, hash %s", resent.Nonce, resent.Hash())
+	}
+
+	if m.nonceLoaded {
+		t.Fatal("expected no nonce to be allocated")
+	}
+}
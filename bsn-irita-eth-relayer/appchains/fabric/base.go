@@ -0,0 +1,98 @@
+package fabric
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const (
+	ChainType = "fabric"
+
+	// key written by the cross-chain chaincode with the ID of the request sent
+	KeyCallService = "CallService"
+)
+
+// ChainParams defines the params for the specific chain
+type ChainParams struct {
+	ChainID        uint64   `json:"chainId"`
+	AppCode        string   `json:"appCode"`
+	ChannelID      string   `json:"channelId"`
+	CrossChainCode string   `json:"ccm"`                  // name of the cross-chain chaincode
+	Nodes          []string `json:"nodes"`                // peers of the channel, the first of which is used
+	StartBlock     *uint64  `json:"startBlock,omitempty"` // block to start from when it is beyond the checkpoint
+	Timeout        int64    `json:"timeout,omitempty"`    // service timeout in blocks on the Hub for the requests of the chain
+}
+
+type EndpointInfo struct {
+	DestSubChainID  string `json:"dest_sub_chain_id"`
+	DestChainID     string `json:"dest_chain_id"`
+	DestChainType   string `json:"dest_chain_type"`
+	EndpointAddress string `json:"endpoint_address"`
+	EndpointType    string `json:"endpoint_type"`
+}
+
+// serviceCallInfo defines the request and response kept by the cross-chain chaincode
+type serviceCallInfo struct {
+	Request  *serviceRequest  `json:"request,omitempty"`
+	Response *serviceResponse `json:"response,omitempty"`
+	Status   string           `json:"status"`
+	Type     string           `json:"type"`
+}
+
+type serviceRequest struct {
+	RequestId    string        `json:"requestID,omitempty"`
+	EndpointInfo string        `json:"serviceName,omitempty"`
+	Method       string        `json:"input,omitempty"`
+	CallData     string        `json:"timeout,omitempty"`
+	CallBack     *CallBackInfo `json:"callback,omitempty"`
+}
+
+type CallBackInfo struct {
+	ChainCode string `json:"chainCode"`
+	FuncName  string `json:"funcName"`
+}
+
+type serviceResponse struct {
+	RequestId   string `json:"requestID,omitempty"`
+	ErrMsg      string `json:"errMsg,omitempty"`
+	Output      string `json:"output,omitempty"`
+	IcRequestId string `json:"icRequestID,omitempty"`
+}
+
+// GetChainID returns the unique chain id from the specified chain params
+func GetChainID(params ChainParams) string {
+	return strconv.FormatUint(params.ChainID, 10)
+}
+
+// GetChainIDFromBytes returns the unique chain id from the given chain params bytes
+func GetChainIDFromBytes(params []byte) (string, error) {
+	var chainParams ChainParams
+	err := json.Unmarshal(params, &chainParams)
+	if err != nil {
+		return "", err
+	}
+
+	return GetChainID(chainParams), nil
+}
+
+// ValidateChainParams validates the given chain params
+func ValidateChainParams(params ChainParams) error {
+	if params.ChainID == 0 {
+		return fmt.Errorf("chain id must be specified")
+	}
+
+	if len(params.ChannelID) == 0 {
+		return fmt.Errorf("channel id must be specified")
+	}
+
+	if len(params.CrossChainCode) == 0 {
+		return fmt.Errorf("cross-chain chaincode must be specified")
+	}
+
+	if len(params.Nodes) == 0 || len(params.Nodes[0]) == 0 {
+		return fmt.Errorf("at least one node must be specified")
+	}
+
+	return nil
+}
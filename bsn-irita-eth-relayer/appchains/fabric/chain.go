@@ -0,0 +1,473 @@
+package fabric
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	eventfab "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+
+	"relayer/appchains/fabric/blockparser"
+	txstore "relayer/appchains/store"
+	"relayer/core"
+	"relayer/logging"
+	"relayer/metrics"
+	"relayer/secrets"
+	"relayer/store"
+)
+
+// FabricChain defines the Fabric chain
+type FabricChain struct {
+	Config  Config
+	ChainID string // unique chain ID
+
+	sdk             *fabsdk.FabricSDK
+	channelProvider context.ChannelProvider
+	channelClient   *channel.Client
+	ledgerClient    *ledger.Client
+
+	store     *store.Store // store backend instance
+	lastBlock int64        // last processed block
+
+	mtx     sync.Mutex
+	done    bool                          // indicates if the chain monitor is done
+	stop    chan struct{}                 // closed to stop the event listener
+	handler core.InterchainRequestHandler // handler for the interchain request
+}
+
+// NewFabricChain constructs a new FabricChain instance
+func NewFabricChain(
+	config Config,
+	store *store.Store,
+) (*FabricChain, error) {
+	if err := ValidateChainParams(config.ChainParams); err != nil {
+		return nil, err
+	}
+
+	sdk, err := fabsdk.New(BuildSdkConfig(config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to init the fabric sdk: %s", err)
+	}
+
+	channelProvider := sdk.ChannelContext(
+		config.ChannelID,
+		fabsdk.WithOrg(config.OrgName),
+		fabsdk.WithUser(config.MspUserName),
+	)
+
+	channelClient, err := channel.New(channelProvider)
+	if err != nil {
+		sdk.Close()
+		return nil, fmt.Errorf("failed to init the fabric channel client: %s", err)
+	}
+
+	ledgerClient, err := ledger.New(channelProvider)
+	if err != nil {
+		sdk.Close()
+		return nil, fmt.Errorf("failed to init the fabric ledger client: %s", err)
+	}
+
+	fabric := &FabricChain{
+		Config:          config,
+		ChainID:         GetChainID(config.ChainParams),
+		sdk:             sdk,
+		channelProvider: channelProvider,
+		channelClient:   channelClient,
+		ledgerClient:    ledgerClient,
+		store:           store,
+		done:            true,
+	}
+
+	err = fabric.storeChainParams()
+	if err != nil {
+		return nil, err
+	}
+
+	err = fabric.storeChainID()
+	if err != nil {
+		return nil, err
+	}
+
+	return fabric, nil
+}
+
+// BuildFabricChain builds a FabricChain instance from the given chain params and store
+func BuildFabricChain(
+	chainParams []byte,
+	store *store.Store,
+) (*FabricChain, error) {
+	chainParams, err := secrets.Open(chainParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the chain params: %s", err)
+	}
+
+	var params ChainParams
+	err = json.Unmarshal(chainParams, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	baseCfgBz, err := store.Get(BaseConfigKey())
+	if err != nil {
+		return nil, err
+	}
+
+	baseCfgBz, err = secrets.Open(baseCfgBz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the base config: %s", err)
+	}
+
+	var baseConfig BaseConfig
+	err = json.Unmarshal(baseCfgBz, &baseConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	config := Config{
+		BaseConfig:  baseConfig,
+		ChainParams: params,
+	}
+
+	return NewFabricChain(config, store)
+}
+
+// GetChainID implements AppChainI
+func (f *FabricChain) GetChainID() string {
+	return f.ChainID
+}
+
+// Start implements AppChainI
+func (f *FabricChain) Start(handler core.InterchainRequestHandler) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if !f.done {
+		return fmt.Errorf("chain %s has been started", f.ChainID)
+	}
+
+	eventClient, err := f.newEventClient()
+	if err != nil {
+		return fmt.Errorf("failed to init the fabric event client: %s", err)
+	}
+
+	reg, events, err := eventClient.RegisterBlockEvent()
+	if err != nil {
+		return fmt.Errorf("failed to register the block event: %s", err)
+	}
+
+	f.done = false
+	f.stop = make(chan struct{})
+	f.handler = handler
+
+	go f.listen(eventClient, reg, events, f.stop)
+
+	logging.Logger.Infof("chain %s started", f.ChainID)
+
+	return nil
+}
+
+// Stop implements AppChainI
+func (f *FabricChain) Stop() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	logging.Logger.Infof("stopping chain %s", f.ChainID)
+
+	if !f.done {
+		f.done = true
+		close(f.stop)
+	}
+
+	return nil
+}
+
+func (f *FabricChain) Close() {
+	_ = f.Stop()
+	f.sdk.Close()
+}
+
+// GetHeight implements AppChainI
+func (f *FabricChain) GetHeight() int64 {
+	info, err := f.ledgerClient.QueryInfo()
+	if err != nil {
+		return f.lastBlock
+	}
+
+	return int64(info.BCI.Height)
+}
+
+// GetActiveNode implements AppChainI
+func (f *FabricChain) GetActiveNode() string {
+	return f.Config.Nodes[0]
+}
+
+// SendResponse implements AppChainI
+func (f *FabricChain) SendResponse(requestID string, response core.ResponseI) error {
+	data := &txstore.RelayerResInfo{
+		RequestId: requestID,
+		TxStatus:  txstore.TxStatus_Success,
+		ErrMsg:    "",
+	}
+
+	defer func(d *txstore.RelayerResInfo) {
+		txstore.RelayerResponeRecord(d)
+	}(data)
+
+	res, _ := json.Marshal(serviceResponse{
+		RequestId: requestID,
+		ErrMsg:    response.GetErrMsg(),
+		Output:    response.GetOutput(),
+	})
+
+	// the execution returns once the tx is committed
+	fabres, err := f.channelClient.Execute(channel.Request{
+		ChaincodeID: f.Config.CrossChainCode,
+		Fcn:         "setResponse",
+		Args:        [][]byte{res},
+	})
+	if err != nil {
+		data.TxStatus = txstore.TxStatus_Error
+		data.ErrMsg = fmt.Sprintf("call fabric setResponse failed :%s", err)
+
+		return fmt.Errorf("failed to call setResponse on %s: %s", f.ChainID, err)
+	}
+
+	data.FromResTxId = string(fabres.TransactionID)
+
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseSent, data.FromResTxId, "")
+
+	if fabres.TxValidationCode != pb.TxValidationCode_VALID {
+		data.TxStatus = txstore.TxStatus_Error
+		data.ErrMsg = fmt.Sprintf("call fabric setResponse TxValidationCode is %s", fabres.TxValidationCode)
+
+		return fmt.Errorf("tx %s of setResponse invalid: %s", data.FromResTxId, fabres.TxValidationCode)
+	}
+
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseConfirmed, data.FromResTxId, "")
+
+	if len(response.GetErrMsg()) > 0 {
+		data.TxStatus = txstore.TxStatus_Error
+		data.ErrMsg = response.GetErrMsg()
+	}
+
+	return nil
+}
+
+// listen handles the block events until stopped
+func (f *FabricChain) listen(eventClient *event.Client, reg eventfab.Registration, events <-chan *eventfab.BlockEvent, stop chan struct{}) {
+	defer eventClient.Unregister(reg)
+
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				logging.Logger.Errorf("block events of chain %s closed", f.ChainID)
+				return
+			}
+
+			f.parseInterchainEventsFromBlock(e)
+			f.updateCheckpoint(e.Block.Header.Number)
+
+		case <-stop:
+			logging.Logger.Infof("chain %s stopped", f.ChainID)
+			return
+		}
+	}
+}
+
+// newEventClient creates the event client which seeks from the block to start from
+func (f *FabricChain) newEventClient() (*event.Client, error) {
+	opts := []event.ClientOption{event.WithBlockEvents()}
+
+	fromBlock, ok, err := f.loadStartBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		logging.Logger.Infof("chain %s starts from block %d", f.ChainID, fromBlock)
+		opts = append(opts, event.WithSeekType(seek.FromBlock), event.WithBlockNum(fromBlock))
+	}
+
+	return event.New(f.channelProvider, opts...)
+}
+
+// loadStartBlock determines the block to start from
+// The events resume from the block next to the checkpoint unless the chain params specify
+// a greater start block
+func (f *FabricChain) loadStartBlock() (uint64, bool, error) {
+	var fromBlock uint64
+	found := false
+
+	checkpoint, err := f.store.GetInt64(CheckpointKey(f.ChainID, f.Config.ChannelID))
+	if err != nil && err != store.ErrNotFound {
+		return 0, false, fmt.Errorf("failed to load the checkpoint of chain %s: %s", f.ChainID, err)
+	}
+
+	if err == nil {
+		fromBlock = uint64(checkpoint) + 1
+		found = true
+	}
+
+	if f.Config.StartBlock != nil && (!found || *f.Config.StartBlock > fromBlock) {
+		fromBlock = *f.Config.StartBlock
+		found = true
+	}
+
+	return fromBlock, found, nil
+}
+
+// updateCheckpoint records the given block number as processed
+func (f *FabricChain) updateCheckpoint(blockNumber uint64) {
+	f.lastBlock = int64(blockNumber)
+	metrics.SetScannedHeight(f.ChainID, f.lastBlock)
+
+	err := f.store.SetInt64(CheckpointKey(f.ChainID, f.Config.ChannelID), f.lastBlock)
+	if err != nil {
+		logging.Logger.Errorf("failed to update the checkpoint of chain %s: %s", f.ChainID, err)
+	}
+}
+
+// parseInterchainEventsFromBlock parses the requests written by the cross-chain chaincode in the block
+func (f *FabricChain) parseInterchainEventsFromBlock(e *eventfab.BlockEvent) {
+	block, err := blockparser.ParseBlock(e.Block)
+	if err != nil {
+		logging.Logger.Errorf("failed to parse block %d of chain %s: %s", e.Block.Header.Number, f.ChainID, err)
+		return
+	}
+
+	for _, trans := range block.Transactions {
+		if trans.Status != int(pb.TxValidationCode_VALID) {
+			continue
+		}
+
+		for _, rw := range trans.NameSpaceSets {
+			if rw.NameSpace != f.Config.CrossChainCode {
+				continue
+			}
+
+			for _, w := range rw.Writes {
+				if w.Key != KeyCallService {
+					continue
+				}
+
+				request, err := f.buildInterchainRequest(w.Value, trans)
+				if err != nil {
+					logging.Logger.Errorf("failed to build the request %s of chain %s: %s", w.Value, f.ChainID, err)
+					continue
+				}
+
+				_ = f.handler(f.ChainID, request, trans.TxId)
+			}
+		}
+	}
+}
+
+// buildInterchainRequest builds an interchain request from the request kept by the cross-chain chaincode
+func (f *FabricChain) buildInterchainRequest(requestID string, trans *blockparser.TransactionInfo) (core.InterchainRequest, error) {
+	info, err := f.getServiceInfo(requestID)
+	if err != nil {
+		return core.InterchainRequest{}, err
+	}
+
+	if info.Request == nil {
+		return core.InterchainRequest{}, fmt.Errorf("request %s not found", requestID)
+	}
+
+	var endpointInfo EndpointInfo
+	err = json.Unmarshal([]byte(info.Request.EndpointInfo), &endpointInfo)
+	if err != nil {
+		return core.InterchainRequest{}, fmt.Errorf("failed to decode endpointInfo: %s", err)
+	}
+
+	return core.InterchainRequest{
+		ID:              info.Request.RequestId,
+		SourceChainID:   f.ChainID,
+		DestChainID:     endpointInfo.DestChainID,
+		DestSubChainID:  endpointInfo.DestSubChainID,
+		DestChainType:   endpointInfo.DestChainType,
+		EndpointAddress: endpointInfo.EndpointAddress,
+		EndpointType:    endpointInfo.EndpointType,
+		Method:          info.Request.Method,
+		CallData:        convCallData(info.Request.CallData),
+		TxHash:          trans.TxId,
+		Sender:          trans.CreateName,
+		Timeout:         f.Config.Timeout,
+		Timestamp:       trans.TimeSpanSec*1000 + trans.TimeSpanNsec/1000000,
+	}, nil
+}
+
+// getServiceInfo queries the request and response of the given request ID from the cross-chain chaincode
+func (f *FabricChain) getServiceInfo(requestID string) (*serviceCallInfo, error) {
+	fabres, err := f.channelClient.Query(channel.Request{
+		ChaincodeID: f.Config.CrossChainCode,
+		Fcn:         "query",
+		Args:        [][]byte{[]byte(requestID)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the service info: %s", err)
+	}
+
+	info := &serviceCallInfo{}
+	err = json.Unmarshal(fabres.Payload, info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the service info: %s", err)
+	}
+
+	return info, nil
+}
+
+// convCallData decodes the call data in hex, base64 or as is in order
+func convCallData(data string) []byte {
+	bytes, err := hexutil.Decode(data)
+	if err == nil {
+		return bytes
+	}
+
+	bytes, err = base64.StdEncoding.DecodeString(data)
+	if err == nil {
+		return bytes
+	}
+
+	return []byte(data)
+}
+
+// storeChainParams stores the chain params
+func (f *FabricChain) storeChainParams() error {
+	bz, err := json.Marshal(f.Config.ChainParams)
+	if err != nil {
+		return err
+	}
+
+	bz, err = secrets.Seal(bz)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the chain params: %s", err)
+	}
+
+	return f.store.Set(ChainParamsKey(f.ChainID), bz)
+}
+
+func (f *FabricChain) storeChainID() error {
+	chainIDsbz, err := f.store.Get([]byte("chainIDs"))
+	if err != nil {
+		return err
+	}
+	chainIDs := map[string]string{}
+	err = json.Unmarshal(chainIDsbz, &chainIDs)
+	if err != nil {
+		return err
+	}
+	chainIDs[f.ChainID] = ChainType
+	bz, _ := json.Marshal(chainIDs)
+	return f.store.Set([]byte("chainIDs"), bz)
+}
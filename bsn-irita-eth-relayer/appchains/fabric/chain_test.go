@@ -0,0 +1,72 @@
+package fabric
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"relayer/store"
+)
+
+func TestLoadStartBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fabric-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := &FabricChain{ChainID: "1", store: s}
+	chain.Config.ChannelID = "mychannel"
+
+	if _, ok, err := chain.loadStartBlock(); err != nil || ok {
+		t.Fatalf("expected to start from the newest block, got %v, %v", ok, err)
+	}
+
+	if err := s.SetInt64(CheckpointKey(chain.ChainID, chain.Config.ChannelID), 100); err != nil {
+		t.Fatal(err)
+	}
+
+	fromBlock, ok, err := chain.loadStartBlock()
+	if err != nil || !ok || fromBlock != 101 {
+		t.Fatalf("expected to resume from 101, got %d, %v, %v", fromBlock, ok, err)
+	}
+
+	startBlock := uint64(50)
+	chain.Config.StartBlock = &startBlock
+
+	fromBlock, _, _ = chain.loadStartBlock()
+	if fromBlock != 101 {
+		t.Fatalf("expected the checkpoint to take precedence, got %d", fromBlock)
+	}
+
+	startBlock = 200
+
+	fromBlock, _, _ = chain.loadStartBlock()
+	if fromBlock != 200 {
+		t.Fatalf("expected to start from 200, got %d", fromBlock)
+	}
+}
+
+func TestValidateChainParams(t *testing.T) {
+	params := ChainParams{ChainID: 1, ChannelID: "mychannel", CrossChainCode: "cc_cross", Nodes: []string{"peer0.org1.example.com"}}
+	if err := ValidateChainParams(params); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := params
+	invalid.CrossChainCode = ""
+	if err := ValidateChainParams(invalid); err == nil {
+		t.Fatal("expected an error for the missing cross-chain chaincode")
+	}
+
+	invalid = params
+	invalid.Nodes = nil
+	if err := ValidateChainParams(invalid); err == nil {
+		t.Fatal("expected an error for the missing nodes")
+	}
+}
@@ -0,0 +1,69 @@
+package fabric
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/spf13/viper"
+
+	"relayer/appchains/fabric/redconfig"
+	"relayer/appchains/fabric/redconfig/configbackend"
+	cfg "relayer/config"
+	"relayer/logging"
+)
+
+const (
+	Prefix = "fabric"
+
+	// base config
+	SdkConfig   = "sdk_config"
+	MspUserName = "msp_user_name"
+	OrgName     = "org_name"
+)
+
+// BaseConfig defines the base config
+type BaseConfig struct {
+	SdkConfig   string `json:"sdk_config"`    // path of the Fabric SDK config file
+	MspUserName string `json:"msp_user_name"` // MSP user to sign the txs with
+	OrgName     string `json:"org_name"`      // organization of the MSP user
+}
+
+func (bc *BaseConfig) PrintConfig() {
+	logging.Logger.Infof("fabric sdk config: %s, org: %s, user: %s", bc.SdkConfig, bc.OrgName, bc.MspUserName)
+}
+
+// Config defines the specific chain config
+type Config struct {
+	BaseConfig
+	ChainParams
+}
+
+// NewBaseConfig constructs a new BaseConfig instance from viper
+func NewBaseConfig(v *viper.Viper) (*BaseConfig, error) {
+	config := &BaseConfig{
+		SdkConfig:   v.GetString(cfg.GetConfigKey(Prefix, SdkConfig)),
+		MspUserName: v.GetString(cfg.GetConfigKey(Prefix, MspUserName)),
+		OrgName:     v.GetString(cfg.GetConfigKey(Prefix, OrgName)),
+	}
+
+	if len(config.SdkConfig) == 0 {
+		return nil, fmt.Errorf("%s must be specified", cfg.GetConfigKey(Prefix, SdkConfig))
+	}
+
+	return config, nil
+}
+
+// BuildSdkConfig builds the Fabric SDK config provider of the given config
+// The channel of the chain is set into the config file with the first node as the peer
+func BuildSdkConfig(config Config) core.ConfigProvider {
+	ch := configbackend.ChannelConfig{ChannelId: config.ChannelID, PeerName: config.Nodes[0]}
+
+	return redconfig.FromFile(config.SdkConfig, []redconfig.SetOption{redconfig.SetChannel(&ch)})
+}
+
+// ValidBaseConfig validates if the given bytes is valid BaseConfig
+func ValidateBaseConfig(baseCfg []byte) error {
+	var baseConfig BaseConfig
+	return json.Unmarshal(baseCfg, &baseConfig)
+}
@@ -0,0 +1,37 @@
+package fabric
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+
+	"relayer/logging"
+)
+
+// CallContract implements ContractCallerI
+// The endpoint address is the chaincode name and the call data is passed as the only argument
+func (f *FabricChain) CallContract(endpointAddress string, method string, callData []byte) (output string, txHash string, err error) {
+	if len(endpointAddress) == 0 {
+		return "", "", fmt.Errorf("chaincode must be specified")
+	}
+
+	// the execution returns once the tx is committed
+	fabres, err := f.channelClient.Execute(channel.Request{
+		ChaincodeID: endpointAddress,
+		Fcn:         method,
+		Args:        [][]byte{callData},
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to call %s of %s: %s", method, endpointAddress, err)
+	}
+
+	txHash = string(fabres.TransactionID)
+	logging.Logger.Infof("chaincode %s called on %s, method: %s, tx: %s", endpointAddress, f.ChainID, method, txHash)
+
+	if fabres.TxValidationCode != pb.TxValidationCode_VALID {
+		return string(fabres.Payload), txHash, fmt.Errorf("tx %s of calling %s invalid: %s", txHash, method, fabres.TxValidationCode)
+	}
+
+	return string(fabres.Payload), txHash, nil
+}
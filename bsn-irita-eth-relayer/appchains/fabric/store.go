@@ -0,0 +1,46 @@
+package fabric
+
+import (
+	"fmt"
+
+	"relayer/secrets"
+	"relayer/store"
+)
+
+const (
+	StorePrefix = ChainType
+
+	KeyBaseConfig        = "baseconfig"
+	KeyPrefixChainParams = "params"
+	KeyPrefixCheckpoint  = "checkpoint"
+)
+
+// BaseConfigKey returns the key for the Fabric base config
+func BaseConfigKey() []byte {
+	return []byte(fmt.Sprintf("%s:%s", StorePrefix, KeyBaseConfig))
+}
+
+// ChainParamsKey returns the key for the params of the given chain
+func ChainParamsKey(chainID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s", StorePrefix, KeyPrefixChainParams, chainID))
+}
+
+// CheckpointKey returns the key for the last processed block of the specified chain and channel
+func CheckpointKey(chainID string, channelID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s:%s", StorePrefix, KeyPrefixCheckpoint, chainID, channelID))
+}
+
+// StoreBaseConfig stores the base config
+func StoreBaseConfig(store *store.Store, baseConfig []byte) error {
+	err := ValidateBaseConfig(baseConfig)
+	if err != nil {
+		return err
+	}
+
+	bz, err := secrets.Seal(baseConfig)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the base config: %s", err)
+	}
+
+	return store.Set(BaseConfigKey(), bz)
+}
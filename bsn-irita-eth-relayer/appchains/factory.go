@@ -7,21 +7,28 @@ import (
 
 	"github.com/spf13/viper"
 
+	"relayer/appchains/adapter"
 	"relayer/appchains/eth"
+	"relayer/appchains/fabric"
+	"relayer/appchains/fisco"
 	"relayer/config"
-	// "relayer/appchains/ethereum"
 	"relayer/core"
 	"relayer/store"
 )
 
+// ChainTypeOPB is the chain type served by the adapter server of the OPB relayer
+// The OPB SDK is not available to this module, so OPB chains are adapter chains
+// registered as the opb type, whose params are passed through to the OPB relayer
+const ChainTypeOPB = "opb"
+
 // AppChainFactory defines an application chain factory
 type AppChainFactory struct {
 	Store *store.Store // store
 }
 
-// AppChainFactory defines an application chain factory
+// BaseConfigFactory defines a base config factory
 type BaseConfigFactory struct {
-	config *viper.Viper// store
+	config *viper.Viper // config
 }
 
 // NewAppChainFactory constructs a new application chain factory
@@ -31,7 +38,7 @@ func NewAppChainFactory(store *store.Store) *AppChainFactory {
 	}
 }
 
-// NewAppChainFactory constructs a new application chain factory
+// NewBaseConfigFactory constructs a new base config factory
 func NewBaseConfigFactory(v *viper.Viper) *BaseConfigFactory {
 	return &BaseConfigFactory{
 		config: v,
@@ -41,97 +48,124 @@ func NewBaseConfigFactory(v *viper.Viper) *BaseConfigFactory {
 // BuildAppChain implements AppChainFactoryI
 func (f *AppChainFactory) BuildAppChain(chainType string, chainParams []byte) (core.AppChainI, error) {
 	switch strings.ToLower(chainType) {
-	case "eth":
+	case eth.ChainType:
 		return eth.BuildEthChain(chainParams, f.Store)
 
-	case "fabric":
-		return nil, nil
+	case fisco.ChainType:
+		return fisco.BuildFISCOChain(chainParams, f.Store)
 
-	case "fisco":
-		return nil, nil
+	case fabric.ChainType:
+		return fabric.BuildFabricChain(chainParams, f.Store)
+
+	case adapter.ChainType, ChainTypeOPB:
+		return adapter.BuildAdapterChain(strings.ToLower(chainType), chainParams, f.Store)
 
 	default:
-		return nil, fmt.Errorf("application chain %s not supported", chainType)
+		return nil, errNotSupported(chainType)
 	}
 }
 
 // GetChainID implements AppChainFactoryI
 func (f *AppChainFactory) GetChainID(chainType string, chainParams []byte) (chainID string, err error) {
 	switch strings.ToLower(chainType) {
-	case "eth":
+	case eth.ChainType:
 		return eth.GetChainIDFromBytes(chainParams)
 
-	case "fabric":
-		return "", nil
+	case fisco.ChainType:
+		return fisco.GetChainIDFromBytes(chainParams)
+
+	case fabric.ChainType:
+		return fabric.GetChainIDFromBytes(chainParams)
 
-	case "fisco":
-		return "", nil
+	case adapter.ChainType, ChainTypeOPB:
+		return adapter.GetChainIDFromBytes(chainParams)
 
 	default:
-		return "", fmt.Errorf("application chain %s not supported", chainType)
+		return "", errNotSupported(chainType)
 	}
 }
 
 // StoreBaseConfig implements AppChainFactoryI
 func (f *AppChainFactory) StoreBaseConfig(chainType string, baseConfig []byte) error {
 	switch strings.ToLower(chainType) {
-	case "eth":
+	case eth.ChainType:
 		return eth.StoreBaseConfig(f.Store, baseConfig)
 
-	case "fabric":
-		return nil
+	case fisco.ChainType:
+		return fisco.StoreBaseConfig(f.Store, baseConfig)
+
+	case fabric.ChainType:
+		return fabric.StoreBaseConfig(f.Store, baseConfig)
 
-	case "fisco":
-		return nil
+	case adapter.ChainType, ChainTypeOPB:
+		return adapter.StoreBaseConfig(f.Store, strings.ToLower(chainType), baseConfig)
 
 	default:
-		return fmt.Errorf("application chain %s not supported", chainType)
+		return errNotSupported(chainType)
 	}
 }
 
-// StoreBaseConfig implements AppChainFactoryI
+// DeleteChainConfig implements AppChainFactoryI
 func (f *AppChainFactory) DeleteChainConfig(chainType string, chainID string) error {
 	switch strings.ToLower(chainType) {
-	case "eth":
-		err := f.Store.Delete(eth.ChainParamsKey(chainID))
-		if err != nil{
-			return err
-		}
-		chainIDsbz, _ := f.Store.Get([]byte("chainIDs"))
-		chainIDs:= map[string]string{}
-		json.Unmarshal(chainIDsbz, &chainIDs)
-		delete(chainIDs, chainID)
-		bz, err := json.Marshal(chainIDs)
-		err = f.Store.Set([]byte("chainIDs"), bz)
-		if err != nil{
-			return err
-		}
-		return f.Store.Delete(eth.ChainParamsKey(chainID))
-		return nil
-
-	case "fabric":
-		return nil
-
-	case "fisco":
-		return nil
+	case eth.ChainType:
+		return f.deleteChainConfig(chainID, eth.ChainParamsKey(chainID))
+
+	case fisco.ChainType:
+		return f.deleteChainConfig(chainID, fisco.ChainParamsKey(chainID))
+
+	case fabric.ChainType:
+		return f.deleteChainConfig(chainID, fabric.ChainParamsKey(chainID))
+
+	case adapter.ChainType, ChainTypeOPB:
+		return f.deleteChainConfig(chainID, adapter.ChainParamsKey(strings.ToLower(chainType), chainID))
+
 	default:
-		return fmt.Errorf("application chain %s not supported", chainType)
+		return errNotSupported(chainType)
 	}
 }
 
-// StoreBaseConfig implements AppChainFactoryI
+// deleteChainConfig deletes the chain params and removes the chain from the persisted chain IDs
+func (f *AppChainFactory) deleteChainConfig(chainID string, paramsKey []byte) error {
+	err := f.Store.Delete(paramsKey)
+	if err != nil {
+		return err
+	}
+
+	chainIDsbz, _ := f.Store.Get([]byte("chainIDs"))
+	chainIDs := map[string]string{}
+	json.Unmarshal(chainIDsbz, &chainIDs)
+	delete(chainIDs, chainID)
+
+	bz, err := json.Marshal(chainIDs)
+	if err != nil {
+		return err
+	}
+
+	return f.Store.Set([]byte("chainIDs"), bz)
+}
+
+// NewBaseConfig constructs the base config of the given chain type from its own config section
 func (bc *BaseConfigFactory) NewBaseConfig(chainType string) (config.BaseConfigI, error) {
 	switch strings.ToLower(chainType) {
-	case "eth":
+	case eth.ChainType:
 		return eth.NewBaseConfig(bc.config), nil
 
-	case "fabric":
-		return nil, nil
+	case fisco.ChainType:
+		return fisco.NewBaseConfig(bc.config)
+
+	case fabric.ChainType:
+		return fabric.NewBaseConfig(bc.config)
 
-	case "fisco":
-		return nil, nil
+	case adapter.ChainType, ChainTypeOPB:
+		return adapter.NewBaseConfig(bc.config, strings.ToLower(chainType)), nil
 
 	default:
-		return nil, fmt.Errorf("application chain %s not supported", chainType)
+		return nil, errNotSupported(chainType)
 	}
 }
+
+// errNotSupported returns the error for the chain type which can not be hosted
+func errNotSupported(chainType string) error {
+	return fmt.Errorf("application chain %s not supported", chainType)
+}
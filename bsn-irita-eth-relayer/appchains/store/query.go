@@ -0,0 +1,278 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"relayer/common/ledger"
+)
+
+const (
+	DefaultPageSize = 20  // default number of the txs per page
+	MaxPageSize     = 100 // maximum number of the txs per page
+
+	timeLayout = "2006-01-02 15:04:05"
+
+	txColumns = "request_id, from_chainid, from_tx, hub_req_tx, ic_request_id, to_chainid, to_tx, hub_res_tx, from_res_tx, " +
+		"tx_status, tx_time, tx_createtime, error, source_service, tx_state"
+)
+
+// lookup columns of the cross-chain tx
+const (
+	ColumnRequestID   = "request_id"
+	ColumnIcRequestID = "ic_request_id"
+	ColumnFromTx      = "from_tx"
+)
+
+// CrossChainTx defines the record of the cross-chain tx ledger
+type CrossChainTx struct {
+	RequestID     string `json:"request_id"`
+	FromChainID   string `json:"from_chain_id"`
+	FromTx        string `json:"from_tx"`
+	HubReqTx      string `json:"hub_req_tx"`
+	IcRequestID   string `json:"ic_request_id"`
+	ToChainID     string `json:"to_chain_id"`
+	ToTx          string `json:"to_tx,omitempty"`
+	HubResTx      string `json:"hub_res_tx,omitempty"`
+	FromResTx     string `json:"from_res_tx,omitempty"`
+	TxStatus      int    `json:"tx_status"`
+	TxTime        string `json:"tx_time,omitempty"`
+	TxCreateTime  string `json:"tx_create_time"`
+	Error         string `json:"error,omitempty"`
+	SourceService int    `json:"source_service"`
+	TxState       string `json:"tx_state"`
+
+	Transitions []TxTransition `json:"transitions,omitempty"`
+}
+
+// TxFilter defines the filter to list the cross-chain txs
+type TxFilter struct {
+	ChainID   string    // source or destination chain ID, all if empty
+	Status    int       // tx status, all if negative
+	State     string    // lifecycle state, all if empty
+	StartTime time.Time // lower bound of the creation time, inclusive
+	EndTime   time.Time // upper bound of the creation time, exclusive
+	Page      int       // page number starting from 1
+	Size      int       // page size
+}
+
+// StatusCount defines the number of the cross-chain txs in the status
+type StatusCount struct {
+	TxStatus int   `json:"tx_status"`
+	Count    int64 `json:"count"`
+}
+
+// StateCount defines the number of the cross-chain txs in the lifecycle state
+type StateCount struct {
+	TxState string `json:"tx_state"`
+	Count   int64  `json:"count"`
+}
+
+// GetCrossChainTxs retrieves the cross-chain txs whose lookup column equals the given value
+// The records of both the relayer and the provider are returned
+func GetCrossChainTxs(column string, value string) ([]CrossChainTx, error) {
+	switch column {
+	case ColumnRequestID, ColumnIcRequestID, ColumnFromTx:
+	default:
+		return nil, fmt.Errorf("invalid lookup column: %s", column)
+	}
+
+	querySql := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ? ORDER BY funique_id", txColumns, _TabName_cc_Tx, column)
+
+	return queryCrossChainTxs(querySql, value)
+}
+
+// ListCrossChainTxs lists the cross-chain txs by the given filter along with the total number
+func ListCrossChainTxs(filter TxFilter) ([]CrossChainTx, int64, error) {
+	where, args := buildTxFilter(filter)
+
+	total, err := queryCount(fmt.Sprintf("SELECT COUNT(*) FROM %s%s", _TabName_cc_Tx, where), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, size := normalizePage(filter.Page, filter.Size)
+
+	querySql := fmt.Sprintf(
+		"SELECT %s FROM %s%s ORDER BY funique_id DESC LIMIT %d OFFSET %d",
+		txColumns, _TabName_cc_Tx, where, size, (page-1)*size,
+	)
+
+	txs, err := queryCrossChainTxs(querySql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return txs, total, nil
+}
+
+// CountCrossChainTxsByStatus aggregates the cross-chain txs by status with the given filter
+// The status and the pagination of the filter are ignored
+func CountCrossChainTxsByStatus(filter TxFilter) ([]StatusCount, error) {
+	filter.Status = -1
+	where, args := buildTxFilter(filter)
+
+	querySql := fmt.Sprintf("SELECT tx_status, COUNT(*) FROM %s%s GROUP BY tx_status ORDER BY tx_status", _TabName_cc_Tx, where)
+
+	list, err := ledger.Query(func(rows *sql.Rows) (interface{}, error) {
+		var count StatusCount
+		err := rows.Scan(&count.TxStatus, &count.Count)
+		return count, err
+	}, querySql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]StatusCount, len(list))
+	for i, item := range list {
+		counts[i] = item.(StatusCount)
+	}
+
+	return counts, nil
+}
+
+// CountCrossChainTxsByState aggregates the cross-chain txs by lifecycle state with the given filter
+// The state and the pagination of the filter are ignored
+func CountCrossChainTxsByState(filter TxFilter) ([]StateCount, error) {
+	filter.State = ""
+	where, args := buildTxFilter(filter)
+
+	querySql := fmt.Sprintf("SELECT tx_state, COUNT(*) FROM %s%s GROUP BY tx_state ORDER BY tx_state", _TabName_cc_Tx, where)
+
+	list, err := ledger.Query(func(rows *sql.Rows) (interface{}, error) {
+		var count StateCount
+		err := rows.Scan(&count.TxState, &count.Count)
+		return count, err
+	}, querySql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]StateCount, len(list))
+	for i, item := range list {
+		counts[i] = item.(StateCount)
+	}
+
+	return counts, nil
+}
+
+// buildTxFilter builds the where clause and the args of the given filter
+func buildTxFilter(filter TxFilter) (string, []interface{}) {
+	conds := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if len(filter.ChainID) > 0 {
+		conds = append(conds, "(from_chainid = ? OR to_chainid = ?)")
+		args = append(args, filter.ChainID, filter.ChainID)
+	}
+
+	if filter.Status >= 0 {
+		conds = append(conds, "tx_status = ?")
+		args = append(args, filter.Status)
+	}
+
+	if len(filter.State) > 0 {
+		conds = append(conds, "tx_state = ?")
+		args = append(args, filter.State)
+	}
+
+	if !filter.StartTime.IsZero() {
+		conds = append(conds, "tx_createtime >= ?")
+		args = append(args, filter.StartTime.Format(timeLayout))
+	}
+
+	if !filter.EndTime.IsZero() {
+		conds = append(conds, "tx_createtime < ?")
+		args = append(args, filter.EndTime.Format(timeLayout))
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// normalizePage returns the valid page number and size
+func normalizePage(page int, size int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+
+	if size < 1 {
+		size = DefaultPageSize
+	}
+
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+
+	return page, size
+}
+
+func queryCount(querySql string, args ...interface{}) (int64, error) {
+	list, err := ledger.Query(func(rows *sql.Rows) (interface{}, error) {
+		var count int64
+		err := rows.Scan(&count)
+		return count, err
+	}, querySql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(list) == 0 {
+		return 0, nil
+	}
+
+	return list[0].(int64), nil
+}
+
+func queryCrossChainTxs(querySql string, args ...interface{}) ([]CrossChainTx, error) {
+	list, err := ledger.Query(scanCrossChainTx, querySql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]CrossChainTx, len(list))
+	for i, item := range list {
+		txs[i] = item.(CrossChainTx)
+	}
+
+	return txs, nil
+}
+
+// scanCrossChainTx scans the cross-chain tx from the row, the nullable columns are read as empty
+func scanCrossChainTx(rows *sql.Rows) (interface{}, error) {
+	var tx CrossChainTx
+	var toTx, fromResTx, txTime, errMsg sql.NullString
+
+	err := rows.Scan(
+		&tx.RequestID,
+		&tx.FromChainID,
+		&tx.FromTx,
+		&tx.HubReqTx,
+		&tx.IcRequestID,
+		&tx.ToChainID,
+		&toTx,
+		&tx.HubResTx,
+		&fromResTx,
+		&tx.TxStatus,
+		&txTime,
+		&tx.TxCreateTime,
+		&errMsg,
+		&tx.SourceService,
+		&tx.TxState,
+	)
+	if err != nil {
+		return tx, err
+	}
+
+	tx.ToTx = toTx.String
+	tx.FromResTx = fromResTx.String
+	tx.TxTime = txTime.String
+	tx.Error = errMsg.String
+
+	return tx, nil
+}
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"relayer/common/ledger"
+)
+
+
+func TestRelayerResponeRecord(t *testing.T) {
+	data :=&RelayerResInfo{
+		RequestId: "requestID",
+		TxStatus: TxStatus_Success,
+		ErrMsg: "",
+	}
+	defer func(d *RelayerResInfo) {
+
+		fmt.Println(d.RequestId)
+	}(data)
+
+
+	data.RequestId = "123456"
+
+}
+func TestBuildTxFilter(t *testing.T) {
+	where, args := buildTxFilter(TxFilter{Status: -1})
+	if where != "" || len(args) != 0 {
+		t.Fatalf("expected no condition, got %q %v", where, args)
+	}
+
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.Local)
+	where, args = buildTxFilter(TxFilter{ChainID: "eth-1", Status: TxStatus_Error, StartTime: start})
+
+	expected := " WHERE (from_chainid = ? OR to_chainid = ?) AND tx_status = ? AND tx_createtime >= ?"
+	if where != expected {
+		t.Fatalf("expected %q, got %q", expected, where)
+	}
+
+	if len(args) != 4 || args[3] != "2021-06-01 00:00:00" {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func TestNormalizePage(t *testing.T) {
+	if page, size := normalizePage(0, 0); page != 1 || size != DefaultPageSize {
+		t.Fatalf("unexpected page %d and size %d", page, size)
+	}
+
+	if _, size := normalizePage(2, 1000); size != MaxPageSize {
+		t.Fatalf("expected size to be capped to %d, got %d", MaxPageSize, size)
+	}
+}
+
+func TestCanTransit(t *testing.T) {
+	transitions := []struct {
+		from  string
+		to    string
+		valid bool
+	}{
+		{TxState_Detected, TxState_Submitted, true},
+		{TxState_Submitted, TxState_Expired, true},
+		{TxState_Expired, TxState_ResponseSent, true},
+		{TxState_ResponseSent, TxState_ResponseSent, true},
+		{TxState_Failed, TxState_ResponseSent, true},
+		{"", TxState_ResponseConfirmed, true},
+		{TxState_Detected, TxState_ResponseConfirmed, false},
+		{TxState_ResponseConfirmed, TxState_Failed, false},
+		{TxState_Submitted, TxState_Detected, false},
+	}
+
+	for _, transition := range transitions {
+		if CanTransit(transition.from, transition.to) != transition.valid {
+			t.Fatalf("expected the transition from %q to %q to be valid: %v", transition.from, transition.to, transition.valid)
+		}
+	}
+}
+
+func TestTxStateCondition(t *testing.T) {
+	where, args := txStateCondition("req1", TxState_ResponseConfirmed)
+
+	expected := "request_id = ? and source_service = ? and tx_state in (?,?)"
+	if where != expected {
+		t.Fatalf("expected %q, got %q", expected, where)
+	}
+
+	if len(args) != 4 || args[0] != "req1" || args[3] != TxState_ResponseSent {
+		t.Fatalf("unexpected args: %v", args)
+	}
+
+	where, _ = txStateCondition("req1", TxState_Detected)
+	if where != "request_id = ? and source_service = ? and tx_state in (NULL)" {
+		t.Fatalf("expected no state to be transited to detected, got %q", where)
+	}
+}
+
+func TestSQLiteLedger(t *testing.T) {
+	if err := InitLedger(ledger.Config{Driver: ledger.DriverSQLite, DSN: ":memory:"}); err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	InitRelayerTransRecord("req1", "eth-1", "0x01", "fisco-1", "", "", TxStatus_Unknow, "")
+	RelayerSubmitRecord("req1", "hubtx", "icreq1")
+	RecordTxTransition("req1", TxState_HubResponded, "", "")
+
+	// invalid transition is ignored
+	RecordTxTransition("req1", TxState_ResponseConfirmed, "0x02", "")
+
+	RelayerResponeRecord(&RelayerResInfo{RequestId: "req1", FromResTxId: "0x02", TxStatus: TxStatus_Success})
+
+	txs, total, err := ListCrossChainTxs(TxFilter{ChainID: "eth-1", Status: TxStatus_Success, State: TxState_HubResponded})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 1 || len(txs) != 1 || txs[0].IcRequestID != "icreq1" || txs[0].FromResTx != "0x02" {
+		t.Fatalf("unexpected txs: %d %+v", total, txs)
+	}
+
+	transitions, err := GetTxTransitions("req1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transitions) != 3 || transitions[2].State != TxState_HubResponded {
+		t.Fatalf("unexpected transitions: %+v", transitions)
+	}
+}
+
+func TestMigrateLegacyLedger(t *testing.T) {
+	config := ledger.Config{Driver: ledger.DriverSQLite, DSN: ":memory:"}
+	if err := ledger.Init(config); err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	// the table created before the lifecycle state is tracked
+	legacy := strings.Replace(fmt.Sprintf(_Create_CrossChain_Tx_Sql_Generic, "INTEGER PRIMARY KEY AUTOINCREMENT", "datetime"), ",\n  tx_state varchar(32) NOT NULL DEFAULT ''", "", 1)
+	if _, _, err := ledger.Exec(legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, _ := ledger.ColumnExists(_TabName_cc_Tx, "tx_state"); exists {
+		t.Fatal("expected the legacy table without the tx_state column")
+	}
+
+	applied, err := ledger.Migrate(Migrations(config.Driver))
+	if err != nil || len(applied) != 4 {
+		t.Fatalf("expected all the migrations to be applied, got %v %v", applied, err)
+	}
+
+	if exists, err := ledger.ColumnExists(_TabName_cc_Tx, "tx_state"); err != nil || !exists {
+		t.Fatalf("expected the tx_state column to be added, got %v %v", exists, err)
+	}
+
+	for _, index := range txIndexes {
+		if exists, err := ledger.IndexExists(_TabName_cc_Tx, index.name); err != nil || !exists {
+			t.Fatalf("expected the index %s to be created, got %v %v", index.name, exists, err)
+		}
+	}
+
+	if applied, err = ledger.Migrate(Migrations(config.Driver)); err != nil || len(applied) != 0 {
+		t.Fatalf("expected no migration to be applied again, got %v %v", applied, err)
+	}
+}
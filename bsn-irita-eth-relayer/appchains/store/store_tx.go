@@ -2,7 +2,7 @@ package store
 
 import (
 	"fmt"
-	"relayer/common/mysql"
+	"relayer/common/ledger"
 	"relayer/logging"
 	"time"
 )
@@ -58,10 +58,11 @@ func InitRelayerTransRecord(
 		"tx_createtime, " +
 		"tx_status, " +
 		"error, " +
-		"source_service ) "+
-		"VALUES ( ?, ?, ?,?, ?, ?, ?,?,?,?);", _TabName_cc_Tx)
+		"source_service, " +
+		"tx_state ) "+
+		"VALUES ( ?, ?, ?,?, ?, ?, ?,?,?,?,?);", _TabName_cc_Tx)
 
-	lastId, rows, err := mysql.Exec(insertsql,
+	lastId, rows, err := ledger.Exec(insertsql,
 		requestId,
 		fromChainId,
 		fromTxId,
@@ -71,12 +72,14 @@ func InitRelayerTransRecord(
 		NowTime(),
 		txStatus,
 		errMsg,
-		source_service)
+		source_service,
+		TxState_Detected)
 
 	if err != nil {
 		logging.Logger.Errorf("Init Relayer trans record Failed :%s", err.Error())
 	} else {
 		logging.Logger.Infof("Init Relayer trans record  lastId:%d ;rows:%d ", lastId, rows)
+		insertTxTransition(requestId, TxState_Detected, fromTxId, errMsg)
 	}
 
 
@@ -98,7 +101,7 @@ func RelayerResponeRecord(data *RelayerResInfo) {
 	logging.Logger.Infof("set relayer callback record , requestId is %s,tx status is %d",data.RequestId,data.TxStatus)
 	sql :=fmt.Sprintf("update %s set from_res_tx = ? ,error = ? ,tx_time =? ,tx_status = ? where request_id = ? and source_service = %d",_TabName_cc_Tx,source_service)
 
-	lastId, rows, err := mysql.Exec(sql,
+	lastId, rows, err := ledger.Exec(sql,
 		data.FromResTxId,
 		data.ErrMsg,
 		NowTime(),
@@ -115,6 +118,18 @@ func RelayerResponeRecord(data *RelayerResInfo) {
 }
 
 
+// RelayerSubmitErrorRecord records the last error to submit the interchain request to the Hub
+// The tx status is left unknown as the submission is retried
+func RelayerSubmitErrorRecord(requestId string, errMsg string) {
+	logging.Logger.Infof("set relayer submit error record , requestId is %s", requestId)
+	sql := fmt.Sprintf("update %s set error = ? ,tx_time = ? where request_id = ? and source_service = %d", _TabName_cc_Tx, source_service)
+
+	if _, _, err := ledger.Exec(sql, errMsg, NowTime(), requestId); err != nil {
+		logging.Logger.Errorf("set relayer submit error record Failed :%s", err.Error())
+	}
+}
+
+
 //requestId ,to_chainid,ic_request_id ,to_tx,hub_res_tx ,tx_status,error,source_service
 
 //InitProviderTransRecord
@@ -134,7 +149,7 @@ func InitProviderTransRecord(requestId ,to_chainid,ic_request_id ,to_tx ,error s
 
 
 
-	lastId, rows, err := mysql.Exec(insertsql,
+	lastId, rows, err := ledger.Exec(insertsql,
 		requestId,
 		ic_request_id,
 		to_chainid,
@@ -142,7 +157,7 @@ func InitProviderTransRecord(requestId ,to_chainid,ic_request_id ,to_tx ,error s
 		NowTime(),
 		tx_status,
 		error,
-		source_service)
+		Source_Provider)
 
 	if err != nil {
 		logging.Logger.Errorf("Init Provider trans record Failed :%s", err.Error())
@@ -166,9 +181,9 @@ func ProviderCallBackTransRecord(data *ProviderResInfo )  {
 	if data.IcRequestId == "" {
 		return
 	}
-	sql :=fmt.Sprintf("update %s set hub_res_tx = ? ,error = ? ,tx_time =? ,tx_status = ? where ic_request_id = ? and source_service = %d",_TabName_cc_Tx,source_service)
+	sql :=fmt.Sprintf("update %s set hub_res_tx = ? ,error = ? ,tx_time =? ,tx_status = ? where ic_request_id = ? and source_service = %d",_TabName_cc_Tx,Source_Provider)
 
-	lastId, rows, err := mysql.Exec(sql,
+	lastId, rows, err := ledger.Exec(sql,
 		data.HUBResTxId,
 		data.ErrMsg,
 		NowTime(),
@@ -176,9 +191,9 @@ func ProviderCallBackTransRecord(data *ProviderResInfo )  {
 		data.IcRequestId)
 
 	if err != nil {
-		logging.Logger.Errorf("set relayer callback record Failed :%s", err.Error())
+		logging.Logger.Errorf("set provider callback record Failed :%s", err.Error())
 	} else {
-		logging.Logger.Infof("set relayer callback record lastId:%d ;rows:%d ", lastId, rows)
+		logging.Logger.Infof("set provider callback record lastId:%d ;rows:%d ", lastId, rows)
 	}
 }
 
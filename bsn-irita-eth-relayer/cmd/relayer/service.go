@@ -0,0 +1,396 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cfg "relayer/config"
+	"relayer/core"
+	"relayer/hub"
+)
+
+const (
+	flagServiceName       = "name"
+	flagDescription       = "description"
+	flagTags              = "tags"
+	flagAuthorDescription = "author-description"
+	flagSchemas           = "schemas"
+	flagProvider          = "provider"
+	flagDeposit           = "deposit"
+	flagPricing           = "pricing"
+	flagQoS               = "qos"
+	flagOptions           = "options"
+)
+
+var (
+	ServiceCmd = &cobra.Command{
+		Use:   "service",
+		Short: "Service definition and binding management commands",
+	}
+)
+
+// ServiceDefineCmd implements the service define command
+func ServiceDefineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "define [config-file]",
+		Short: "Define the service on the Irita-Hub, the name and schemas default to the service config",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hubChain, err := loadHubChain(args)
+			if err != nil {
+				return err
+			}
+
+			flags := cmd.Flags()
+			name, _ := flags.GetString(flagServiceName)
+			description, _ := flags.GetString(flagDescription)
+			tags, _ := flags.GetStringSlice(flagTags)
+			authorDescription, _ := flags.GetString(flagAuthorDescription)
+			schemas, _ := flags.GetString(flagSchemas)
+
+			txHash, err := hubChain.DefineService(core.ServiceDefinition{
+				ServiceName:       name,
+				Description:       description,
+				Tags:              tags,
+				AuthorDescription: authorDescription,
+				Schemas:           schemas,
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("service defined successfully: %s\n", txHash)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagServiceName, "", "service name")
+	cmd.Flags().String(flagDescription, "", "service description")
+	cmd.Flags().StringSlice(flagTags, nil, "service tags")
+	cmd.Flags().String(flagAuthorDescription, "", "author description")
+	cmd.Flags().String(flagSchemas, "", "input and output schemas")
+
+	return cmd
+}
+
+// ServiceBindCmd implements the service bind command
+func ServiceBindCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bind [deposit] [config-file]",
+		Short: "Bind the service on the Irita-Hub, the pricing charges the service fee of the service config by default",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hubChain, err := loadHubChain(args[1:])
+			if err != nil {
+				return err
+			}
+
+			binding := bindingFromFlags(cmd)
+			binding.Deposit = args[0]
+
+			txHash, err := hubChain.BindService(binding)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("service bound successfully: %s\n", txHash)
+
+			return nil
+		},
+	}
+
+	addBindingFlags(cmd)
+	cmd.Flags().String(flagOptions, "", "binding options in JSON")
+
+	return cmd
+}
+
+// ServiceUpdateBindingCmd implements the service update-binding command
+func ServiceUpdateBindingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-binding [config-file]",
+		Short: "Update the service binding on the Irita-Hub, the fields not given are left unchanged",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hubChain, err := loadHubChain(args)
+			if err != nil {
+				return err
+			}
+
+			binding := bindingFromFlags(cmd)
+			binding.Deposit, _ = cmd.Flags().GetString(flagDeposit)
+
+			txHash, err := hubChain.UpdateServiceBinding(binding)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("service binding updated successfully: %s\n", txHash)
+
+			return nil
+		},
+	}
+
+	addBindingFlags(cmd)
+	cmd.Flags().String(flagDeposit, "", "deposit added to the binding")
+
+	return cmd
+}
+
+// ServiceDisableCmd implements the service disable command
+func ServiceDisableCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "disable [config-file]",
+		Short: "Disable the service binding on the Irita-Hub",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hubChain, err := loadHubChain(args)
+			if err != nil {
+				return err
+			}
+
+			name, _ := cmd.Flags().GetString(flagServiceName)
+			provider, _ := cmd.Flags().GetString(flagProvider)
+
+			txHash, err := hubChain.DisableServiceBinding(name, provider)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("service binding disabled successfully: %s\n", txHash)
+
+			return nil
+		},
+	}
+
+	addProviderFlags(cmd)
+
+	return cmd
+}
+
+// ServiceEnableCmd implements the service enable command
+func ServiceEnableCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enable [config-file]",
+		Short: "Enable the disabled service binding on the Irita-Hub",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hubChain, err := loadHubChain(args)
+			if err != nil {
+				return err
+			}
+
+			name, _ := cmd.Flags().GetString(flagServiceName)
+			provider, _ := cmd.Flags().GetString(flagProvider)
+			deposit, _ := cmd.Flags().GetString(flagDeposit)
+
+			txHash, err := hubChain.EnableServiceBinding(name, provider, deposit)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("service binding enabled successfully: %s\n", txHash)
+
+			return nil
+		},
+	}
+
+	addProviderFlags(cmd)
+	cmd.Flags().String(flagDeposit, "", "deposit added to the binding")
+
+	return cmd
+}
+
+// ServiceRefundFeesCmd implements the service refund-fees command
+// The command refunds the binding deposit, so it is aliased as refund-deposit
+func ServiceRefundFeesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "refund-fees [config-file]",
+		Aliases: []string{"refund-deposit"},
+		Short:   "Refund the deposit of the disabled service binding on the Irita-Hub",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hubChain, err := loadHubChain(args)
+			if err != nil {
+				return err
+			}
+
+			name, _ := cmd.Flags().GetString(flagServiceName)
+			provider, _ := cmd.Flags().GetString(flagProvider)
+
+			txHash, err := hubChain.RefundServiceDeposit(name, provider)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("service deposit refunded successfully: %s\n", txHash)
+
+			return nil
+		},
+	}
+
+	addProviderFlags(cmd)
+
+	return cmd
+}
+
+// ServiceWithdrawFeesCmd implements the service withdraw-fees command
+func ServiceWithdrawFeesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "withdraw-fees [config-file]",
+		Short: "Withdraw the fees earned by the provider on the Irita-Hub",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hubChain, err := loadHubChain(args)
+			if err != nil {
+				return err
+			}
+
+			provider, _ := cmd.Flags().GetString(flagProvider)
+
+			txHash, err := hubChain.WithdrawEarnedFees(provider)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("earned fees withdrawn successfully: %s\n", txHash)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagProvider, "", "provider address, default to the key address")
+
+	return cmd
+}
+
+// ServiceFeesCmd implements the service fees command
+func ServiceFeesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fees [config-file]",
+		Short: "Show the fees earned by the provider on the Irita-Hub",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hubChain, err := loadHubChain(args)
+			if err != nil {
+				return err
+			}
+
+			provider, _ := cmd.Flags().GetString(flagProvider)
+
+			fees, err := hubChain.QueryEarnedFees(provider)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("earned fees: %s\n", fees)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagProvider, "", "provider address, default to the key address")
+
+	return cmd
+}
+
+// ServiceShowCmd implements the service show command
+func ServiceShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show [config-file]",
+		Short: "Show the service definition and bindings on the Irita-Hub",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hubChain, err := loadHubChain(args)
+			if err != nil {
+				return err
+			}
+
+			name, _ := cmd.Flags().GetString(flagServiceName)
+
+			status, err := hubChain.GetService(name)
+			if err != nil {
+				return err
+			}
+
+			bz, err := json.MarshalIndent(status, "", "    ")
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%s\n", bz)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagServiceName, "", "service name, default to the service config")
+
+	return cmd
+}
+
+// loadHubChain builds the Irita-Hub chain by the config file in the args
+func loadHubChain(args []string) (hub.IritaHubChain, error) {
+	configFileName := cfg.DefaultConfigFileName
+	if len(args) > 0 {
+		configFileName = args[0]
+	}
+
+	config, err := cfg.LoadYAMLConfig(configFileName)
+	if err != nil {
+		return hub.IritaHubChain{}, err
+	}
+
+	return hub.BuildIritaHubChain(hub.NewConfig(config)), nil
+}
+
+// addProviderFlags adds the flags to specify the service binding
+func addProviderFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagServiceName, "", "service name, default to the service config")
+	cmd.Flags().String(flagProvider, "", "provider address, default to the key address")
+}
+
+// addBindingFlags adds the flags of the service binding fields
+func addBindingFlags(cmd *cobra.Command) {
+	addProviderFlags(cmd)
+	cmd.Flags().String(flagPricing, "", "pricing in JSON")
+	cmd.Flags().Uint64(flagQoS, 0, "quality of service, in terms of the minimum response time")
+}
+
+// bindingFromFlags builds the service binding from the binding flags
+func bindingFromFlags(cmd *cobra.Command) core.ServiceBinding {
+	flags := cmd.Flags()
+
+	name, _ := flags.GetString(flagServiceName)
+	provider, _ := flags.GetString(flagProvider)
+	pricing, _ := flags.GetString(flagPricing)
+	qos, _ := flags.GetUint64(flagQoS)
+	options, _ := flags.GetString(flagOptions)
+
+	return core.ServiceBinding{
+		ServiceName: name,
+		Provider:    provider,
+		Pricing:     pricing,
+		QoS:         qos,
+		Options:     options,
+	}
+}
+
+func init() {
+	ServiceCmd.AddCommand(
+		ServiceDefineCmd(),
+		ServiceBindCmd(),
+		ServiceUpdateBindingCmd(),
+		ServiceDisableCmd(),
+		ServiceEnableCmd(),
+		ServiceRefundFeesCmd(),
+		ServiceWithdrawFeesCmd(),
+		ServiceFeesCmd(),
+		ServiceShowCmd(),
+	)
+
+	HubCmd.AddCommand(ServiceCmd)
+}
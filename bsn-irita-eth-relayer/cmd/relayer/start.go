@@ -6,15 +6,16 @@ import (
 	"github.com/spf13/cobra"
 	_ "net/http/pprof"
 	"relayer/appchains"
+	"relayer/common/ledger"
 	cfg "relayer/config"
 	"relayer/core"
 	"relayer/hub"
 	"relayer/logging"
-	"relayer/mysql"
+	"relayer/provider"
 	"relayer/server"
 	"relayer/store"
 
-	txstore "relayer/appchains/eth/store"
+	txstore "relayer/appchains/store"
 )
 
 const (
@@ -41,30 +42,47 @@ func StartCmd() *cobra.Command {
 				return err
 			}
 
-			appChainType := config.GetString(cfg.ConfigKeyAppChainType)
+			appChainTypes := cfg.GetAppChainTypes(config)
+			if len(appChainTypes) == 0 {
+				return fmt.Errorf("no app chain type configured, set %s or %s", cfg.ConfigKeyAppChainTypes, cfg.ConfigKeyAppChainType)
+			}
 
 			store, err := store.NewStore(config.GetString(cfg.ConfigKeyStorePath))
 			if err != nil {
 				return err
 			}
 
-			mysqlConfig := mysql.NewConfig(config)
-
-			txstore.InitMysql(mysqlConfig.DSN())
-			//mysql.NewDB(mysqlConfig)
-			//defer mysql.Close()
+			if err := txstore.InitLedger(ledger.NewConfig(config)); err != nil {
+				return err
+			}
+			defer ledger.Close()
 
 			appChainFactory := appchains.NewAppChainFactory(store)
-			hubChain := hub.BuildIritaHubChain(hub.NewConfig(config))
-			relayerInstance := core.NewRelayer(appChainType, hubChain, appChainFactory, logging.Logger)
+			hubConfig := hub.NewConfig(config)
+			if err := hub.ValidateRoutes(hubConfig.Routes); err != nil {
+				return err
+			}
 
+			hubChain := hub.BuildIritaHubChain(hubConfig)
+			hubChain.Endpoints.StartHealthCheck()
+			relayerInstance := core.NewRelayer(appChainTypes, hubChain, appChainFactory, store, logging.Logger)
+			if retention := cfg.GetRetention(config); retention != 0 {
+				relayerInstance.Retention = retention
+			}
+
+			// each hosted type takes the base config from its own section
 			baseConfigFactory := appchains.NewBaseConfigFactory(config)
-			BaseConfig, err := baseConfigFactory.NewBaseConfig(appChainType)
-			if err != nil {
-				return err
+			for _, appChainType := range appChainTypes {
+				BaseConfig, err := baseConfigFactory.NewBaseConfig(appChainType)
+				if err != nil {
+					return err
+				}
+				baseConfigByte, _ := json.Marshal(BaseConfig)
+				if err := appChainFactory.StoreBaseConfig(appChainType, baseConfigByte); err != nil {
+					return fmt.Errorf("failed to store the %s base config: %s", appChainType, err)
+				}
 			}
-			baseConfigByte, _ := json.Marshal(BaseConfig)
-			appChainFactory.StoreBaseConfig(appChainType, baseConfigByte)
+
 			chainIDsbz, _ := store.Get([]byte("chainIDs"))
 			if chainIDsbz == nil {
 				chainIDsbz, err = json.Marshal(map[string]string{})
@@ -76,27 +94,37 @@ func StartCmd() *cobra.Command {
 				chainIDs := map[string]string{}
 				json.Unmarshal(chainIDsbz, &chainIDs)
 				for chainID, chainType := range chainIDs {
-					if chainType == appChainType {
-						chainParams, err := store.Get([]byte(fmt.Sprintf("%s:params:%s", appChainType, chainID)))
-						if err != nil {
-							return err
-						}
-						chain, err := relayerInstance.AppChainFactory.BuildAppChain(chainType, chainParams)
-						if err != nil {
-							return err
-						}
-
-						if err := chain.Start(relayerInstance.HandleInterchainRequest); err != nil {
-							return err
-						}
-
-						relayerInstance.AppChains[chainID] = chain
-						relayerInstance.AppChainStates[chainID] = true
+					if !relayerInstance.IsChainTypeHosted(chainType) {
+						logging.Logger.Warnf("chain %s of type %s is not hosted, skipped", chainID, chainType)
+						continue
+					}
+
+					chainParams, err := store.Get([]byte(fmt.Sprintf("%s:params:%s", chainType, chainID)))
+					if err != nil {
+						return err
+					}
+
+					if err := relayerInstance.RestoreChain(chainType, chainID, chainParams); err != nil {
+						return err
 					}
 				}
 			}
 
-			chainManager := server.NewChainManager(relayerInstance)
+			// resume the response subscriptions interrupted by the last shutdown
+			relayerInstance.RecoverSubscriptions()
+
+			// resubmit the interchain requests and responses left in the queues
+			relayerInstance.StartQueues()
+
+			// deliver the requests addressed to the relayer as the provider on the Hub
+			providerConfig := provider.NewConfig(config)
+			if providerConfig.Enabled {
+				if err := provider.NewProvider(providerConfig, hubChain, relayerInstance, store).Start(); err != nil {
+					return err
+				}
+			}
+
+			chainManager := server.NewChainManager(relayerInstance, hubChain)
 
 			httpPort := config.GetInt(_HttpPort)
 			if httpPort == 0 {
@@ -0,0 +1,145 @@
+package ledger
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// supported drivers of the ledger backend
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// QueryOption scans a row into the result item
+type QueryOption func(rows *sql.Rows) (interface{}, error)
+
+// Backend defines the database which the cross-chain tx ledger is stored in
+// The statements use ? as the placeholder, which is rebound by the backend if required
+type Backend interface {
+	// Driver returns the driver name of the backend
+	Driver() string
+
+	// Exec executes the statement and returns the last inserted ID and the number of the affected rows
+	Exec(query string, args ...interface{}) (lastId, rows int64, err error)
+
+	// Query executes the query and scans each row by the given option
+	Query(option QueryOption, query string, args ...interface{}) ([]interface{}, error)
+
+	// TableExists checks if the table exists
+	TableExists(table string) (bool, error)
+
+	// ColumnExists checks if the column exists in the table
+	ColumnExists(table string, column string) (bool, error)
+
+	// IndexExists checks if the index exists on the table
+	IndexExists(table string, index string) (bool, error)
+
+	// Close closes the backend
+	Close() error
+}
+
+var (
+	backend Backend
+
+	errNotInitialized = errors.New("ledger backend not initialized")
+)
+
+// NewBackend creates the backend of the given config
+func NewBackend(config Config) (Backend, error) {
+	switch config.Driver {
+	case DriverMySQL:
+		return NewMySQLBackend(config.DSN)
+
+	case DriverPostgres:
+		return NewPostgresBackend(config.DSN)
+
+	case DriverSQLite:
+		return NewSQLiteBackend(config.DSN)
+
+	default:
+		return nil, fmt.Errorf("unsupported ledger driver: %s, should be one of %s, %s and %s", config.Driver, DriverMySQL, DriverPostgres, DriverSQLite)
+	}
+}
+
+// Init creates the backend of the given config and sets it as the ledger backend
+func Init(config Config) error {
+	b, err := NewBackend(config)
+	if err != nil {
+		return err
+	}
+
+	SetBackend(b)
+
+	return nil
+}
+
+// SetBackend sets the ledger backend
+func SetBackend(b Backend) {
+	backend = b
+}
+
+// Driver returns the driver name of the ledger backend
+func Driver() string {
+	if backend == nil {
+		return ""
+	}
+
+	return backend.Driver()
+}
+
+// Exec executes the statement on the ledger backend
+func Exec(query string, args ...interface{}) (lastId, rows int64, err error) {
+	if backend == nil {
+		return 0, 0, errNotInitialized
+	}
+
+	return backend.Exec(query, args...)
+}
+
+// Query executes the query on the ledger backend
+func Query(option QueryOption, query string, args ...interface{}) ([]interface{}, error) {
+	if backend == nil {
+		return nil, errNotInitialized
+	}
+
+	return backend.Query(option, query, args...)
+}
+
+// TableExists checks if the table exists in the ledger backend
+func TableExists(table string) (bool, error) {
+	if backend == nil {
+		return false, errNotInitialized
+	}
+
+	return backend.TableExists(table)
+}
+
+// ColumnExists checks if the column exists in the table of the ledger backend
+func ColumnExists(table string, column string) (bool, error) {
+	if backend == nil {
+		return false, errNotInitialized
+	}
+
+	return backend.ColumnExists(table, column)
+}
+
+// IndexExists checks if the index exists on the table of the ledger backend
+func IndexExists(table string, index string) (bool, error) {
+	if backend == nil {
+		return false, errNotInitialized
+	}
+
+	return backend.IndexExists(table, index)
+}
+
+// Close closes the ledger backend
+func Close() error {
+	if backend == nil {
+		return nil
+	}
+
+	return backend.Close()
+}
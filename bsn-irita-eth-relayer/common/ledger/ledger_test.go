@@ -0,0 +1,63 @@
+package ledger
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestSQLiteBackend(t *testing.T) {
+	b, err := NewSQLiteBackend(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	if exists, err := b.TableExists("tb_test"); err != nil || exists {
+		t.Fatalf("expected the table not to exist, got %v %v", exists, err)
+	}
+
+	if _, _, err := b.Exec("CREATE TABLE tb_test (id INTEGER PRIMARY KEY AUTOINCREMENT, name varchar(32) NOT NULL DEFAULT '')"); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, err := b.TableExists("tb_test"); err != nil || !exists {
+		t.Fatalf("expected the table to exist, got %v %v", exists, err)
+	}
+
+	if exists, err := b.ColumnExists("tb_test", "name"); err != nil || !exists {
+		t.Fatalf("expected the column to exist, got %v %v", exists, err)
+	}
+
+	if exists, err := b.ColumnExists("tb_test", "address"); err != nil || exists {
+		t.Fatalf("expected the column not to exist, got %v %v", exists, err)
+	}
+
+	lastId, rows, err := b.Exec("INSERT INTO tb_test (name) VALUES (?);", "relayer")
+	if err != nil || lastId != 1 || rows != 1 {
+		t.Fatalf("unexpected insert result: %d %d %v", lastId, rows, err)
+	}
+
+	list, err := b.Query(func(rows *sql.Rows) (interface{}, error) {
+		var name string
+		err := rows.Scan(&name)
+		return name, err
+	}, "SELECT name FROM tb_test WHERE id = ?", lastId)
+	if err != nil || len(list) != 1 || list[0] != "relayer" {
+		t.Fatalf("unexpected query result: %v %v", list, err)
+	}
+}
+
+func TestRebindDollar(t *testing.T) {
+	query := rebindDollar("UPDATE t SET a = ?, b = '?' WHERE c = ? AND d IN (?,?);")
+
+	expected := "UPDATE t SET a = $1, b = '?' WHERE c = $2 AND d IN ($3,$4)"
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestNewBackend(t *testing.T) {
+	if _, err := NewBackend(Config{Driver: "oracle"}); err == nil {
+		t.Fatal("expected the unsupported driver to be rejected")
+	}
+}
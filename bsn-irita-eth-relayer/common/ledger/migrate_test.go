@@ -0,0 +1,63 @@
+package ledger
+
+import (
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	b, err := NewSQLiteBackend(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	migrations := []Migration{
+		{Version: 2, Name: "add name", Up: func(b Backend) error {
+			_, _, err := b.Exec("ALTER TABLE tb_test ADD COLUMN name varchar(32) NOT NULL DEFAULT ''")
+			return err
+		}},
+		{Version: 1, Name: "create table", Up: func(b Backend) error {
+			_, _, err := b.Exec("CREATE TABLE tb_test (id INTEGER PRIMARY KEY AUTOINCREMENT)")
+			return err
+		}},
+	}
+
+	applied, err := migrate(b, migrations[1:])
+	if err != nil || len(applied) != 1 {
+		t.Fatalf("expected the first migration to be applied, got %v %v", applied, err)
+	}
+
+	statuses, err := getMigrationStatus(b, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statuses) != 2 || !statuses[0].Applied || statuses[1].Applied || statuses[1].Version != 2 {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+
+	// the applied migrations are skipped
+	applied, err = migrate(b, migrations)
+	if err != nil || len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatalf("expected the second migration to be applied, got %v %v", applied, err)
+	}
+
+	if exists, err := b.ColumnExists("tb_test", "name"); err != nil || !exists {
+		t.Fatalf("expected the column to be added, got %v %v", exists, err)
+	}
+
+	// the schema newer than the migrations is rejected
+	if _, err := migrate(b, migrations[1:]); err == nil {
+		t.Fatal("expected the newer schema version to be rejected")
+	}
+}
+
+func TestSortMigrations(t *testing.T) {
+	if _, err := sortMigrations([]Migration{{Version: 1}, {Version: 1}}); err == nil {
+		t.Fatal("expected the duplicate version to be rejected")
+	}
+
+	if _, err := sortMigrations([]Migration{{Version: 0}}); err == nil {
+		t.Fatal("expected the non-positive version to be rejected")
+	}
+}
@@ -2,15 +2,21 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+
+	"relayer/secrets"
 )
 
 const (
 	DefaultConfigFileName = "./config/config.yaml"
 
-	ConfigKeyAppChainType = "base.app_chain_type"
-	ConfigKeyStorePath    = "base.store_path"
+	ConfigKeyAppChainType  = "base.app_chain_type"
+	ConfigKeyAppChainTypes = "base.app_chain_types"
+	ConfigKeyStorePath     = "base.store_path"
+	ConfigKeyRetention     = "base.retention_days"
 
 	DefaultStorePath = ".db"
 )
@@ -31,10 +37,43 @@ func LoadYAMLConfig(configFileName string) (*viper.Viper, error) {
 		return nil, fmt.Errorf("failed to read the config file: %s", err)
 	}
 
+	// decrypt the encrypted secrets transparently
+	err = secrets.DecryptConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
 	return v, nil
 }
 
+// GetRetention returns the period to keep the processed records in the store for
+// Zero is returned if not configured, in which case the default retention applies
+func GetRetention(v *viper.Viper) time.Duration {
+	return time.Duration(v.GetInt64(ConfigKeyRetention)) * 24 * time.Hour
+}
+
 // GetConfigKey returns the key with the given prefix
 func GetConfigKey(prefix string, key string) string {
 	return fmt.Sprintf("%s.%s", prefix, key)
 }
+
+// GetAppChainTypes returns the app chain types hosted by the relayer
+// The single app chain type is used if the list is not configured
+func GetAppChainTypes(v *viper.Viper) []string {
+	chainTypes := make([]string, 0)
+
+	for _, chainType := range v.GetStringSlice(ConfigKeyAppChainTypes) {
+		chainType = strings.ToLower(strings.TrimSpace(chainType))
+		if len(chainType) > 0 {
+			chainTypes = append(chainTypes, chainType)
+		}
+	}
+
+	if len(chainTypes) == 0 {
+		if chainType := v.GetString(ConfigKeyAppChainType); len(chainType) > 0 {
+			chainTypes = append(chainTypes, strings.ToLower(chainType))
+		}
+	}
+
+	return chainTypes
+}
@@ -1,5 +1,10 @@
 package core
 
+const (
+	StatusCodeTimeout    = 408
+	ErrMsgRequestTimeout = "request timeout" // error msg responded to the app chain when the request expires on the Hub
+)
+
 // ResponseAdaptor is the wrapped response struct of Irita-Hub
 type ResponseAdaptor struct {
 	StatusCode  int
@@ -13,7 +18,7 @@ func (r ResponseAdaptor) GetErrMsg() string {
 	case 200:
 		return ""
 
-	case 400, 500:
+	case 400, StatusCodeTimeout, 500:
 		return r.Result
 
 	default:
@@ -27,10 +32,24 @@ func (r ResponseAdaptor) GetOutput() string {
 	case 200:
 		return r.Output
 
-	case 400, 500:
+	case 400, StatusCodeTimeout, 500:
 		return r.Result
 
 	default:
 		return ""
 	}
 }
+
+// NewTimeoutResponse returns the response for the request which expires without being responded
+func NewTimeoutResponse() ResponseAdaptor {
+	return ResponseAdaptor{
+		StatusCode: StatusCodeTimeout,
+		Result:     ErrMsgRequestTimeout,
+	}
+}
+
+// IsTimeoutResponse returns true if the response is sent for the request which expires on the Hub
+func IsTimeoutResponse(response ResponseI) bool {
+	adaptor, ok := response.(ResponseAdaptor)
+	return ok && adaptor.StatusCode == StatusCodeTimeout
+}
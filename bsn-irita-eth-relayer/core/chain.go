@@ -1,5 +1,9 @@
 package core
 
+import (
+	"time"
+)
+
 // ChainI defines the basic chain interface
 type ChainI interface {
 	GetChainID() string // chain ID getter
@@ -7,6 +11,7 @@ type ChainI interface {
 
 type InterchainRequestInfo struct {
 	HubReqTxId string
+	ReqCtxId string
 	IcRequestId string
 }
 
@@ -16,6 +21,27 @@ type HubChainI interface {
 
 	// send the interchain request and handle the response with the given callback
 	SendInterchainRequest(request InterchainRequest, cb ResponseCallback) (InterchainRequestInfo,error)
+
+	// handle the response of the given request context and request with the given callback
+	ResponseListener(reqCtxID string, requestID string, cb ResponseCallback) error
+
+	// get the status of the Hub endpoints
+	GetEndpoints() []HubEndpointStatus
+
+	// find the Hub request initiated by the previous submission of the given request since the given time
+	FindInterchainRequest(request InterchainRequest, since time.Time) (InterchainRequestInfo, error)
+}
+
+// HubEndpointStatus defines the health status of a Hub endpoint
+type HubEndpointStatus struct {
+	RPCAddr  string `json:"rpc_addr"`
+	GRPCAddr string `json:"grpc_addr"`
+	Active   bool   `json:"active"`
+	Healthy  bool   `json:"healthy"`
+	Height   int64  `json:"height"`
+	Latency  int64  `json:"latency"` // latency of the last probe in milliseconds
+	Failures int    `json:"failures"`
+	Error    string `json:"error,omitempty"`
 }
 
 // AppChainI defines the interface to interact with the application chain
@@ -31,12 +57,22 @@ type AppChainI interface {
 	// get the current height
 	GetHeight() int64
 
+	// get the node currently connected
+	GetActiveNode() string
+
 	// send the response to the application chain
 	SendResponse(requestID string, response ResponseI) error
 
 	Close()
 }
 
+// ContractCallerI defines the interface of the application chain able to deliver the
+// requests as the service provider, which is implemented optionally by the app chains
+type ContractCallerI interface {
+	// call the contract at the given endpoint address with the call data, returning the output and the tx hash
+	CallContract(endpointAddress string, method string, callData []byte) (output string, txHash string, err error)
+}
+
 // AppChainFactoryI abstracts the application chain operation interface
 type AppChainFactoryI interface {
 	// build an application chain according to the given app chain type and params
@@ -65,6 +101,8 @@ type InterchainRequest struct {
 	CallData        []byte // target method name and json string of arguments
 	TxHash          string // source transaction hash
 	Sender          string // message sender
+	Timeout         int64  // service timeout in blocks on the Hub, the default of the Hub is used if zero
+	Timestamp       int64  // unix time in milliseconds of the source tx, the time detected is used if zero
 }
 
 // ResponseI defines the response related interfaces
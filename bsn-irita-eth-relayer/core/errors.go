@@ -0,0 +1,23 @@
+package core
+
+// TransientError defines the error of the contract call which is safe to retry,
+// e.g. the node is unreachable before the tx is sent
+type TransientError struct {
+	Err error
+}
+
+// NewTransientError wraps the given error as a transient error
+func NewTransientError(err error) error {
+	return TransientError{Err: err}
+}
+
+// Error implements error
+func (e TransientError) Error() string {
+	return e.Err.Error()
+}
+
+// IsTransient returns true if the given error is transient
+func IsTransient(err error) bool {
+	_, ok := err.(TransientError)
+	return ok
+}
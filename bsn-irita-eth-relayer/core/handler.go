@@ -1,66 +1,387 @@
 package core
 
 import (
-	"relayer/appchains/eth/store"
+	"fmt"
+	"relayer/appchains/store"
 	"strings"
+	"sync"
+	"time"
+
+	"relayer/metrics"
 )
 
 // HandleInterchainRequest handles the interchain request
+// The request is persisted into the request queue, from which it is submitted to the Hub chain
+// by the queue worker, so that the chain listener does not wait on the Hub
+// The request which has been submitted or queued is skipped
 func (r *Relayer) HandleInterchainRequest(chainID string, request InterchainRequest, txHash string) error {
 	r.Logger.Infof("got the interchain request on %s: %+v", chainID, request)
 
-
-
 	request.TxHash = txHash
+	if request.Timestamp == 0 {
+		request.Timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	}
 
-	callback := func(icRequestID string, response ResponseI) {
+	processed, ok, err := r.Processed.Get(chainID, request.ID)
+	if err != nil {
+		r.Logger.Errorf("failed to query the processed interchain request %s on %s: %s", request.ID, chainID, err)
+		return err
+	}
+
+	if ok {
 		r.Logger.Infof(
-			"got the response of the interchain request on %s: %+v",
-			r.HubChain.GetChainID(),
-			response,
+			"interchain request %s on %s already submitted, hub tx: %s, hub request: %s",
+			request.ID,
+			chainID,
+			processed.HubReqTxID,
+			processed.IcRequestID,
 		)
 
-		// TODO
-		//mysql.OnInterchainRequestHandled()
+		return nil
+	}
 
-		err := r.AppChains[chainID].SendResponse(request.ID, response)
-		if err != nil {
-			r.Logger.Errorf(
-				"failed to send the response to %s: %s",
-				chainID,
-				err,
-			)
+	if r.Queue.Has(chainID, request.ID) {
+		r.Logger.Infof("interchain request %s on %s already queued", request.ID, chainID)
+		return nil
+	}
 
-			return
+	_, err = r.Queue.Push(chainID, request)
+	if err != nil {
+		r.Logger.Errorf("failed to enqueue the interchain request %s on %s: %s", request.ID, chainID, err)
+		return err
+	}
+
+	metrics.RequestDetected(chainID)
+	store.InitRelayerTransRecord(request.ID, chainID, request.TxHash, request.DestChainID, "", "", store.TxStatus_Unknow, "")
+
+	r.Logger.Infof("HandleInterchainRequest is End !!!")
+	return nil
+}
+
+// StartQueues starts to resubmit the queued interchain requests and redeliver the queued responses
+// The processed requests beyond the retention are pruned periodically as well
+func (r *Relayer) StartQueues() {
+	go func() {
+		ticker := time.NewTicker(DefaultQueuePollInterval)
+		defer ticker.Stop()
+
+		var pruned time.Time
+
+		for now := range ticker.C {
+			if now.Sub(pruned) >= DefaultPruneInterval {
+				r.prune(now)
+				pruned = now
+			}
+
+			entries, err := r.Queue.Due()
+			if err != nil {
+				r.Logger.Errorf("failed to load the queued interchain requests: %s", err)
+			}
+
+			for _, entry := range entries {
+				r.submitRequest(entry)
+			}
+
+			responses, err := r.ResponseQueue.Due()
+			if err != nil {
+				r.Logger.Errorf("failed to load the queued responses: %s", err)
+			}
+
+			for _, entry := range responses {
+				if !r.ResponseQueue.Acquire(entry) {
+					continue
+				}
+
+				r.deliverResponse(entry)
+				r.ResponseQueue.Release(entry)
+			}
 		}
+	}()
+}
 
-		r.Logger.Infof(
-			"response sent to %s successfully",
-			chainID,
-		)
+// prune removes the processed requests beyond the retention
+func (r *Relayer) prune(now time.Time) {
+	if r.Retention <= 0 {
+		return
 	}
 
-	reqInfo,err := r.HubChain.SendInterchainRequest(request, callback)
+	count, err := r.Processed.Prune(now.Add(-r.Retention))
 	if err != nil {
+		r.Logger.Errorf("failed to prune the processed requests: %s", err)
+		return
+	}
 
-		if  ! strings.Contains(err.Error(),"duplicated request sequence"){
-			store.InitRelayerTransRecord(request.ID,chainID,txHash,request.DestChainID,reqInfo.HubReqTxId,reqInfo.IcRequestId,store.TxStatus_Error,err.Error())
-		}else {
-			r.Logger.Infof("duplicated request sequence ! not record trans")
+	if count > 0 {
+		r.Logger.Infof("pruned %d processed records older than %s", count, r.Retention)
+	}
+}
+
+// submitRequest submits the queued interchain request to the Hub chain
+// The entry is removed from the queue once the Hub request ID is known, otherwise it is rescheduled
+func (r *Relayer) submitRequest(entry *QueueEntry) {
+	if !r.Queue.Acquire(entry) {
+		return
+	}
+	defer r.Queue.Release(entry)
+
+	chainID := entry.ChainID
+	request := entry.Request
+
+	if _, ok, err := r.Processed.Get(chainID, request.ID); err == nil && ok {
+		r.Logger.Infof("interchain request %s on %s already submitted", request.ID, chainID)
+
+		if err := r.Queue.Done(entry); err != nil {
+			r.Logger.Errorf("failed to dequeue the interchain request %s: %s", request.ID, err)
 		}
 
+		return
+	}
+
+	// the response may arrive before the submission is recorded, even synchronously on sending,
+	// in which case it is handled once the submission is recorded
+	var mtx sync.Mutex
+	submitted := false
+	var pending func()
+
+	callback := func(icRequestID string, response ResponseI) {
+		mtx.Lock()
+		if !submitted {
+			pending = func() { r.onResponse(chainID, request.ID, icRequestID, response) }
+			mtx.Unlock()
+
+			return
+		}
+		mtx.Unlock()
+
+		r.onResponse(chainID, request.ID, icRequestID, response)
+	}
+
+	if err := r.Processed.MarkSubmitting(chainID, request.ID); err != nil {
+		r.Logger.Errorf("failed to mark the interchain request %s as submitting: %s", request.ID, err)
+	}
+
+	reqInfo,err := r.HubChain.SendInterchainRequest(request, callback)
+	if err != nil && reqInfo.IcRequestId == "" && strings.Contains(err.Error(),"duplicated request sequence") {
+		r.Logger.Infof("interchain request %s on %s submitted before, recovering the Hub request", request.ID, chainID)
+
+		reqInfo, err = r.recoverSubmission(chainID, request, callback)
+	}
+
+	if err != nil && reqInfo.IcRequestId == "" {
+		metrics.HubSubmitted(chainID, err)
+
 		r.Logger.Errorf(
-			"failed to handle the interchain request %+v on %s: %s",
+			"failed to handle the interchain request %+v on %s, attempts: %d: %s",
 			request,
 			r.HubChain.GetChainID(),
+			entry.Attempts+1,
 			err,
 		)
 
-		return err
+		dead, retryErr := r.Queue.Retry(entry, err)
+		if retryErr != nil {
+			r.Logger.Errorf("failed to reschedule the interchain request %s: %s", request.ID, retryErr)
+			return
+		}
+
+		if !dead {
+			store.RelayerSubmitErrorRecord(request.ID, err.Error())
+			return
+		}
+
+		r.Logger.Errorf("interchain request %s on %s given up after %d attempts", request.ID, chainID, entry.Attempts)
+
+		store.RelayerResponeRecord(&store.RelayerResInfo{
+			RequestId: request.ID,
+			TxStatus:  store.TxStatus_Error,
+			ErrMsg:    err.Error(),
+		})
+		store.RecordTxTransition(request.ID, store.TxState_Failed, "", err.Error())
+
+		return
 	}
 
-	//mysql.OnInterchainRequestReceived(request.ID, chainID, txHash)
-	store.InitRelayerTransRecord(request.ID,chainID,txHash,request.DestChainID,reqInfo.HubReqTxId,reqInfo.IcRequestId,store.TxStatus_Unknow,"")
-	r.Logger.Infof("HandleInterchainRequest is End !!!")
-	return nil
+	if err != nil {
+		r.Logger.Errorf("failed to listen to the response of the interchain request %s: %s", request.ID, err)
+	}
+
+	metrics.HubSubmitted(chainID, nil)
+	metrics.AddPendingRequests(chainID, 1)
+
+	store.RelayerSubmitRecord(request.ID, reqInfo.HubReqTxId, reqInfo.IcRequestId)
+
+	if err := r.Processed.Add(chainID, request, reqInfo); err != nil {
+		r.Logger.Errorf("failed to record the processed interchain request %s: %s", request.ID, err)
+	}
+
+	mtx.Lock()
+	submitted = true
+	onResponse := pending
+	if onResponse == nil {
+		sub := Subscription{
+			ReqCtxID:      reqInfo.ReqCtxId,
+			HubRequestID:  reqInfo.IcRequestId,
+			SourceChainID: chainID,
+			RequestID:     request.ID,
+		}
+
+		if err := r.Subscriptions.Add(sub); err != nil {
+			r.Logger.Errorf("failed to persist the response subscription of request %s: %s", request.ID, err)
+		}
+	}
+	mtx.Unlock()
+
+	if err := r.Queue.Done(entry); err != nil {
+		r.Logger.Errorf("failed to dequeue the interchain request %s: %s", request.ID, err)
+	}
+
+	if onResponse != nil {
+		onResponse()
+	}
+}
+
+// RecoverSubscriptions resumes the response subscriptions of the outstanding Hub requests
+// The response which has been available is handled immediately
+func (r *Relayer) RecoverSubscriptions() {
+	subs, err := r.Subscriptions.List()
+	if err != nil {
+		r.Logger.Errorf("failed to load the response subscriptions: %s", err)
+		return
+	}
+
+	for _, sub := range subs {
+		sub := sub
+
+		callback := func(icRequestID string, response ResponseI) {
+			r.onResponse(sub.SourceChainID, sub.RequestID, icRequestID, response)
+		}
+
+		r.Logger.Infof("recovering the response subscription of request %s on %s", sub.RequestID, sub.SourceChainID)
+
+		if err := r.HubChain.ResponseListener(sub.ReqCtxID, sub.HubRequestID, callback); err != nil {
+			r.Logger.Errorf("failed to recover the response subscription of request %s: %s", sub.RequestID, err)
+			continue
+		}
+
+		metrics.AddPendingRequests(sub.SourceChainID, 1)
+	}
+}
+
+// recoverSubmission finds the Hub request of the interchain request submitted before and listens to its response again
+func (r *Relayer) recoverSubmission(chainID string, request InterchainRequest, callback ResponseCallback) (InterchainRequestInfo, error) {
+	since, _, err := r.Processed.Submitting(chainID, request.ID)
+	if err != nil {
+		r.Logger.Errorf("failed to get the submission time of the interchain request %s: %s", request.ID, err)
+	}
+
+	reqInfo, err := r.HubChain.FindInterchainRequest(request, since)
+	if err != nil {
+		return reqInfo, fmt.Errorf("failed to recover the duplicated request sequence: %s", err)
+	}
+
+	return reqInfo, r.HubChain.ResponseListener(reqInfo.ReqCtxId, reqInfo.IcRequestId, callback)
+}
+
+// onResponse handles the response of the Hub request
+func (r *Relayer) onResponse(chainID string, requestID string, icRequestID string, response ResponseI) {
+	r.Logger.Infof(
+		"got the response of the interchain request on %s: %+v",
+		r.HubChain.GetChainID(),
+		response,
+	)
+
+	if IsTimeoutResponse(response) {
+		store.RecordTxTransition(requestID, store.TxState_Expired, "", response.GetErrMsg())
+	} else {
+		store.RecordTxTransition(requestID, store.TxState_HubResponded, "", response.GetErrMsg())
+	}
+
+	// the response is persisted before the subscription is removed, in case of a crash during the delivery
+	entry := NewResponseEntry(chainID, requestID, response)
+	if err := r.ResponseQueue.Push(entry); err != nil {
+		r.Logger.Errorf("failed to persist the response of request %s: %s", requestID, err)
+	}
+
+	if err := r.Subscriptions.Remove(icRequestID); err != nil {
+		r.Logger.Errorf("failed to remove the response subscription of request %s: %s", requestID, err)
+	}
+
+	metrics.AddPendingRequests(chainID, -1)
+
+	// the queued response may have been picked up by the queue processing
+	if entry.Queued {
+		if !r.ResponseQueue.Acquire(entry) {
+			return
+		}
+		defer r.ResponseQueue.Release(entry)
+	}
+
+	r.deliverResponse(entry)
+}
+
+// deliverResponse sends the response to the app chain
+// The response is dequeued on success, otherwise it is queued for retry and dead lettered once the maximum attempts are exceeded
+func (r *Relayer) deliverResponse(entry *ResponseEntry) {
+	chainID := entry.ChainID
+
+	err := r.sendResponse(chainID, entry.RequestID, entry.Response)
+	metrics.ResponseDelivered(chainID, err)
+
+	if err == nil {
+		r.observeLatency(chainID, entry.RequestID)
+
+		r.Logger.Infof(
+			"response sent to %s successfully",
+			chainID,
+		)
+
+		if entry.Queued {
+			if err := r.ResponseQueue.Done(entry); err != nil {
+				r.Logger.Errorf("failed to dequeue the response of request %s: %s", entry.RequestID, err)
+			}
+		}
+
+		return
+	}
+
+	r.Logger.Errorf(
+		"failed to send the response to %s, attempts: %d: %s",
+		chainID,
+		entry.Attempts+1,
+		err,
+	)
+
+	dead, retryErr := r.ResponseQueue.Retry(entry, err)
+	if retryErr != nil {
+		r.Logger.Errorf("failed to reschedule the response of request %s: %s", entry.RequestID, retryErr)
+		return
+	}
+
+	if dead {
+		r.Logger.Errorf("response of request %s on %s moved to the dead letters after %d attempts", entry.RequestID, chainID, entry.Attempts)
+		store.RecordTxTransition(entry.RequestID, store.TxState_Failed, "", err.Error())
+	}
+}
+
+// observeLatency observes the relay latency of the given request since the source tx
+func (r *Relayer) observeLatency(chainID string, requestID string) {
+	processed, ok, err := r.Processed.Get(chainID, requestID)
+	if err != nil || !ok || processed.SourceTime == 0 {
+		return
+	}
+
+	metrics.ObserveRelayLatency(chainID, time.Unix(0, processed.SourceTime*int64(time.Millisecond)))
+}
+
+// sendResponse sends the response to the specified app chain
+func (r *Relayer) sendResponse(chainID string, requestID string, response ResponseI) error {
+	r.mtx.RLock()
+	chain, ok := r.AppChains[chainID]
+	r.mtx.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("chain ID %s does not exist", chainID)
+	}
+
+	return chain.SendResponse(requestID, response)
 }
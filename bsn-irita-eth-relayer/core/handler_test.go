@@ -0,0 +1,159 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	txstore "relayer/appchains/store"
+	"relayer/common/ledger"
+	"relayer/logging"
+	"relayer/store"
+)
+
+// syncHubChain is a Hub chain which responds synchronously on sending the request
+type syncHubChain struct {
+	HubChainI
+}
+
+func (c *syncHubChain) GetChainID() string {
+	return "hub"
+}
+
+func (c *syncHubChain) SendInterchainRequest(request InterchainRequest, cb ResponseCallback) (InterchainRequestInfo, error) {
+	cb("icreq1", ResponseAdaptor{StatusCode: 200, Output: "output"})
+
+	return InterchainRequestInfo{HubReqTxId: "hubtx", ReqCtxId: "ctx1", IcRequestId: "icreq1"}, nil
+}
+
+// ledgerAppChain is an app chain recording the response transitions as the app chains do
+type ledgerAppChain struct {
+	AppChainI
+}
+
+func (c *ledgerAppChain) SendResponse(requestID string, response ResponseI) error {
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseSent, "0x02", "")
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseConfirmed, "0x02", "")
+
+	return nil
+}
+
+func TestSubmitRequestSyncResponse(t *testing.T) {
+	if err := txstore.InitLedger(ledger.Config{Driver: ledger.DriverSQLite, DSN: ":memory:"}); err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	dir, err := ioutil.TempDir("", "relayer-handler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Relayer{
+		HubChain:      &syncHubChain{},
+		AppChains:     map[string]AppChainI{"eth1": &ledgerAppChain{}},
+		Queue:         NewRequestQueue(s, DefaultRequestMaxAttempts),
+		ResponseQueue: NewResponseQueue(s, DefaultResponseMaxAttempts),
+		Subscriptions: NewSubscriptionStore(s),
+		Processed:     NewProcessedIndex(s),
+		Logger:        logging.Logger,
+	}
+
+	txstore.InitRelayerTransRecord("req1", "eth1", "0x01", "eth2", "", "", txstore.TxStatus_Unknow, "")
+
+	entry, err := r.Queue.Push("eth1", InterchainRequest{ID: "req1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.submitRequest(entry)
+
+	transitions, err := txstore.GetTxTransitions("req1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		txstore.TxState_Detected,
+		txstore.TxState_Submitted,
+		txstore.TxState_HubResponded,
+		txstore.TxState_ResponseSent,
+		txstore.TxState_ResponseConfirmed,
+	}
+
+	if len(transitions) != len(expected) {
+		t.Fatalf("unexpected transitions: %+v", transitions)
+	}
+
+	for i, transition := range transitions {
+		if transition.State != expected[i] {
+			t.Fatalf("expected the transition %d to be %s, got %+v", i, expected[i], transitions)
+		}
+	}
+
+	if r.Queue.Has("eth1", "req1") {
+		t.Fatal("expected the request to be dequeued")
+	}
+
+	if due, err := r.ResponseQueue.Due(); err != nil || len(due) != 0 {
+		t.Fatalf("expected the delivered response to be dequeued, got %d, err: %v", len(due), err)
+	}
+}
+
+// countingHubChain is a Hub chain counting the requests sent
+type countingHubChain struct {
+	HubChainI
+
+	requests int
+}
+
+func (c *countingHubChain) SendInterchainRequest(request InterchainRequest, cb ResponseCallback) (InterchainRequestInfo, error) {
+	c.requests++
+
+	return InterchainRequestInfo{}, nil
+}
+
+func TestHandleInterchainRequestQueues(t *testing.T) {
+	if err := txstore.InitLedger(ledger.Config{Driver: ledger.DriverSQLite, DSN: ":memory:"}); err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	dir, err := ioutil.TempDir("", "relayer-handler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hub := &countingHubChain{}
+	r := &Relayer{
+		HubChain:  hub,
+		Queue:     NewRequestQueue(s, DefaultRequestMaxAttempts),
+		Processed: NewProcessedIndex(s),
+		Logger:    logging.Logger,
+	}
+
+	if err := r.HandleInterchainRequest("eth1", InterchainRequest{ID: "req1"}, "0x01"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the submission is left to the queue worker
+	if hub.requests != 0 {
+		t.Fatalf("expected the request not to be submitted by the listener, got %d submissions", hub.requests)
+	}
+
+	if !r.Queue.Has("eth1", "req1") {
+		t.Fatal("expected the request to be queued")
+	}
+}
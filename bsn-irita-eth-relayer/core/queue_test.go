@@ -0,0 +1,306 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"relayer/logging"
+	"relayer/store"
+)
+
+// mockAppChain is an app chain counting the responses sent
+type mockAppChain struct {
+	AppChainI
+
+	responses int
+	err       error
+}
+
+func (c *mockAppChain) SendResponse(requestID string, response ResponseI) error {
+	c.responses++
+	return c.err
+}
+
+func TestRequestQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relayer-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := NewRequestQueue(s, 2)
+
+	entry, err := q.Push("eth1", InterchainRequest{ID: "req1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := q.Due()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Request.ID != "req1" {
+		t.Fatalf("expected the pushed entry to be due, got %v", entries)
+	}
+
+	if !q.Acquire(entry) {
+		t.Fatal("expected to acquire the entry")
+	}
+	if q.Acquire(entry) {
+		t.Fatal("expected the in-flight entry not to be acquired")
+	}
+
+	if dead, err := q.Retry(entry, errors.New("hub unavailable")); err != nil || dead {
+		t.Fatalf("expected the request to be rescheduled, dead: %v, err: %v", dead, err)
+	}
+	q.Release(entry)
+
+	entries, err = q.Due()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected the rescheduled entry not to be due, got %v", entries)
+	}
+
+	if err := q.Done(entry); err != nil {
+		t.Fatal(err)
+	}
+	if q.Acquire(entry) {
+		t.Fatal("expected the done entry not to be acquired")
+	}
+
+	// the request is given up after the maximum attempts
+	entry, err = q.Push("eth1", InterchainRequest{ID: "req2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		dead, err := q.Retry(entry, errors.New("rejected by the hub"))
+		if err != nil || dead != (i == 2) {
+			t.Fatalf("unexpected retry %d, dead: %v, err: %v", i, dead, err)
+		}
+	}
+
+	if q.Has("eth1", "req2") {
+		t.Fatal("expected the given up request to be dequeued")
+	}
+	if _, err := s.Get(RequestDeadLetterKey("eth1", "req2")); err != nil {
+		t.Fatalf("expected the given up request to be dead lettered: %s", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	if Backoff(1) != DefaultQueueRetryInterval {
+		t.Fatalf("unexpected first interval: %s", Backoff(1))
+	}
+	if Backoff(2) != 2*DefaultQueueRetryInterval {
+		t.Fatalf("unexpected second interval: %s", Backoff(2))
+	}
+	if Backoff(100) != DefaultQueueMaxRetryInterval {
+		t.Fatalf("expected the interval to be capped, got %s", Backoff(100))
+	}
+}
+
+func TestResponseQueueDeadLetter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relayer-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := NewResponseQueue(s, 2)
+	entry := NewResponseEntry("eth1", "req1", ResponseAdaptor{StatusCode: 200, Output: "ok"})
+
+	dead, err := q.Retry(entry, errors.New("node unavailable"))
+	if err != nil || dead {
+		t.Fatalf("expected the response to be queued, dead: %v, err: %v", dead, err)
+	}
+
+	dead, err = q.Retry(entry, errors.New("node unavailable"))
+	if err != nil || !dead {
+		t.Fatalf("expected the response to be dead lettered, dead: %v, err: %v", dead, err)
+	}
+
+	if q.Acquire(entry) {
+		t.Fatal("expected the dead lettered response not to be queued")
+	}
+
+	deadLetters, err := q.DeadLetters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deadLetters) != 1 || deadLetters[0].Response.Output != "ok" {
+		t.Fatalf("unexpected dead letters: %v", deadLetters)
+	}
+
+	if err := q.Redrive("eth1", "req1"); err != nil {
+		t.Fatal(err)
+	}
+
+	due, err := q.Due()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 1 || due[0].Attempts != 0 {
+		t.Fatalf("expected the redriven response to be due, got %v", due)
+	}
+
+	// the redriven response is dequeued once delivered
+	chain := &mockAppChain{}
+	r := &Relayer{
+		AppChains:     map[string]AppChainI{"eth1": chain},
+		ResponseQueue: q,
+		Processed:     NewProcessedIndex(s),
+		Logger:        logging.Logger,
+	}
+
+	r.deliverResponse(due[0])
+
+	if chain.responses != 1 {
+		t.Fatalf("expected the response to be delivered once, got %d", chain.responses)
+	}
+
+	due, err = q.Due()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("expected the delivered response to be dequeued, got %v", due)
+	}
+
+	if err := q.Redrive("eth1", "req1"); err == nil {
+		t.Fatal("expected the redrive of a nonexistent dead letter to fail")
+	}
+}
+
+func TestProcessedIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relayer-processed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := NewProcessedIndex(s)
+
+	if _, ok, err := index.Get("eth1", "req1"); err != nil || ok {
+		t.Fatalf("expected the request not to be processed, ok: %v, err: %v", ok, err)
+	}
+
+	info := InterchainRequestInfo{HubReqTxId: "tx1", IcRequestId: "hubreq1"}
+	if err := index.Add("eth1", InterchainRequest{ID: "req1"}, info); err != nil {
+		t.Fatal(err)
+	}
+
+	processed, ok, err := index.Get("eth1", "req1")
+	if err != nil || !ok {
+		t.Fatalf("expected the request to be processed, ok: %v, err: %v", ok, err)
+	}
+	if processed.HubReqTxID != "tx1" || processed.IcRequestID != "hubreq1" {
+		t.Fatalf("unexpected processed request: %+v", processed)
+	}
+
+	if _, ok, _ := index.Get("eth2", "req1"); ok {
+		t.Fatal("expected the request of another chain not to be processed")
+	}
+
+	if err := index.MarkSubmitting("eth1", "req2"); err != nil {
+		t.Fatal(err)
+	}
+
+	first, ok, err := index.Submitting("eth1", "req2")
+	if err != nil || !ok {
+		t.Fatalf("expected the request to be submitting, ok: %v, err: %v", ok, err)
+	}
+
+	// the first submission time is kept
+	if err := s.SetInt64(SubmittingKey("eth1", "req2"), first.Unix()-60); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.MarkSubmitting("eth1", "req2"); err != nil {
+		t.Fatal(err)
+	}
+	if since, _, _ := index.Submitting("eth1", "req2"); since.Unix() != first.Unix()-60 {
+		t.Fatalf("expected the first submission time to be kept, got %v", since)
+	}
+
+	if err := index.Add("eth1", InterchainRequest{ID: "req2"}, info); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := index.Submitting("eth1", "req2"); ok {
+		t.Fatal("expected the submission marker to be removed once processed")
+	}
+}
+
+func TestProcessedIndexPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relayer-processed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := NewProcessedIndex(s)
+
+	if err := index.Add("eth1", InterchainRequest{ID: "req2"}, InterchainRequestInfo{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// req1 was processed beyond the retention
+	bz, _ := json.Marshal(ProcessedRequest{SourceChainID: "eth1", RequestID: "req1", ProcessedTime: 1})
+	if err := s.Set(ProcessedKey("eth1", "req1"), bz); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SetInt64(SubmittingKey("eth1", "req3"), 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.MarkSubmitting("eth1", "req4"); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := index.Prune(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 records to be pruned, got %d", count)
+	}
+
+	if _, ok, _ := index.Get("eth1", "req1"); ok {
+		t.Fatal("expected req1 to be pruned")
+	}
+	if _, ok, _ := index.Get("eth1", "req2"); !ok {
+		t.Fatal("expected req2 to be kept")
+	}
+	if _, ok, _ := index.Submitting("eth1", "req3"); ok {
+		t.Fatal("expected the submission marker of req3 to be pruned")
+	}
+	if _, ok, _ := index.Submitting("eth1", "req4"); !ok {
+		t.Fatal("expected the submission marker of req4 to be kept")
+	}
+}
@@ -1,43 +1,72 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	"relayer/metrics"
+	"relayer/store"
 )
 
 // Relayer represents a relayer transmitting msgs
-// from app chains with the same architecture
+// from app chains of the hosted types
 // to the Hub chain
 type Relayer struct {
-	AppChainType    string
+	AppChainTypes   []string          // hosted app chain types, the first is the default
+	ChainTypes      map[string]string // app chain type by chain ID
 	HubChain        HubChainI
 	AppChains       map[string]AppChainI
 	AppChainStates  map[string]bool
 	AppChainFactory AppChainFactoryI
+	Queue           *RequestQueue
+	ResponseQueue   *ResponseQueue
+	Subscriptions   *SubscriptionStore
+	Processed       *ProcessedIndex
+	Retention       time.Duration // period to keep the processed requests for
 	Logger          *log.Logger
-	mtx             sync.Mutex
+	mtx             sync.RWMutex // guards the app chains and their states
 }
 
 // NewRelayer constructs a new Relayer instance
-func NewRelayer(appChainType string, hub HubChainI, appChainFactory AppChainFactoryI, logger *log.Logger) *Relayer {
+func NewRelayer(appChainTypes []string, hub HubChainI, appChainFactory AppChainFactoryI, store *store.Store, logger *log.Logger) *Relayer {
 	return &Relayer{
-		AppChainType:    appChainType,
+		AppChainTypes:   appChainTypes,
+		ChainTypes:      map[string]string{},
 		HubChain:        hub,
 		AppChainFactory: appChainFactory,
+		Queue:           NewRequestQueue(store, DefaultRequestMaxAttempts),
+		ResponseQueue:   NewResponseQueue(store, DefaultResponseMaxAttempts),
+		Subscriptions:   NewSubscriptionStore(store),
+		Processed:       NewProcessedIndex(store),
+		Retention:       DefaultRetention,
 		Logger:          logger,
 		AppChains:       map[string]AppChainI{},
 		AppChainStates:  map[string]bool{},
 	}
 }
 
+// chainTypeParams carries the chain type in the app chain params
+type chainTypeParams struct {
+	ChainType string `json:"chain_type"`
+}
+
 // AddChain adds an app chain with the specified app chain params
+// The chain type is taken from the chain_type of the params, the default type is used if absent
 func (r *Relayer) AddChain(appChainParams []byte) (chainID string, err error) {
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 
-	chainID, err = r.AppChainFactory.GetChainID(r.AppChainType, appChainParams)
+	chainType, err := r.resolveChainType(appChainParams)
+	if err != nil {
+		return "", err
+	}
+
+	chainID, err = r.AppChainFactory.GetChainID(chainType, appChainParams)
 	if err != nil {
 		return "", err
 	}
@@ -47,7 +76,7 @@ func (r *Relayer) AddChain(appChainParams []byte) (chainID string, err error) {
 		return "", fmt.Errorf("chain ID %s already exists", chainID)
 	}
 
-	chain, err := r.AppChainFactory.BuildAppChain(r.AppChainType, appChainParams)
+	chain, err := r.AppChainFactory.BuildAppChain(chainType, appChainParams)
 	if err != nil {
 		return "", err
 	}
@@ -58,10 +87,78 @@ func (r *Relayer) AddChain(appChainParams []byte) (chainID string, err error) {
 
 	r.AppChains[chainID] = chain
 	r.AppChainStates[chainID] = true
+	r.ChainTypes[chainID] = chainType
 
 	return chainID, nil
 }
 
+// RestoreChain builds and starts the persisted app chain of the given type
+func (r *Relayer) RestoreChain(chainType string, chainID string, appChainParams []byte) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if !r.IsChainTypeHosted(chainType) {
+		return fmt.Errorf("chain type %s is not hosted by the relayer", chainType)
+	}
+
+	chain, err := r.AppChainFactory.BuildAppChain(chainType, appChainParams)
+	if err != nil {
+		return err
+	}
+
+	if err := chain.Start(r.HandleInterchainRequest); err != nil {
+		return err
+	}
+
+	r.AppChains[chainID] = chain
+	r.AppChainStates[chainID] = true
+	r.ChainTypes[chainID] = chainType
+
+	return nil
+}
+
+// IsChainTypeHosted checks if the given chain type is hosted by the relayer
+func (r *Relayer) IsChainTypeHosted(chainType string) bool {
+	for _, t := range r.AppChainTypes {
+		if strings.EqualFold(t, chainType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetChainType gets the type of the specified app chain
+func (r *Relayer) GetChainType(chainID string) string {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r.ChainTypes[chainID]
+}
+
+// resolveChainType determines the chain type of the given app chain params
+func (r *Relayer) resolveChainType(appChainParams []byte) (string, error) {
+	var params chainTypeParams
+	if err := json.Unmarshal(appChainParams, &params); err != nil {
+		return "", fmt.Errorf("invalid chain params: %s", err)
+	}
+
+	chainType := strings.ToLower(params.ChainType)
+	if len(chainType) == 0 {
+		if len(r.AppChainTypes) == 0 {
+			return "", fmt.Errorf("no app chain type configured")
+		}
+
+		return r.AppChainTypes[0], nil
+	}
+
+	if !r.IsChainTypeHosted(chainType) {
+		return "", fmt.Errorf("chain type %s is not hosted by the relayer, hosted types: %s", chainType, strings.Join(r.AppChainTypes, ", "))
+	}
+
+	return chainType, nil
+}
+
 // DeleteChain delete a app chain for the relayer
 func (r *Relayer) DeleteChain(chainID string) error {
 	r.mtx.Lock()
@@ -83,7 +180,9 @@ func (r *Relayer) DeleteChain(chainID string) error {
 	chain.Close()
 	delete(r.AppChains, chainID)
 	delete(r.AppChainStates, chainID)
-	r.AppChainFactory.DeleteChainConfig(r.AppChainType, chainID)
+	metrics.RemoveChain(chainID)
+	r.AppChainFactory.DeleteChainConfig(r.ChainTypes[chainID], chainID)
+	delete(r.ChainTypes, chainID)
 
 	return nil
 }
@@ -138,6 +237,9 @@ func (r *Relayer) StopChain(chainID string) error {
 
 // GetChain gets the specified app chain
 func (r *Relayer) GetChain(chainID string) (appChain AppChainI, err error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
 	appChain, ok := r.AppChains[chainID]
 	if !ok {
 		return nil, fmt.Errorf("chain ID %s does not exist", chainID)
@@ -150,8 +252,8 @@ func (r *Relayer) GetChain(chainID string) (appChain AppChainI, err error) {
 func (r *Relayer) GetChains() []string {
 	chains := make([]string, 0)
 
-	r.mtx.Lock()
-	defer r.mtx.Unlock()
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
 
 	for c, s := range r.AppChainStates {
 		if s {
@@ -162,14 +264,33 @@ func (r *Relayer) GetChains() []string {
 	return chains
 }
 
-// GetChainStatus gets the status of the specified app chain
-func (r *Relayer) GetChainStatus(chainID string) (state bool, height int64, err error) {
+// GetHubEndpoints gets the status of the Hub endpoints
+func (r *Relayer) GetHubEndpoints() []HubEndpointStatus {
+	return r.HubChain.GetEndpoints()
+}
+
+// GetChainStatus gets the status and the active node of the specified app chain
+func (r *Relayer) GetChainStatus(chainID string) (state bool, height int64, node string, err error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
 	state, ok := r.AppChainStates[chainID]
 	if !ok {
-		return state, height, fmt.Errorf("chain ID %s does not exist", chainID)
+		return state, height, node, fmt.Errorf("chain ID %s does not exist", chainID)
 	}
 
 	height = r.AppChains[chainID].GetHeight()
+	node = r.AppChains[chainID].GetActiveNode()
+
+	return state, height, node, nil
+}
+
+// GetDeadLetters retrieves the responses failed to be delivered after the maximum attempts
+func (r *Relayer) GetDeadLetters() ([]*ResponseEntry, error) {
+	return r.ResponseQueue.DeadLetters()
+}
 
-	return state, height, nil
+// RedriveDeadLetter requeues the dead lettered response of the specified request
+func (r *Relayer) RedriveDeadLetter(chainID string, requestID string) error {
+	return r.ResponseQueue.Redrive(chainID, requestID)
 }
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestResolveChainType(t *testing.T) {
+	r := &Relayer{AppChainTypes: []string{"eth", "fisco"}}
+
+	chainType, err := r.resolveChainType([]byte(`{"chain_id":"1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chainType != "eth" {
+		t.Fatalf("expected the default chain type eth, got %s", chainType)
+	}
+
+	chainType, err = r.resolveChainType([]byte(`{"chain_type":"FISCO","chain_id":"1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chainType != "fisco" {
+		t.Fatalf("expected the chain type fisco, got %s", chainType)
+	}
+
+	if _, err := r.resolveChainType([]byte(`{"chain_type":"opb"}`)); err == nil {
+		t.Fatal("expected the chain type not hosted to be rejected")
+	}
+
+	if _, err := r.resolveChainType([]byte(`invalid`)); err == nil {
+		t.Fatal("expected the invalid params to be rejected")
+	}
+}
+
+func TestSendResponseConcurrentWithChainUpdates(t *testing.T) {
+	chain := &mockAppChain{}
+	r := &Relayer{AppChains: map[string]AppChainI{"eth1": chain}, AppChainStates: map[string]bool{}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		// the chains are added and stopped meanwhile
+		for i := 0; i < 100; i++ {
+			r.mtx.Lock()
+			r.AppChains[fmt.Sprintf("eth%d", i+2)] = &mockAppChain{}
+			r.AppChainStates["eth1"] = i%2 == 0
+			r.mtx.Unlock()
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		if err := r.sendResponse("eth1", "req1", ResponseAdaptor{StatusCode: 200}); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _ = r.GetChain("eth1")
+		_ = r.GetChains()
+	}
+
+	<-done
+
+	if chain.responses != 100 {
+		t.Fatalf("expected 100 responses, got %d", chain.responses)
+	}
+}
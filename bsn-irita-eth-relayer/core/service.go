@@ -0,0 +1,71 @@
+package core
+
+import (
+	"time"
+)
+
+// ServiceDefinition defines the service definition on the Hub
+type ServiceDefinition struct {
+	ServiceName       string   `json:"service_name"`
+	Description       string   `json:"description"`
+	Tags              []string `json:"tags"`
+	Author            string   `json:"author,omitempty"` // the key address of the definer, ignored on defining
+	AuthorDescription string   `json:"author_description"`
+	Schemas           string   `json:"schemas"` // input and output schemas
+}
+
+// ServiceBinding defines the service binding of a provider on the Hub
+type ServiceBinding struct {
+	ServiceName string `json:"service_name"`
+	Provider    string `json:"provider"` // default to the key address
+	Deposit     string `json:"deposit"`
+	Pricing     string `json:"pricing"`
+	QoS         uint64 `json:"qos"`     // quality of service, in terms of the minimum response time
+	Options     string `json:"options"` // ignored on updating
+}
+
+// ServiceBindingStatus defines the service binding along with its state on the Hub
+type ServiceBindingStatus struct {
+	ServiceBinding
+
+	Available    bool      `json:"available"`
+	DisabledTime time.Time `json:"disabled_time"`
+	Owner        string    `json:"owner"`
+}
+
+// ServiceStatus defines the service definition and bindings on the Hub
+type ServiceStatus struct {
+	Definition ServiceDefinition      `json:"definition"`
+	Bindings   []ServiceBindingStatus `json:"bindings"`
+}
+
+// ServiceManager defines the service definition and binding management interface on the Hub
+// The service name, schemas, pricing and QoS default to the service config if empty
+type ServiceManager interface {
+	// define the service, returning the tx hash
+	DefineService(definition ServiceDefinition) (txHash string, err error)
+
+	// bind the service with the deposit, returning the tx hash
+	BindService(binding ServiceBinding) (txHash string, err error)
+
+	// update the service binding, the empty fields are left unchanged
+	UpdateServiceBinding(binding ServiceBinding) (txHash string, err error)
+
+	// disable the service binding
+	DisableServiceBinding(serviceName string, provider string) (txHash string, err error)
+
+	// enable the disabled service binding, topping up the deposit if given
+	EnableServiceBinding(serviceName string, provider string, deposit string) (txHash string, err error)
+
+	// refund the deposit of the disabled service binding
+	RefundServiceDeposit(serviceName string, provider string) (txHash string, err error)
+
+	// withdraw the fees earned by the provider
+	WithdrawEarnedFees(provider string) (txHash string, err error)
+
+	// query the fees earned by the provider and not yet withdrawn
+	QueryEarnedFees(provider string) (fees string, err error)
+
+	// get the service definition and bindings
+	GetService(serviceName string) (ServiceStatus, error)
+}
@@ -3,23 +3,65 @@ module relayer
 go 1.14
 
 require (
+	github.com/FISCO-BCOS/go-sdk v0.11.0
+	github.com/Knetic/govaluate v3.0.1-0.20171022003610-9aa49832a739+incompatible // indirect
+	github.com/Shopify/sarama v1.28.0 // indirect
+	github.com/StackExchange/wmi v0.0.0-20190523213315-cbe66965904d // indirect
+	github.com/VividCortex/gohistogram v1.0.0 // indirect
+	github.com/aristanetworks/goarista v0.0.0-20210107181124-fad53805024e // indirect
+	github.com/cloudflare/cfssl v0.0.0-20180202232422-27b05afbb513 // indirect
 	github.com/cockroachdb/pebble v0.0.0-20201118202804-75ede898b66c
 	github.com/ethereum/go-ethereum v1.9.18
+	github.com/fsouza/go-dockerclient v1.7.2 // indirect
 	github.com/gin-gonic/gin v1.4.0
-	github.com/go-sql-driver/mysql v1.4.1
+	github.com/go-ole/go-ole v1.2.5 // indirect
+	github.com/go-sql-driver/mysql v1.5.0
+	github.com/golang/protobuf v1.4.3
+	github.com/golang/snappy v0.0.3-0.20201103224600-674baa8c7fc3 // indirect
+	github.com/google/certificate-transparency-go v1.0.21 // indirect
+	github.com/google/uuid v1.1.2
+	github.com/hyperledger/fabric v1.4.3 // indirect
+	github.com/hyperledger/fabric-amcl v0.0.0-20210319225857-000ace5745f9 // indirect
+	github.com/hyperledger/fabric-lib-go v1.0.0 // indirect
+	github.com/hyperledger/fabric-sdk-go v1.0.0-alpha5
 	github.com/irisnet/service-sdk-go v1.0.1-0.20210416090657-1bdf41efe743
+	github.com/lib/pq v1.10.0
+	github.com/mattn/go-sqlite3 v1.14.6
+	github.com/miekg/pkcs11 v1.0.3
+	github.com/op/go-logging v0.0.0-20160315200505-970db520ece7 // indirect
+	github.com/pborman/uuid v1.2.0
 	github.com/pelletier/go-toml v1.6.0 // indirect
-	github.com/sirupsen/logrus v1.6.0
+	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.8.0
+	github.com/shirou/gopsutil v3.20.12+incompatible // indirect
+	github.com/sirupsen/logrus v1.7.0
 	github.com/spf13/afero v1.2.2 // indirect
 	github.com/spf13/cast v1.3.1 // indirect
 	github.com/spf13/cobra v1.1.1
 	github.com/spf13/jwalterweatherman v1.1.0 // indirect
 	github.com/spf13/viper v1.7.1
+	github.com/stretchr/testify v1.7.0 // indirect
+	github.com/sykesm/zap-logfmt v0.0.4 // indirect
+	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
+	google.golang.org/grpc v1.35.0
+	relayer/appchains/adapter/adapterpb v0.0.0
+	relayer/appchains/fabric/blockparser v0.0.0
+	relayer/appchains/fabric/redconfig v0.0.0
+	relayer/appchains/fisco v0.0.0
 )
 
 replace (
+	github.com/go-kit/kit => github.com/go-kit/kit v0.8.0
 	github.com/gogo/protobuf => github.com/regen-network/protobuf v1.3.2-alpha.regen.4
+	github.com/golang/protobuf => github.com/golang/protobuf v1.3.1
 	github.com/keybase/go-keychain => github.com/99designs/go-keychain v0.0.0-20191008050251-8e49817e8af4
+	github.com/prometheus/client_golang => github.com/prometheus/client_golang v0.9.2
 	github.com/tendermint/tendermint => github.com/bianjieai/tendermint v0.34.1-irita-210113
 	github.com/ugorji/go => github.com/ugorji/go v1.1.2
+	google.golang.org/genproto => google.golang.org/genproto v0.0.0-20200513103714-09dca8ec2884
+	google.golang.org/grpc => google.golang.org/grpc v1.31.0
+	relayer/appchains/adapter/adapterpb => ../bsn-irita-appchains/adapter/adapterpb
+	relayer/appchains/fabric/blockparser => ../bsn-irita-appchains/fabric/blockparser
+	relayer/appchains/fabric/redconfig => ../bsn-irita-appchains/fabric/redconfig
+	relayer/appchains/fisco => ../bsn-irita-appchains/fisco
 )
@@ -36,3 +36,21 @@ type ServiceInput struct {
 	Body   `json:"body"`
 }
 
+
+// ServiceOutput defines the service output responded by the provider
+type ServiceOutput struct {
+	Header `json:"header"`
+	Body   OutputBody `json:"body"`
+}
+
+// OutputBody defines the body of the service output
+type OutputBody struct {
+	TxHash string `json:"tx_hash"`          // hash of the tx calling the destination contract
+	Output string `json:"output,omitempty"` // output of the contract call
+}
+
+// ServiceResult defines the result of the service response
+type ServiceResult struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
@@ -1,7 +1,6 @@
 package hub
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	servicesdk "github.com/irisnet/service-sdk-go"
@@ -11,7 +10,6 @@ import (
 	"relayer/common"
 	"relayer/core"
 	"relayer/logging"
-	"time"
 )
 
 type ServiceInfo struct {
@@ -19,27 +17,28 @@ type ServiceInfo struct {
 	Schemas     string
 	Provider    string
 	ServiceFee  string
+	Timeout     int64
 	QoS         uint64
 }
 
 // IritaHubChain defines the Irita-Hub chain
 type IritaHubChain struct {
-	ChainID     string
-	NodeRPCAddr string
+	ChainID   string
+	Endpoints *EndpointPool // service clients of the Hub endpoints
 
 	KeyPath    string
 	KeyName    string
 	Passphrase string
 
-	ServiceInfo   ServiceInfo
-	ServiceClient servicesdk.ServiceClient
+	ServiceInfo ServiceInfo
+	Routes      []Route // routes to the Hub services, in place of the service config
+	Dispatcher  *ResponseDispatcher
 }
 
 // NewIritaHubChain constructs a new Irita-Hub chain
 func NewIritaHubChain(
 	chainID string,
-	nodeRPCAddr string,
-	nodeGRPCAddr string,
+	endpoints []Endpoint,
 	keyPath string,
 	keyName string,
 	passphrase string,
@@ -47,18 +46,26 @@ func NewIritaHubChain(
 	schemas string,
 	provider string,
 	serviceFee string,
+	timeout uint,
 	qos uint64,
+	routes []Route,
 ) IritaHubChain {
 	if len(chainID) == 0 {
 		chainID = defaultChainID
 	}
 
-	if len(nodeRPCAddr) == 0 {
-		nodeRPCAddr = defaultNodeRPCAddr
+	if len(endpoints) == 0 {
+		endpoints = []Endpoint{{}}
 	}
 
-	if len(nodeGRPCAddr) == 0 {
-		nodeGRPCAddr = defaultNodeGRPCAddr
+	for i := range endpoints {
+		if len(endpoints[i].RPCAddr) == 0 {
+			endpoints[i].RPCAddr = defaultNodeRPCAddr
+		}
+
+		if len(endpoints[i].GRPCAddr) == 0 {
+			endpoints[i].GRPCAddr = defaultNodeGRPCAddr
+		}
 	}
 
 	if len(keyPath) == 0 {
@@ -66,19 +73,23 @@ func NewIritaHubChain(
 	}
 
 	if len(serviceName) == 0 {
-		keyPath = defaultServiceName
+		serviceName = defaultServiceName
 	}
 
 	if len(schemas) == 0 {
-		keyPath = defaultSchemas
+		schemas = defaultSchemas
 	}
 
 	if len(provider) == 0 {
-		keyPath = defaultProvider
+		provider = defaultProvider
 	}
 
 	if len(serviceFee) == 0 {
-		keyPath = defaultServiceFee
+		serviceFee = defaultServiceFee
+	}
+
+	if timeout == 0 {
+		timeout = defaultTimeout
 	}
 
 	if qos == 0 {
@@ -90,21 +101,25 @@ func NewIritaHubChain(
 		panic(err)
 	}
 
-	config := types.ClientConfig{
-		NodeURI:  nodeRPCAddr,
-		GRPCAddr: nodeGRPCAddr,
-		ChainID:  chainID,
-		Gas:      defaultGas,
-		Fee:      fee,
-		Mode:     defaultBroadcastMode,
-		Algo:     defaultKeyAlgorithm,
-		KeyDAO:   store.NewFileDAO(keyPath),
-		Level:    "debug",
+	keyDAO := store.NewFileDAO(keyPath)
+
+	buildClient := func(endpoint Endpoint) servicesdk.ServiceClient {
+		return servicesdk.NewServiceClient(types.ClientConfig{
+			NodeURI:  endpoint.RPCAddr,
+			GRPCAddr: endpoint.GRPCAddr,
+			ChainID:  chainID,
+			Gas:      defaultGas,
+			Fee:      fee,
+			Mode:     defaultBroadcastMode,
+			Algo:     defaultKeyAlgorithm,
+			KeyDAO:   keyDAO,
+			Level:    "debug",
+		})
 	}
 
 	hub := IritaHubChain{
 		ChainID:     chainID,
-		NodeRPCAddr: nodeRPCAddr,
+		Endpoints:   NewEndpointPool(chainID, endpoints, buildClient),
 		KeyPath:     keyPath,
 		KeyName:     keyName,
 		Passphrase:  passphrase,
@@ -113,20 +128,29 @@ func NewIritaHubChain(
 			Schemas:     schemas,
 			Provider:    provider,
 			ServiceFee:  serviceFee,
+			Timeout:     int64(timeout),
 			QoS:         qos,
 		},
-		ServiceClient: servicesdk.NewServiceClient(config),
+		Routes: routes,
 	}
 
+	hub.Dispatcher = NewResponseDispatcher(chainID, hub.Endpoints.Client())
+	hub.Endpoints.OnSwitch(hub.Dispatcher.SetServiceClient)
+
 	return hub
 }
 
 // BuildIritaHubChain builds an Irita-Hub instance from the given config
+// The single node address is used unless the endpoints are specified
 func BuildIritaHubChain(config Config) IritaHubChain {
+	endpoints := config.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = []Endpoint{{RPCAddr: config.NodeRPCAddr, GRPCAddr: config.NodeGRPCAddr}}
+	}
+
 	return NewIritaHubChain(
 		config.ChainID,
-		config.NodeRPCAddr,
-		config.NodeGRPCAddr,
+		endpoints,
 		config.KeyPath,
 		config.KeyName,
 		config.Passphrase,
@@ -134,7 +158,9 @@ func BuildIritaHubChain(config Config) IritaHubChain {
 		config.Schemas,
 		config.Provider,
 		config.ServiceFee,
+		config.Timeout,
 		config.QoS,
+		config.Routes,
 	)
 }
 
@@ -143,6 +169,11 @@ func (ic IritaHubChain) GetChainID() string {
 	return ic.ChainID
 }
 
+// GetEndpoints implements IritaHubChainI
+func (ic IritaHubChain) GetEndpoints() []core.HubEndpointStatus {
+	return ic.Endpoints.Endpoints()
+}
+
 // SendInterchainRequest implements IritaHubChainI
 func (ic IritaHubChain) SendInterchainRequest(
 	request core.InterchainRequest,
@@ -156,17 +187,23 @@ func (ic IritaHubChain) SendInterchainRequest(
 		return info,err
 	}
 
-	reqCtxID, resTx, err := ic.ServiceClient.InvokeService(invokeServiceReq, ic.BuildBaseTx())
+	endpoint := ic.Endpoints.ActiveEndpoint()
+	serviceClient := ic.Endpoints.Client()
+
+	reqCtxID, resTx, err := serviceClient.InvokeService(invokeServiceReq, ic.BuildBaseTx())
 	if err != nil {
+		ic.Endpoints.ReportError(endpoint, err)
 		//mysql.TxErrCollection(request.ID, err.Error())
 		return info,err
 	}
 	info.HubReqTxId=resTx.Hash
+	info.ReqCtxId=reqCtxID
 
 	logging.Logger.Infof("request context created on %s: %s", ic.ChainID, reqCtxID)
 
-	requests, err := ic.ServiceClient.QueryRequestsByReqCtx(reqCtxID, 1)
+	requests, err := serviceClient.QueryRequestsByReqCtx(reqCtxID, 1)
 	if err != nil {
+		ic.Endpoints.ReportError(endpoint, err)
 		return info,err
 	}
 
@@ -187,9 +224,18 @@ func (ic IritaHubChain) SendInterchainRequest(
 func (ic IritaHubChain) BuildServiceInvocationRequest(
 	request core.InterchainRequest,
 ) (service.InvokeServiceRequest, error) {
-	serviceFeeCap, err := types.ParseDecCoins(ic.ServiceInfo.ServiceFee)
+	route, err := ic.ResolveRoute(request)
+	if err != nil {
+		return service.InvokeServiceRequest{}, err
+	}
+
 	destID := common.GetDestID(request.DestChainType, request.DestSubChainID, request.DestChainID)
 
+	timeout := route.Timeout
+	if request.Timeout > 0 {
+		timeout = request.Timeout
+	}
+
 	input := ServiceInput{
 		Header: Header{
 			ReqSequence: request.ID,
@@ -219,59 +265,32 @@ func (ic IritaHubChain) BuildServiceInvocationRequest(
 	}
 
 	return service.InvokeServiceRequest{
-		ServiceName:   ic.ServiceInfo.ServiceName,
-		Providers:     []string{ic.ServiceInfo.Provider},
+		ServiceName:   route.ServiceName,
+		Providers:     route.Providers,
 		Input:         string(serviceInput),
-		Timeout:       100,
-		ServiceFeeCap: serviceFeeCap,
+		Timeout:       timeout,
+		ServiceFeeCap: route.ServiceFeeCap,
 	}, nil
 }
 
-// ResponseListener gets and handles the response of the given request context ID by event subscription
+// ResponseListener gets and handles the response of the given request by the response dispatcher
 func (ic IritaHubChain) ResponseListener(reqCtxID string, requestID string, cb core.ResponseCallback) error {
-	response, err := ic.ServiceClient.QueryServiceResponse(requestID)
-	if response.RequestContextID == reqCtxID {
-		resp := core.ResponseAdaptor{
-			StatusCode: 200,
-			Result:     response.Result,
-			Output:     response.Output,
-		}
-
-		cb(requestID, resp)
+	logging.Logger.Infof("waiting for the service response on %s", ic.ChainID)
 
-		return nil
+	if err := ic.Dispatcher.Register(reqCtxID, requestID, cb); err != nil {
+		return err
 	}
 
-	callbackWrapper := func(reqCtxID, requestID, result string, response string) {
-		resp := core.ResponseAdaptor{
+	// the response may be available before the request is registered
+	response, err := ic.Endpoints.Client().QueryServiceResponse(requestID)
+	if err == nil && response.RequestContextID == reqCtxID {
+		ic.Dispatcher.Dispatch(requestID, core.ResponseAdaptor{
 			StatusCode: 200,
-			Result:     result,
-			Output:     response,
-		}
-
-		cb(requestID, resp)
-	}
-
-	logging.Logger.Infof("waiting for the service response on %s", ic.ChainID)
-
-	subscription, err := ic.ServiceClient.SubscribeServiceResponse(reqCtxID, callbackWrapper)
-	if err != nil {
-		return err
+			Result:     response.Result,
+			Output:     response.Output,
+		})
 	}
 
-	go func() {
-		for {
-			reqCtx, err := ic.ServiceClient.QueryRequestContext(reqCtxID)
-			status, err2 := ic.ServiceClient.Status(context.Background())
-			req, err3 := ic.ServiceClient.QueryServiceRequest(requestID)
-			if err != nil || err2 != nil || err3 != nil || reqCtx.BatchState == "BATCH_COMPLETED" || status.SyncInfo.LatestBlockHeight > req.ExpirationHeight {
-				logging.Logger.Infof("HUB Unsubscribe RequestID is %s", requestID)
-				_ = ic.ServiceClient.Unsubscribe(subscription)
-				break
-			}
-			time.Sleep(time.Second)
-		}
-	}()
 	return nil
 }
 
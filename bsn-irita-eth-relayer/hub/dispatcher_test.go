@@ -0,0 +1,183 @@
+package hub
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/irisnet/service-sdk-go/service"
+	"github.com/irisnet/service-sdk-go/types"
+
+	"relayer/core"
+)
+
+// mockServiceClient is a service client serving the given requests and responses
+type mockServiceClient struct {
+	requests  map[string]service.QueryServiceRequestResponse
+	responses map[string]service.QueryServiceResponseResponse
+
+	subscribeErr  error
+	subscriptions int
+	unsubscribed  int
+}
+
+func (c *mockServiceClient) SubscribeTx(builder *types.EventQueryBuilder, handler types.EventTxHandler) (types.Subscription, types.Error) {
+	if c.subscribeErr != nil {
+		return types.Subscription{}, types.Wrap(c.subscribeErr)
+	}
+
+	c.subscriptions++
+
+	return types.Subscription{ID: "tx"}, nil
+}
+
+func (c *mockServiceClient) SubscribeNewBlockHeader(handler types.EventNewBlockHeaderHandler) (types.Subscription, types.Error) {
+	return types.Subscription{ID: "block"}, nil
+}
+
+func (c *mockServiceClient) Unsubscribe(subscription types.Subscription) types.Error {
+	c.unsubscribed++
+	return nil
+}
+
+func (c *mockServiceClient) QueryServiceRequest(requestID string) (service.QueryServiceRequestResponse, types.Error) {
+	request, ok := c.requests[requestID]
+	if !ok {
+		return request, types.Wrap(errors.New("request not found"))
+	}
+
+	return request, nil
+}
+
+func (c *mockServiceClient) QueryServiceResponse(requestID string) (service.QueryServiceResponseResponse, types.Error) {
+	response, ok := c.responses[requestID]
+	if !ok {
+		return response, types.Wrap(errors.New("response not found"))
+	}
+
+	return response, nil
+}
+
+func TestResponseDispatcherDispatch(t *testing.T) {
+	d := &ResponseDispatcher{chainID: "irita-hub", pending: map[string]*pendingRequest{}}
+
+	responses := 0
+	d.pending["ABCD"] = &pendingRequest{
+		reqCtxID:  "ab",
+		requestID: "abcd",
+		cb: func(icRequestID string, response core.ResponseI) {
+			if icRequestID != "abcd" || response.GetOutput() != "ok" {
+				t.Fatalf("unexpected response of %s: %+v", icRequestID, response)
+			}
+
+			responses++
+		},
+	}
+
+	d.Dispatch("ABCD", core.ResponseAdaptor{StatusCode: 200, Output: "ok"})
+	d.Dispatch("abcd", core.ResponseAdaptor{StatusCode: 200, Output: "ok"})
+
+	if responses != 1 {
+		t.Fatalf("expected the response to be dispatched once, got %d", responses)
+	}
+	if d.Pending() != 0 {
+		t.Fatalf("expected no pending requests, got %d", d.Pending())
+	}
+}
+
+func TestResponseDispatcherExpire(t *testing.T) {
+	client := &mockServiceClient{
+		requests: map[string]service.QueryServiceRequestResponse{
+			"req1": {ID: "req1", ExpirationHeight: 10},
+			"req2": {ID: "req2", ExpirationHeight: 20},
+		},
+		responses: map[string]service.QueryServiceResponseResponse{
+			"req1": {RequestContextID: "ctx1", Output: "ok"},
+		},
+	}
+
+	d := &ResponseDispatcher{chainID: "irita-hub", serviceClient: client, pending: map[string]*pendingRequest{}}
+
+	responses := map[string]core.ResponseI{}
+	cb := func(icRequestID string, response core.ResponseI) {
+		responses[icRequestID] = response
+	}
+
+	d.pending["REQ1"] = &pendingRequest{reqCtxID: "ctx1", requestID: "req1", expirationHeight: 10, cb: cb}
+	d.pending["REQ2"] = &pendingRequest{reqCtxID: "ctx2", requestID: "req2", cb: cb}
+	d.pending["REQ3"] = &pendingRequest{reqCtxID: "ctx3", requestID: "req3", expirationHeight: 10, cb: cb}
+
+	d.onNewBlockHeader(types.EventDataNewBlockHeader{Header: types.Header{Height: 11}})
+
+	// the response found on expiration is dispatched
+	if response, ok := responses["req1"]; !ok || response.GetOutput() != "ok" {
+		t.Fatalf("expected the found response of req1, got %+v", response)
+	}
+
+	if response, ok := responses["req3"]; !ok || !core.IsTimeoutResponse(response) {
+		t.Fatalf("expected the timeout response of req3, got %+v", response)
+	}
+
+	if d.Pending() != 1 {
+		t.Fatalf("expected req2 to be pending, got %d pending", d.Pending())
+	}
+
+	// the unknown expiration height is refreshed
+	if d.pending["REQ2"].expirationHeight != 20 {
+		t.Fatalf("expected the expiration height of req2 to be refreshed, got %d", d.pending["REQ2"].expirationHeight)
+	}
+
+	d.onNewBlockHeader(types.EventDataNewBlockHeader{Header: types.Header{Height: 20}})
+	if d.Pending() != 1 {
+		t.Fatal("expected req2 not to expire at the expiration height")
+	}
+
+	d.onNewBlockHeader(types.EventDataNewBlockHeader{Header: types.Header{Height: 21}})
+	if response, ok := responses["req2"]; !ok || !core.IsTimeoutResponse(response) {
+		t.Fatalf("expected the timeout response of req2, got %+v", response)
+	}
+}
+
+func TestResponseDispatcherResubscribe(t *testing.T) {
+	client := &mockServiceClient{}
+	d := &ResponseDispatcher{chainID: "irita-hub", serviceClient: client, timeout: time.Minute, pending: map[string]*pendingRequest{}}
+
+	if err := d.subscribe(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+
+	// the live subscriptions are kept
+	d.checkSubscription(now)
+	if client.subscriptions != 1 || client.unsubscribed != 0 {
+		t.Fatalf("expected the subscriptions to be kept, got %d subscriptions", client.subscriptions)
+	}
+
+	// the subscriptions without new block headers are renewed
+	now = now.Add(2 * time.Minute)
+	client.subscribeErr = errors.New("connection refused")
+
+	d.checkSubscription(now)
+	if client.unsubscribed != 2 || d.subscribed {
+		t.Fatalf("expected the stale subscriptions to be dropped, got %d unsubscribed", client.unsubscribed)
+	}
+
+	// the failed resubscription is retried with backoff
+	d.checkSubscription(now.Add(time.Second))
+	if d.attempts != 1 {
+		t.Fatalf("expected no retry before the backoff, got %d attempts", d.attempts)
+	}
+
+	client.subscribeErr = nil
+
+	d.checkSubscription(now.Add(core.Backoff(1)))
+	if !d.subscribed || client.subscriptions != 2 || d.attempts != 0 {
+		t.Fatalf("expected to be resubscribed, got %d subscriptions, %d attempts", client.subscriptions, d.attempts)
+	}
+
+	d.onNewBlockHeader(types.EventDataNewBlockHeader{Header: types.Header{Height: 1}})
+	if time.Since(d.lastHeader) > time.Second {
+		t.Fatal("expected the new block header to be recorded")
+	}
+}
@@ -0,0 +1,51 @@
+package hub
+
+import (
+	"errors"
+	"testing"
+
+	"relayer/core"
+)
+
+func TestSelectEndpoint(t *testing.T) {
+	endpoints := []core.HubEndpointStatus{
+		{RPCAddr: "a", Healthy: true, Height: 100, Latency: 20},
+		{RPCAddr: "b", Healthy: true, Height: 103, Latency: 10},
+		{RPCAddr: "c", Healthy: true, Height: 103, Latency: 5},
+	}
+
+	// the active endpoint is sticky within the height lag
+	if i := selectEndpoint(endpoints, 0); i != 0 {
+		t.Fatalf("expected to stay on endpoint 0, got %d", i)
+	}
+
+	// the healthiest endpoint is preferred when the active one lags behind
+	endpoints[0].Height = 90
+	if i := selectEndpoint(endpoints, 0); i != 2 {
+		t.Fatalf("expected to switch to endpoint 2, got %d", i)
+	}
+
+	// the healthiest endpoint is preferred when the active one is unhealthy
+	endpoints[2].Healthy = false
+	if i := selectEndpoint(endpoints, 2); i != 1 {
+		t.Fatalf("expected to switch to endpoint 1, got %d", i)
+	}
+
+	// the next endpoint is tried in turn when all are unhealthy
+	for i := range endpoints {
+		endpoints[i].Healthy = false
+	}
+	if i := selectEndpoint(endpoints, 2); i != 0 {
+		t.Fatalf("expected to try endpoint 0, got %d", i)
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	if !IsConnectionError(errors.New("post failed: dial tcp 127.0.0.1:26657: connect: connection refused")) {
+		t.Fatal("expected the connection error to be detected")
+	}
+
+	if IsConnectionError(errors.New("insufficient fees")) {
+		t.Fatal("expected the tx error not to be a connection error")
+	}
+}
@@ -0,0 +1,41 @@
+package hub
+
+import (
+	"testing"
+
+	"relayer/core"
+)
+
+func TestMatchServiceInput(t *testing.T) {
+	ic := IritaHubChain{
+		ServiceInfo: ServiceInfo{
+			ServiceName: "cc-contract-call",
+			Provider:    "iaa1default",
+			ServiceFee:  "1000000upoint",
+			Timeout:     100,
+		},
+	}
+
+	request := core.InterchainRequest{ID: "req1", SourceChainID: "eth1", DestChainType: "eth"}
+
+	invocation, err := ic.BuildServiceInvocationRequest(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !MatchServiceInput(invocation.Input, request) {
+		t.Fatal("expected the service input to match its request")
+	}
+
+	if MatchServiceInput(invocation.Input, core.InterchainRequest{ID: "req2", SourceChainID: "eth1"}) {
+		t.Fatal("expected the service input not to match another request")
+	}
+
+	if MatchServiceInput(invocation.Input, core.InterchainRequest{ID: "req1", SourceChainID: "eth2"}) {
+		t.Fatal("expected the service input not to match the request of another chain")
+	}
+
+	if MatchServiceInput("invalid", request) {
+		t.Fatal("expected an invalid service input not to match")
+	}
+}
@@ -0,0 +1,113 @@
+package hub
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/irisnet/service-sdk-go/types"
+
+	"relayer/core"
+)
+
+// Route maps the interchain requests matching the patterns to a Hub service
+// The patterns are matched in the glob syntax, and the empty pattern matches any value
+// The empty service fields default to the service config
+type Route struct {
+	DestChainType string   `yaml:"dest_chain_type" mapstructure:"dest_chain_type"`
+	EndpointType  string   `yaml:"endpoint_type" mapstructure:"endpoint_type"`
+	Method        string   `yaml:"method" mapstructure:"method"`
+	ServiceName   string   `yaml:"service_name" mapstructure:"service_name"`
+	Providers     []string `yaml:"providers" mapstructure:"providers"`
+	ServiceFeeCap string   `yaml:"service_fee_cap" mapstructure:"service_fee_cap"`
+	Timeout       int64    `yaml:"timeout" mapstructure:"timeout"` // service timeout in blocks
+}
+
+// ServiceRoute is the Hub service resolved for an interchain request
+type ServiceRoute struct {
+	ServiceName   string
+	Providers     []string
+	ServiceFeeCap types.DecCoins
+	Timeout       int64
+}
+
+// Matches returns true if the given request matches all the patterns of the route
+func (r Route) Matches(request core.InterchainRequest) bool {
+	return matchPattern(r.DestChainType, request.DestChainType) &&
+		matchPattern(r.EndpointType, request.EndpointType) &&
+		matchPattern(r.Method, request.Method)
+}
+
+// ValidateRoutes validates the patterns and fee caps of the given routes
+func ValidateRoutes(routes []Route) error {
+	for i, route := range routes {
+		for _, pattern := range []string{route.DestChainType, route.EndpointType, route.Method} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid pattern %s of route %d: %s", pattern, i, err)
+			}
+		}
+
+		if len(route.ServiceFeeCap) > 0 {
+			if _, err := types.ParseDecCoins(route.ServiceFeeCap); err != nil {
+				return fmt.Errorf("invalid service fee cap of route %d: %s", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ResolveRoute resolves the Hub service of the given request by the first matching route
+// The service config is used if no route matches
+func (ic IritaHubChain) ResolveRoute(request core.InterchainRequest) (ServiceRoute, error) {
+	route := Route{}
+
+	for _, r := range ic.Routes {
+		if r.Matches(request) {
+			route = r
+			break
+		}
+	}
+
+	resolved := ServiceRoute{
+		ServiceName: route.ServiceName,
+		Providers:   route.Providers,
+		Timeout:     route.Timeout,
+	}
+
+	if len(resolved.ServiceName) == 0 {
+		resolved.ServiceName = ic.ServiceInfo.ServiceName
+	}
+
+	if len(resolved.Providers) == 0 {
+		resolved.Providers = []string{ic.ServiceInfo.Provider}
+	}
+
+	if resolved.Timeout == 0 {
+		resolved.Timeout = ic.ServiceInfo.Timeout
+	}
+
+	serviceFeeCap := route.ServiceFeeCap
+	if len(serviceFeeCap) == 0 {
+		serviceFeeCap = ic.ServiceInfo.ServiceFee
+	}
+
+	feeCap, err := types.ParseDecCoins(serviceFeeCap)
+	if err != nil {
+		return ServiceRoute{}, fmt.Errorf("invalid service fee cap %s: %s", serviceFeeCap, err)
+	}
+
+	resolved.ServiceFeeCap = feeCap
+
+	return resolved, nil
+}
+
+// matchPattern returns true if the value matches the glob pattern, or the pattern is empty
+func matchPattern(pattern string, value string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+
+	matched, err := path.Match(pattern, value)
+
+	return err == nil && matched
+}
@@ -0,0 +1,78 @@
+package hub
+
+import (
+	"encoding/json"
+	"testing"
+
+	"relayer/core"
+)
+
+func TestResolveRoute(t *testing.T) {
+	ic := IritaHubChain{
+		ServiceInfo: ServiceInfo{
+			ServiceName: "cc-contract-call",
+			Provider:    "iaa1default",
+			ServiceFee:  "1000000upoint",
+			Timeout:     100,
+		},
+		Routes: []Route{
+			{DestChainType: "fabric", ServiceName: "cc-fabric-call", Providers: []string{"iaa1fabric"}},
+			{DestChainType: "eth", Method: "transfer*", ServiceName: "cc-transfer", ServiceFeeCap: "2000000upoint", Timeout: 50},
+			{EndpointType: "oracle", ServiceName: "oracle", Providers: []string{"iaa1oracle1", "iaa1oracle2"}},
+		},
+	}
+
+	tests := []struct {
+		request     core.InterchainRequest
+		serviceName string
+		providers   int
+		feeCap      string
+		timeout     int64
+	}{
+		{core.InterchainRequest{DestChainType: "fabric", Method: "transfer"}, "cc-fabric-call", 1, "1000000.000000000000000000upoint", 100},
+		{core.InterchainRequest{DestChainType: "eth", Method: "transferFrom"}, "cc-transfer", 1, "2000000.000000000000000000upoint", 50},
+		{core.InterchainRequest{DestChainType: "eth", EndpointType: "oracle", Method: "price"}, "oracle", 2, "1000000.000000000000000000upoint", 100},
+		{core.InterchainRequest{DestChainType: "eth", Method: "hello"}, "cc-contract-call", 1, "1000000.000000000000000000upoint", 100},
+	}
+
+	for i, test := range tests {
+		route, err := ic.ResolveRoute(test.request)
+		if err != nil {
+			t.Fatalf("case %d: %s", i, err)
+		}
+
+		if route.ServiceName != test.serviceName || len(route.Providers) != test.providers ||
+			route.ServiceFeeCap.String() != test.feeCap || route.Timeout != test.timeout {
+			t.Fatalf("case %d: unexpected route %+v", i, route)
+		}
+	}
+
+	// the timeout of the request takes precedence over the route
+	invocation, err := ic.BuildServiceInvocationRequest(core.InterchainRequest{DestChainType: "eth", Method: "transfer", Timeout: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if invocation.ServiceName != "cc-transfer" || invocation.Providers[0] != "iaa1default" || invocation.Timeout != 20 {
+		t.Fatalf("unexpected invocation: %+v", invocation)
+	}
+
+	var input ServiceInput
+	if err := json.Unmarshal([]byte(invocation.Input), &input); err != nil || input.Body.Method != "transfer" {
+		t.Fatalf("unexpected input: %s", invocation.Input)
+	}
+}
+
+func TestValidateRoutes(t *testing.T) {
+	if err := ValidateRoutes([]Route{{DestChainType: "eth", Method: "transfer*", ServiceFeeCap: "1000upoint"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ValidateRoutes([]Route{{Method: "[transfer"}}); err == nil {
+		t.Fatal("expected the malformed pattern to be rejected")
+	}
+
+	if err := ValidateRoutes([]Route{{ServiceFeeCap: "invalid fee"}}); err == nil {
+		t.Fatal("expected the invalid fee cap to be rejected")
+	}
+}
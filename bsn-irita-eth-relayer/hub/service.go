@@ -0,0 +1,256 @@
+package hub
+
+import (
+	"fmt"
+
+	"github.com/irisnet/service-sdk-go/service"
+	"github.com/irisnet/service-sdk-go/types"
+
+	"relayer/core"
+	"relayer/logging"
+)
+
+// defaultOptions is the binding options used if not specified
+const defaultOptions = "{}"
+
+// DefineService implements core.ServiceManager
+func (ic IritaHubChain) DefineService(definition core.ServiceDefinition) (string, error) {
+	request := service.DefineServiceRequest{
+		ServiceName:       ic.serviceName(definition.ServiceName),
+		Description:       definition.Description,
+		Tags:              definition.Tags,
+		AuthorDescription: definition.AuthorDescription,
+		Schemas:           definition.Schemas,
+	}
+
+	if len(request.Schemas) == 0 {
+		request.Schemas = ic.ServiceInfo.Schemas
+	}
+
+	return ic.sendServiceTx("define service "+request.ServiceName, func(client service.Client) (types.ResultTx, types.Error) {
+		return client.DefineService(request, ic.BuildBaseTx())
+	})
+}
+
+// BindService implements core.ServiceManager
+func (ic IritaHubChain) BindService(binding core.ServiceBinding) (string, error) {
+	if len(binding.Deposit) == 0 {
+		return "", fmt.Errorf("deposit can not be empty")
+	}
+
+	deposit, err := types.ParseDecCoins(binding.Deposit)
+	if err != nil {
+		return "", fmt.Errorf("invalid deposit: %s", err)
+	}
+
+	request := service.BindServiceRequest{
+		ServiceName: ic.serviceName(binding.ServiceName),
+		Provider:    binding.Provider,
+		Deposit:     deposit,
+		Pricing:     binding.Pricing,
+		QoS:         binding.QoS,
+		Options:     binding.Options,
+	}
+
+	if len(request.Pricing) == 0 {
+		request.Pricing = ic.defaultPricing()
+	}
+
+	if request.QoS == 0 {
+		request.QoS = ic.ServiceInfo.QoS
+	}
+
+	if len(request.Options) == 0 {
+		request.Options = defaultOptions
+	}
+
+	return ic.sendServiceTx("bind service "+request.ServiceName, func(client service.Client) (types.ResultTx, types.Error) {
+		return client.BindService(request, ic.BuildBaseTx())
+	})
+}
+
+// UpdateServiceBinding implements core.ServiceManager
+func (ic IritaHubChain) UpdateServiceBinding(binding core.ServiceBinding) (string, error) {
+	var deposit types.DecCoins
+
+	if len(binding.Deposit) > 0 {
+		coins, err := types.ParseDecCoins(binding.Deposit)
+		if err != nil {
+			return "", fmt.Errorf("invalid deposit: %s", err)
+		}
+
+		deposit = coins
+	}
+
+	request := service.UpdateServiceBindingRequest{
+		ServiceName: ic.serviceName(binding.ServiceName),
+		Provider:    binding.Provider,
+		Deposit:     deposit,
+		Pricing:     binding.Pricing,
+		QoS:         binding.QoS,
+	}
+
+	return ic.sendServiceTx("update the binding of service "+request.ServiceName, func(client service.Client) (types.ResultTx, types.Error) {
+		return client.UpdateServiceBinding(request, ic.BuildBaseTx())
+	})
+}
+
+// DisableServiceBinding implements core.ServiceManager
+func (ic IritaHubChain) DisableServiceBinding(serviceName string, provider string) (string, error) {
+	serviceName = ic.serviceName(serviceName)
+
+	return ic.sendServiceTx("disable the binding of service "+serviceName, func(client service.Client) (types.ResultTx, types.Error) {
+		return client.DisableServiceBinding(serviceName, provider, ic.BuildBaseTx())
+	})
+}
+
+// EnableServiceBinding implements core.ServiceManager
+func (ic IritaHubChain) EnableServiceBinding(serviceName string, provider string, deposit string) (string, error) {
+	var coins types.DecCoins
+
+	if len(deposit) > 0 {
+		parsed, err := types.ParseDecCoins(deposit)
+		if err != nil {
+			return "", fmt.Errorf("invalid deposit: %s", err)
+		}
+
+		coins = parsed
+	}
+
+	serviceName = ic.serviceName(serviceName)
+
+	return ic.sendServiceTx("enable the binding of service "+serviceName, func(client service.Client) (types.ResultTx, types.Error) {
+		return client.EnableServiceBinding(serviceName, provider, coins, ic.BuildBaseTx())
+	})
+}
+
+// RefundServiceDeposit implements core.ServiceManager
+func (ic IritaHubChain) RefundServiceDeposit(serviceName string, provider string) (string, error) {
+	// the provider is required by the refund, unlike the other binding txs
+	provider, err := ic.provider(provider)
+	if err != nil {
+		return "", err
+	}
+
+	serviceName = ic.serviceName(serviceName)
+
+	return ic.sendServiceTx("refund the deposit of service "+serviceName, func(client service.Client) (types.ResultTx, types.Error) {
+		return client.RefundServiceDeposit(serviceName, provider, ic.BuildBaseTx())
+	})
+}
+
+// WithdrawEarnedFees implements core.ServiceManager
+func (ic IritaHubChain) WithdrawEarnedFees(provider string) (string, error) {
+	provider, err := ic.provider(provider)
+	if err != nil {
+		return "", err
+	}
+
+	return ic.sendServiceTx("withdraw the fees earned by "+provider, func(client service.Client) (types.ResultTx, types.Error) {
+		return client.WithdrawEarnedFees(provider, ic.BuildBaseTx())
+	})
+}
+
+// QueryEarnedFees implements core.ServiceManager
+func (ic IritaHubChain) QueryEarnedFees(provider string) (string, error) {
+	provider, err := ic.provider(provider)
+	if err != nil {
+		return "", err
+	}
+
+	fees, err := ic.Endpoints.Client().QueryFees(provider)
+	if err != nil {
+		return "", fmt.Errorf("failed to query the fees earned by %s: %s", provider, err)
+	}
+
+	return fees.String(), nil
+}
+
+// GetService implements core.ServiceManager
+func (ic IritaHubChain) GetService(serviceName string) (core.ServiceStatus, error) {
+	serviceName = ic.serviceName(serviceName)
+	client := ic.Endpoints.Client()
+
+	definition, err := client.QueryServiceDefinition(serviceName)
+	if err != nil {
+		return core.ServiceStatus{}, fmt.Errorf("failed to query the definition of service %s: %s", serviceName, err)
+	}
+
+	bindings, err := client.QueryServiceBindings(serviceName)
+	if err != nil {
+		return core.ServiceStatus{}, fmt.Errorf("failed to query the bindings of service %s: %s", serviceName, err)
+	}
+
+	status := core.ServiceStatus{
+		Definition: core.ServiceDefinition{
+			ServiceName:       definition.Name,
+			Description:       definition.Description,
+			Tags:              definition.Tags,
+			Author:            definition.Author,
+			AuthorDescription: definition.AuthorDescription,
+			Schemas:           definition.Schemas,
+		},
+		Bindings: make([]core.ServiceBindingStatus, 0, len(bindings)),
+	}
+
+	for _, binding := range bindings {
+		status.Bindings = append(status.Bindings, core.ServiceBindingStatus{
+			ServiceBinding: core.ServiceBinding{
+				ServiceName: binding.ServiceName,
+				Provider:    binding.Provider,
+				Deposit:     binding.Deposit.String(),
+				Pricing:     binding.Pricing,
+				QoS:         binding.QoS,
+				Options:     binding.Options,
+			},
+			Available:    binding.Available,
+			DisabledTime: binding.DisabledTime,
+			Owner:        binding.Owner,
+		})
+	}
+
+	return status, nil
+}
+
+// sendServiceTx sends the service tx by the active endpoint, returning the tx hash
+func (ic IritaHubChain) sendServiceTx(action string, send func(client service.Client) (types.ResultTx, types.Error)) (string, error) {
+	endpoint := ic.Endpoints.ActiveEndpoint()
+
+	resTx, err := send(ic.Endpoints.Client())
+	if err != nil {
+		ic.Endpoints.ReportError(endpoint, err)
+		return "", fmt.Errorf("failed to %s on %s: %s", action, ic.ChainID, err)
+	}
+
+	logging.Logger.Infof("succeeded to %s on %s: %s", action, ic.ChainID, resTx.Hash)
+
+	return resTx.Hash, nil
+}
+
+// provider returns the given provider address, or the key address if empty
+func (ic IritaHubChain) provider(provider string) (string, error) {
+	if len(provider) > 0 {
+		return provider, nil
+	}
+
+	addr, err := ic.ShowKey(ic.KeyName, ic.Passphrase)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the key address: %s", err)
+	}
+
+	return addr, nil
+}
+
+// serviceName returns the given service name, or the configured one if empty
+func (ic IritaHubChain) serviceName(serviceName string) string {
+	if len(serviceName) == 0 {
+		return ic.ServiceInfo.ServiceName
+	}
+
+	return serviceName
+}
+
+// defaultPricing builds the pricing charging the configured service fee per call
+func (ic IritaHubChain) defaultPricing() string {
+	return fmt.Sprintf(`{"price":"%s"}`, ic.ServiceInfo.ServiceFee)
+}
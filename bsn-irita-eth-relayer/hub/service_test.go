@@ -0,0 +1,55 @@
+package hub
+
+import (
+	"encoding/json"
+	"testing"
+
+	"relayer/core"
+)
+
+func TestServiceDefaults(t *testing.T) {
+	ic := IritaHubChain{
+		ServiceInfo: ServiceInfo{ServiceName: "cc-contract-call", ServiceFee: "1000000upoint"},
+	}
+
+	if name := ic.serviceName(""); name != "cc-contract-call" {
+		t.Fatalf("expected the configured service name, got %s", name)
+	}
+
+	if name := ic.serviceName("oracle"); name != "oracle" {
+		t.Fatalf("expected the given service name, got %s", name)
+	}
+
+	var pricing struct {
+		Price string `json:"price"`
+	}
+	if err := json.Unmarshal([]byte(ic.defaultPricing()), &pricing); err != nil || pricing.Price != "1000000upoint" {
+		t.Fatalf("unexpected default pricing: %s", ic.defaultPricing())
+	}
+}
+
+func TestBindServiceDeposit(t *testing.T) {
+	ic := IritaHubChain{}
+
+	// the deposit is validated before any tx is built
+	if _, err := ic.BindService(core.ServiceBinding{}); err == nil {
+		t.Fatal("expected the empty deposit to be rejected")
+	}
+
+	if _, err := ic.BindService(core.ServiceBinding{Deposit: "invalid deposit"}); err == nil {
+		t.Fatal("expected the invalid deposit to be rejected")
+	}
+
+	if _, err := ic.UpdateServiceBinding(core.ServiceBinding{Deposit: "invalid deposit"}); err == nil {
+		t.Fatal("expected the invalid deposit to be rejected")
+	}
+}
+
+func TestServiceProvider(t *testing.T) {
+	ic := IritaHubChain{}
+
+	// the given provider is used as is without resolving the key address
+	if provider, err := ic.provider("iaa1provider"); err != nil || provider != "iaa1provider" {
+		t.Fatalf("expected the given provider, got %s, %v", provider, err)
+	}
+}
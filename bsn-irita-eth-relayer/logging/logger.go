@@ -1,9 +1,12 @@
 package logging
 
 import (
+	stdlog "log"
 	"os"
 
 	log "github.com/sirupsen/logrus"
+
+	"relayer/secrets"
 )
 
 // Logger is a logger instance
@@ -15,4 +18,9 @@ func init() {
 	Logger.SetOutput(os.Stdout)
 
 	Logger.SetLevel(log.InfoLevel)
+
+	// redact the secrets in all log output
+	Logger.AddHook(secrets.RedactHook{})
+	log.AddHook(secrets.RedactHook{})
+	stdlog.SetOutput(secrets.NewRedactWriter(os.Stderr))
 }
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestResultLabels(t *testing.T) {
+	HubSubmitted("eth-1", nil)
+	HubSubmitted("eth-1", errors.New("timeout"))
+	HubSubmitted("eth-1", errors.New("timeout"))
+
+	if v := testutil.ToFloat64(hubSubmissions.WithLabelValues("eth-1", resultSuccess)); v != 1 {
+		t.Fatalf("expected 1 successful submission, got %v", v)
+	}
+
+	if v := testutil.ToFloat64(hubSubmissions.WithLabelValues("eth-1", resultFailure)); v != 2 {
+		t.Fatalf("expected 2 failed submissions, got %v", v)
+	}
+
+	AddPendingRequests("eth-1", 2)
+	AddPendingRequests("eth-1", -1)
+
+	if v := testutil.ToFloat64(pendingRequests.WithLabelValues("eth-1")); v != 1 {
+		t.Fatalf("expected 1 pending request, got %v", v)
+	}
+}
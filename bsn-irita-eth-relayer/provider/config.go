@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+
+	cfg "relayer/config"
+	"relayer/core"
+)
+
+const (
+	Prefix = "provider"
+
+	Enabled     = "enabled"
+	ServiceName = "service_name"
+	Workers     = "workers"
+
+	DefaultServiceName = "cc-contract-call"
+	DefaultWorkers     = 4
+)
+
+// Config represents the provider mode config
+type Config struct {
+	Enabled     bool   `yaml:"enabled"`      // whether to serve as the provider of the service
+	ServiceName string `yaml:"service_name"` // service to provide
+	Workers     int    `yaml:"workers"`      // number of the requests delivered concurrently
+
+	Retention time.Duration `yaml:"-"` // period to keep the responded markers for, taken from the base config
+}
+
+// NewConfig constructs a new Config from viper
+func NewConfig(v *viper.Viper) Config {
+	config := Config{
+		Enabled:     v.GetBool(cfg.GetConfigKey(Prefix, Enabled)),
+		ServiceName: v.GetString(cfg.GetConfigKey(Prefix, ServiceName)),
+		Workers:     v.GetInt(cfg.GetConfigKey(Prefix, Workers)),
+		Retention:   cfg.GetRetention(v),
+	}
+
+	if len(config.ServiceName) == 0 {
+		config.ServiceName = DefaultServiceName
+	}
+
+	if config.Workers <= 0 {
+		config.Workers = DefaultWorkers
+	}
+
+	if config.Retention == 0 {
+		config.Retention = core.DefaultRetention
+	}
+
+	return config
+}
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/irisnet/service-sdk-go/service"
+
+	"relayer/core"
+	"relayer/hub"
+	"relayer/store"
+)
+
+const (
+	KeyPrefixDelivery   = "provider:delivery"
+	KeyPrefixDeadLetter = "provider:deadletter"
+
+	DefaultRespondMaxAttempts = 10 // maximum attempts to respond a delivered request before it is dead lettered
+)
+
+// Delivery defines the result of the request delivered to the destination chain
+// It is persisted before responding, so that the destination contract is called only once
+// and the response alone is retried until it is accepted by the Hub
+type Delivery struct {
+	RequestID   string `json:"request_id"`
+	ReqSequence string `json:"req_sequence"`
+	ChainID     string `json:"chain_id"` // destination chain ID
+	Output      string `json:"output"`
+	TxHash      string `json:"tx_hash"`
+	ErrMsg      string `json:"err_msg"` // delivery error, responded as the service result
+	Attempts    int    `json:"attempts"`
+	NextRetry   int64  `json:"next_retry"`
+	LastError   string `json:"last_error"` // last error of responding
+}
+
+// Response builds the service response of the delivery
+func (d *Delivery) Response() service.InvokeServiceResponseRequest {
+	input := hub.ServiceInput{
+		Header: hub.Header{ReqSequence: d.ReqSequence},
+		Body:   hub.Body{Dest: hub.Dest{ChainID: d.ChainID}},
+	}
+
+	var err error
+	if len(d.ErrMsg) > 0 {
+		err = errors.New(d.ErrMsg)
+	}
+
+	return BuildResponse(input, d.Output, d.TxHash, err)
+}
+
+// DeliveryKey returns the key of the delivery of the given request
+func DeliveryKey(requestID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s", KeyPrefixDelivery, strings.ToUpper(requestID)))
+}
+
+// DeadLetterKey returns the dead letter key of the delivery of the given request
+func DeadLetterKey(requestID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s", KeyPrefixDeadLetter, strings.ToUpper(requestID)))
+}
+
+// DeliveryStore persists the deliveries pending to be responded
+type DeliveryStore struct {
+	store       *store.Store
+	maxAttempts int
+}
+
+// NewDeliveryStore constructs a new DeliveryStore instance
+func NewDeliveryStore(store *store.Store, maxAttempts int) *DeliveryStore {
+	return &DeliveryStore{
+		store:       store,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Get returns the delivery of the given request, false if not delivered
+func (s *DeliveryStore) Get(requestID string) (*Delivery, bool, error) {
+	bz, err := s.store.Get(DeliveryKey(requestID))
+	if err == store.ErrNotFound {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var delivery Delivery
+	if err := json.Unmarshal(bz, &delivery); err != nil {
+		return nil, false, err
+	}
+
+	return &delivery, true, nil
+}
+
+// Save persists the given delivery which is due immediately
+func (s *DeliveryStore) Save(delivery *Delivery) error {
+	delivery.NextRetry = time.Now().Unix()
+
+	return s.save(DeliveryKey(delivery.RequestID), delivery)
+}
+
+// Done removes the delivery of the given request once responded
+func (s *DeliveryStore) Done(requestID string) error {
+	return s.store.Delete(DeliveryKey(requestID))
+}
+
+// Retry records the failed response and reschedules the given delivery with exponential backoff
+// The delivery is moved to the dead letters if the maximum attempts are exceeded, in which case true is returned
+func (s *DeliveryStore) Retry(delivery *Delivery, err error) (bool, error) {
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	delivery.NextRetry = time.Now().Add(core.Backoff(delivery.Attempts)).Unix()
+
+	if delivery.Attempts < s.maxAttempts {
+		return false, s.save(DeliveryKey(delivery.RequestID), delivery)
+	}
+
+	if err := s.save(DeadLetterKey(delivery.RequestID), delivery); err != nil {
+		return false, err
+	}
+
+	return true, s.Done(delivery.RequestID)
+}
+
+// Due returns the deliveries whose retry time has come
+func (s *DeliveryStore) Due() ([]*Delivery, error) {
+	now := time.Now().Unix()
+	deliveries := make([]*Delivery, 0)
+
+	err := s.store.Iterate([]byte(KeyPrefixDelivery+":"), func(key, value []byte) bool {
+		var delivery Delivery
+		if err := json.Unmarshal(value, &delivery); err != nil {
+			return true
+		}
+
+		if delivery.NextRetry <= now {
+			deliveries = append(deliveries, &delivery)
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (s *DeliveryStore) save(key []byte, delivery *Delivery) error {
+	bz, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Set(key, bz)
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"relayer/hub"
+	"relayer/store"
+)
+
+func TestDeliveryStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "provider-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries := NewDeliveryStore(s, 2)
+
+	if _, ok, err := deliveries.Get("req1"); err != nil || ok {
+		t.Fatalf("expected no delivery, got %v, %v", ok, err)
+	}
+
+	if err := deliveries.Save(&Delivery{RequestID: "req1", Output: "ok", TxHash: "0xtx"}); err != nil {
+		t.Fatal(err)
+	}
+
+	delivery, ok, err := deliveries.Get("REQ1")
+	if err != nil || !ok || delivery.TxHash != "0xtx" {
+		t.Fatalf("expected the persisted delivery, got %+v, %v", delivery, err)
+	}
+
+	due, err := deliveries.Due()
+	if err != nil || len(due) != 1 {
+		t.Fatalf("expected the delivery to be due, got %d, %v", len(due), err)
+	}
+
+	dead, err := deliveries.Retry(delivery, errors.New("timeout"))
+	if err != nil || dead {
+		t.Fatalf("expected the delivery to be rescheduled, got %v, %v", dead, err)
+	}
+
+	if due, _ := deliveries.Due(); len(due) != 0 {
+		t.Fatalf("expected the delivery to be rescheduled later, got %d due", len(due))
+	}
+
+	dead, err = deliveries.Retry(delivery, errors.New("timeout"))
+	if err != nil || !dead {
+		t.Fatalf("expected the delivery to be dead lettered, got %v, %v", dead, err)
+	}
+
+	if _, ok, _ := deliveries.Get("req1"); ok {
+		t.Fatal("expected the dead lettered delivery to be removed")
+	}
+
+	if _, err := s.Get(DeadLetterKey("req1")); err != nil {
+		t.Fatalf("expected the dead letter, got %v", err)
+	}
+}
+
+func TestDeliveryResponse(t *testing.T) {
+	delivery := Delivery{RequestID: "req1", ReqSequence: "seq1", ChainID: "ropsten", Output: "ok", TxHash: "0xtx"}
+
+	var output hub.ServiceOutput
+	if err := json.Unmarshal([]byte(delivery.Response().Output), &output); err != nil {
+		t.Fatal(err)
+	}
+	if output.Header.ReqSequence != "seq1" || output.Header.ChainID != "ropsten" || output.Body.TxHash != "0xtx" {
+		t.Fatalf("unexpected output: %+v", output)
+	}
+
+	delivery.ErrMsg = "reverted"
+
+	var result hub.ServiceResult
+	response := delivery.Response()
+	if err := json.Unmarshal([]byte(response.Result), &result); err != nil || result.Code != ResultCodeError || result.Message != "reverted" {
+		t.Fatalf("unexpected result: %s", response.Result)
+	}
+}
@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"relayer/core"
+	"relayer/store"
+)
+
+const (
+	KeyPrefixPending = "provider:pending"
+
+	DefaultDeliverMaxAttempts = 10 // maximum attempts to deliver a request failed transiently before the failure is responded
+)
+
+// PendingRequest defines the request queued to be delivered to the destination chain
+// It is marked delivering before the destination contract is called, so that the delivery
+// interrupted by a restart is known and not made again
+type PendingRequest struct {
+	RequestID   string `json:"request_id"`
+	ReqSequence string `json:"req_sequence"`
+	ChainID     string `json:"chain_id"` // destination chain ID
+	Attempts    int    `json:"attempts"`
+	NextRetry   int64  `json:"next_retry"`
+	LastError   string `json:"last_error"` // last transient error of delivering
+	Delivering  int64  `json:"delivering"` // time when the delivery started, 0 if not in progress
+}
+
+// PendingKey returns the key of the pending request of the given request ID
+func PendingKey(requestID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s", KeyPrefixPending, strings.ToUpper(requestID)))
+}
+
+// PendingStore persists the requests pending to be delivered
+type PendingStore struct {
+	store       *store.Store
+	maxAttempts int
+}
+
+// NewPendingStore constructs a new PendingStore instance
+func NewPendingStore(store *store.Store, maxAttempts int) *PendingStore {
+	return &PendingStore{
+		store:       store,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Queue persists the given request which is due immediately
+// The request already queued is left as it is
+func (s *PendingStore) Queue(requestID string) error {
+	if _, ok, err := s.Get(requestID); err != nil || ok {
+		return err
+	}
+
+	return s.save(&PendingRequest{
+		RequestID: requestID,
+		NextRetry: time.Now().Unix(),
+	})
+}
+
+// Get returns the pending request of the given request ID, false if not queued
+func (s *PendingStore) Get(requestID string) (*PendingRequest, bool, error) {
+	bz, err := s.store.Get(PendingKey(requestID))
+	if err == store.ErrNotFound {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var pending PendingRequest
+	if err := json.Unmarshal(bz, &pending); err != nil {
+		return nil, false, err
+	}
+
+	return &pending, true, nil
+}
+
+// MarkDelivering marks the given request in delivery
+func (s *PendingStore) MarkDelivering(pending *PendingRequest) error {
+	pending.Delivering = time.Now().Unix()
+
+	return s.save(pending)
+}
+
+// Retry records the transient failure and reschedules the given request with exponential backoff
+// True is returned without rescheduling if the maximum attempts are exceeded
+func (s *PendingStore) Retry(pending *PendingRequest, err error) (bool, error) {
+	pending.Attempts++
+	pending.LastError = err.Error()
+	pending.Delivering = 0
+	pending.NextRetry = time.Now().Add(core.Backoff(pending.Attempts)).Unix()
+
+	if pending.Attempts >= s.maxAttempts {
+		return true, nil
+	}
+
+	return false, s.save(pending)
+}
+
+// Done removes the pending request of the given request ID once delivered
+func (s *PendingStore) Done(requestID string) error {
+	return s.store.Delete(PendingKey(requestID))
+}
+
+// Due returns the requests not in delivery whose retry time has come
+func (s *PendingStore) Due() ([]*PendingRequest, error) {
+	now := time.Now().Unix()
+
+	return s.filter(func(pending *PendingRequest) bool {
+		return pending.Delivering == 0 && pending.NextRetry <= now
+	})
+}
+
+// Interrupted returns the requests left in delivery
+func (s *PendingStore) Interrupted() ([]*PendingRequest, error) {
+	return s.filter(func(pending *PendingRequest) bool {
+		return pending.Delivering != 0
+	})
+}
+
+func (s *PendingStore) filter(match func(pending *PendingRequest) bool) ([]*PendingRequest, error) {
+	requests := make([]*PendingRequest, 0)
+
+	err := s.store.Iterate([]byte(KeyPrefixPending+":"), func(key, value []byte) bool {
+		var pending PendingRequest
+		if err := json.Unmarshal(value, &pending); err != nil {
+			return true
+		}
+
+		if match(&pending) {
+			requests = append(requests, &pending)
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+func (s *PendingStore) save(pending *PendingRequest) error {
+	bz, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Set(PendingKey(pending.RequestID), bz)
+}
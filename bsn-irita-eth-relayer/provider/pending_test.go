@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"relayer/store"
+)
+
+func TestPendingStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "provider-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pendings := NewPendingStore(s, 2)
+
+	if err := pendings.Queue("req1"); err != nil {
+		t.Fatal(err)
+	}
+
+	due, err := pendings.Due()
+	if err != nil || len(due) != 1 || due[0].RequestID != "req1" {
+		t.Fatalf("expected the queued request to be due, got %v, %v", due, err)
+	}
+
+	pending := due[0]
+	pending.ChainID = "ropsten"
+
+	if err := pendings.MarkDelivering(pending); err != nil {
+		t.Fatal(err)
+	}
+
+	// queuing again leaves the request in delivery as it is
+	if err := pendings.Queue("REQ1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if due, _ := pendings.Due(); len(due) != 0 {
+		t.Fatalf("expected the request in delivery not to be due, got %d due", len(due))
+	}
+
+	interrupted, err := pendings.Interrupted()
+	if err != nil || len(interrupted) != 1 || interrupted[0].ChainID != "ropsten" {
+		t.Fatalf("expected the request in delivery, got %v, %v", interrupted, err)
+	}
+
+	exhausted, err := pendings.Retry(pending, errors.New("connection refused"))
+	if err != nil || exhausted {
+		t.Fatalf("expected the request to be rescheduled, got %v, %v", exhausted, err)
+	}
+
+	if interrupted, _ := pendings.Interrupted(); len(interrupted) != 0 {
+		t.Fatalf("expected the rescheduled request not in delivery, got %d", len(interrupted))
+	}
+
+	if due, _ := pendings.Due(); len(due) != 0 {
+		t.Fatalf("expected the request to be rescheduled later, got %d due", len(due))
+	}
+
+	exhausted, err = pendings.Retry(pending, errors.New("connection refused"))
+	if err != nil || !exhausted {
+		t.Fatalf("expected the attempts to be exhausted, got %v, %v", exhausted, err)
+	}
+
+	if err := pendings.Done("req1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := pendings.Get("req1"); err != nil || ok {
+		t.Fatalf("expected no pending request, got %v, %v", ok, err)
+	}
+}
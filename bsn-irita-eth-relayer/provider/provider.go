@@ -0,0 +1,635 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	servicesdk "github.com/irisnet/service-sdk-go"
+	"github.com/irisnet/service-sdk-go/service"
+	"github.com/irisnet/service-sdk-go/types"
+
+	txstore "relayer/appchains/store"
+	"relayer/core"
+	"relayer/hub"
+	"relayer/logging"
+	"relayer/store"
+)
+
+const (
+	EventTypeNewBatchRequestProvider = "new_batch_request_provider"
+	AttributeKeyServiceName          = "service_name"
+	AttributeKeyProvider             = "provider"
+	AttributeKeyRequests             = "requests"
+
+	KeyPrefixResponded = "provider:responded"
+
+	ResultCodeSuccess = 200
+	ResultCodeError   = 500
+)
+
+// ChainGetter retrieves the app chain of the given chain ID
+type ChainGetter interface {
+	GetChain(chainID string) (core.AppChainI, error)
+}
+
+// Provider serves the service requests addressed to the relayer on the Hub,
+// by calling the destination contracts on the app chains and responding with the result
+type Provider struct {
+	Config Config
+
+	hub        hub.IritaHubChain
+	chains     ChainGetter
+	store      *store.Store
+	pending    *PendingStore  // requests pending to be delivered
+	deliveries *DeliveryStore // deliveries pending to be responded
+	address    string         // provider address of the Hub key
+
+	serviceClient servicesdk.ServiceClient
+	sub           types.Subscription
+	subscribed    bool
+
+	notify   chan struct{}       // signal of the newly queued requests
+	requests chan string         // requests dispatched to the workers
+	inflight map[string]struct{} // requests being delivered
+	mtx      sync.Mutex
+}
+
+// NewProvider constructs a new Provider instance
+func NewProvider(config Config, hubChain hub.IritaHubChain, chains ChainGetter, store *store.Store) *Provider {
+	return &Provider{
+		Config:        config,
+		hub:           hubChain,
+		chains:        chains,
+		store:         store,
+		pending:       NewPendingStore(store, DefaultDeliverMaxAttempts),
+		deliveries:    NewDeliveryStore(store, DefaultRespondMaxAttempts),
+		serviceClient: hubChain.Endpoints.Client(),
+		notify:        make(chan struct{}, 1),
+		requests:      make(chan string, 1000),
+		inflight:      map[string]struct{}{},
+	}
+}
+
+// Start subscribes to the service requests addressed to the provider and starts the workers
+// The requests left unresponded on the Hub are delivered as well, and the failed responses are retried
+func (p *Provider) Start() error {
+	address, err := p.client().QueryAddress(p.hub.KeyName, p.hub.Passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to query the provider address: %s", err)
+	}
+
+	p.address = address.String()
+
+	if err := p.reconcile(); err != nil {
+		return err
+	}
+
+	for i := 0; i < p.Config.Workers; i++ {
+		go p.work()
+	}
+
+	go p.dispatch()
+
+	if err := p.subscribe(); err != nil {
+		return err
+	}
+
+	p.hub.Endpoints.OnSwitch(p.SetServiceClient)
+
+	go p.recover()
+	go p.retryResponses()
+
+	logging.Logger.Infof("serving %s as the provider %s", p.Config.ServiceName, p.address)
+
+	return nil
+}
+
+// SetServiceClient switches the provider to the given service client
+// The subscription is moved to the new client if subscribed
+func (p *Provider) SetServiceClient(serviceClient servicesdk.ServiceClient) {
+	p.mtx.Lock()
+
+	if p.subscribed {
+		_ = p.serviceClient.Unsubscribe(p.sub)
+	}
+
+	resubscribe := p.subscribed
+	p.serviceClient = serviceClient
+	p.subscribed = false
+
+	p.mtx.Unlock()
+
+	if resubscribe {
+		if err := p.subscribe(); err != nil {
+			logging.Logger.Errorf("failed to resubscribe to the service requests: %s", err)
+			return
+		}
+
+		// the requests may be missed while switching
+		go p.recover()
+	}
+}
+
+// subscribe subscribes to the new blocks with the requests addressed to the provider
+func (p *Provider) subscribe() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.subscribed {
+		return nil
+	}
+
+	builder := types.NewEventQueryBuilder().AddCondition(
+		types.NewCond(EventTypeNewBatchRequestProvider, AttributeKeyServiceName).EQ(types.EventValue(p.Config.ServiceName)),
+	).AddCondition(
+		types.NewCond(EventTypeNewBatchRequestProvider, AttributeKeyProvider).EQ(types.EventValue(p.address)),
+	)
+
+	sub, err := p.serviceClient.SubscribeNewBlock(builder, p.onNewBlock)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to the service requests: %s", err)
+	}
+
+	p.sub = sub
+	p.subscribed = true
+
+	return nil
+}
+
+// onNewBlock queues the requests addressed to the provider in the block
+// The requests are persisted without waiting for the workers, which are signaled to pick them up
+func (p *Provider) onNewBlock(block types.EventDataNewBlock) {
+	for _, requestID := range ParseRequestIDs(block.ResultEndBlock.Events, p.Config.ServiceName, p.address) {
+		p.queue(requestID)
+	}
+}
+
+// recover queues the active requests addressed to the provider
+func (p *Provider) recover() {
+	requests, err := p.client().QueryServiceRequests(p.Config.ServiceName, p.address)
+	if err != nil {
+		logging.Logger.Errorf("failed to query the active service requests: %s", err)
+		return
+	}
+
+	for _, request := range requests {
+		p.queue(request.ID)
+	}
+}
+
+// queue persists the given request to be delivered and signals the dispatcher without blocking
+func (p *Provider) queue(requestID string) {
+	if p.responded(requestID) {
+		return
+	}
+
+	if err := p.pending.Queue(requestID); err != nil {
+		logging.Logger.Errorf("failed to queue the service request %s: %s", requestID, err)
+		return
+	}
+
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch hands the due requests over to the workers when signaled or periodically
+func (p *Provider) dispatch() {
+	ticker := time.NewTicker(core.DefaultQueuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.notify:
+		case <-ticker.C:
+		}
+
+		requests, err := p.pending.Due()
+		if err != nil {
+			logging.Logger.Errorf("failed to load the service requests to be delivered: %s", err)
+			continue
+		}
+
+		for _, pending := range requests {
+			if p.acquire(pending.RequestID) {
+				p.requests <- pending.RequestID
+			}
+		}
+	}
+}
+
+// work delivers the dispatched requests
+func (p *Provider) work() {
+	for requestID := range p.requests {
+		p.handle(requestID)
+		p.release(requestID)
+	}
+}
+
+// handle delivers the request to the destination and responds the result to the Hub
+// The request delivered before is not delivered again, only its result is responded.
+// The request is marked delivering before the destination contract is called, and
+// rescheduled with backoff if the delivery fails transiently
+func (p *Provider) handle(requestID string) {
+	pending, ok, err := p.pending.Get(requestID)
+	if err != nil {
+		logging.Logger.Errorf("failed to load the pending service request %s: %s", requestID, err)
+		return
+	}
+
+	if !ok {
+		return
+	}
+
+	if p.responded(requestID) {
+		p.dequeue(requestID)
+		return
+	}
+
+	delivery, delivered, err := p.deliveries.Get(requestID)
+	if err != nil {
+		logging.Logger.Errorf("failed to load the delivery of the service request %s: %s", requestID, err)
+		return
+	}
+
+	if delivered {
+		logging.Logger.Infof("service request %s already delivered, tx: %s", requestID, delivery.TxHash)
+
+		p.dequeue(requestID)
+		p.respondDelivery(delivery)
+
+		return
+	}
+
+	request, queryErr := p.client().QueryServiceRequest(requestID)
+	if queryErr != nil {
+		logging.Logger.Errorf("failed to query the service request %s: %s", requestID, queryErr)
+
+		if p.retry(pending, queryErr) {
+			p.dequeue(requestID)
+		}
+
+		return
+	}
+
+	if request.Provider != p.address || request.ServiceName != p.Config.ServiceName {
+		p.dequeue(requestID)
+		return
+	}
+
+	var input hub.ServiceInput
+	var output, txHash string
+
+	deliverErr := json.Unmarshal([]byte(request.Input), &input)
+	if deliverErr != nil {
+		deliverErr = fmt.Errorf("invalid service input: %s", deliverErr)
+	} else {
+		pending.ReqSequence = input.Header.ReqSequence
+		pending.ChainID = input.Dest.ChainID
+
+		if err := p.pending.MarkDelivering(pending); err != nil {
+			logging.Logger.Errorf("failed to mark the service request %s delivering: %s", requestID, err)
+			return
+		}
+
+		output, txHash, deliverErr = p.Deliver(input)
+		if core.IsTransient(deliverErr) {
+			logging.Logger.Errorf("failed to deliver the service request %s, to be retried: %s", requestID, deliverErr)
+
+			if !p.retry(pending, deliverErr) {
+				return
+			}
+		}
+	}
+
+	txStatus, errMsg := txstore.TxStatus_Success, ""
+	if deliverErr != nil {
+		txStatus, errMsg = txstore.TxStatus_Error, deliverErr.Error()
+		logging.Logger.Errorf("failed to deliver the service request %s: %s", requestID, deliverErr)
+	}
+
+	delivery = &Delivery{
+		RequestID:   requestID,
+		ReqSequence: input.Header.ReqSequence,
+		ChainID:     input.Dest.ChainID,
+		Output:      output,
+		TxHash:      txHash,
+		ErrMsg:      errMsg,
+	}
+
+	// the result is persisted before responding so that the request is not delivered again
+	if err := p.deliveries.Save(delivery); err != nil {
+		logging.Logger.Errorf("failed to persist the delivery of the service request %s: %s", requestID, err)
+		return
+	}
+
+	p.dequeue(requestID)
+
+	txstore.InitProviderTransRecord(input.Header.ReqSequence, input.Dest.ChainID, requestID, txHash, errMsg, txStatus)
+
+	p.respondDelivery(delivery)
+}
+
+// retry reschedules the given pending request, returning true if the maximum attempts are exceeded
+func (p *Provider) retry(pending *PendingRequest, err error) bool {
+	exhausted, retryErr := p.pending.Retry(pending, err)
+	if retryErr != nil {
+		logging.Logger.Errorf("failed to reschedule the service request %s: %s", pending.RequestID, retryErr)
+	} else if exhausted {
+		logging.Logger.Errorf("delivery of the service request %s given up after %d attempts", pending.RequestID, pending.Attempts)
+	}
+
+	return exhausted
+}
+
+// dequeue removes the given request from the pending requests
+func (p *Provider) dequeue(requestID string) {
+	if err := p.pending.Done(requestID); err != nil {
+		logging.Logger.Errorf("failed to dequeue the service request %s: %s", requestID, err)
+	}
+}
+
+// reconcile resolves the deliveries interrupted by the last shutdown
+// The destination contract may have been called, so the request is not delivered again
+// but responded as failed with the outcome unknown
+func (p *Provider) reconcile() error {
+	requests, err := p.pending.Interrupted()
+	if err != nil {
+		return fmt.Errorf("failed to load the interrupted service requests: %s", err)
+	}
+
+	for _, pending := range requests {
+		if _, delivered, err := p.deliveries.Get(pending.RequestID); err != nil {
+			return fmt.Errorf("failed to load the delivery of the service request %s: %s", pending.RequestID, err)
+		} else if delivered {
+			p.dequeue(pending.RequestID)
+			continue
+		}
+
+		delivery := &Delivery{
+			RequestID:   pending.RequestID,
+			ReqSequence: pending.ReqSequence,
+			ChainID:     pending.ChainID,
+			ErrMsg:      fmt.Sprintf("delivery to %s interrupted, the outcome is unknown", pending.ChainID),
+		}
+
+		if err := p.deliveries.Save(delivery); err != nil {
+			return fmt.Errorf("failed to persist the delivery of the service request %s: %s", pending.RequestID, err)
+		}
+
+		p.dequeue(pending.RequestID)
+
+		txstore.InitProviderTransRecord(pending.ReqSequence, pending.ChainID, pending.RequestID, "", delivery.ErrMsg, txstore.TxStatus_Error)
+
+		logging.Logger.Errorf("delivery of the service request %s interrupted, to be responded as failed", pending.RequestID)
+	}
+
+	return nil
+}
+
+// respondDelivery responds the result of the given delivery to the Hub
+// The response is rescheduled with backoff on failure
+func (p *Provider) respondDelivery(delivery *Delivery) {
+	requestID := delivery.RequestID
+
+	data := &txstore.ProviderResInfo{
+		IcRequestId: requestID,
+		TxStatus:    txstore.TxStatus_Success,
+		ErrMsg:      delivery.ErrMsg,
+	}
+
+	if len(delivery.ErrMsg) > 0 {
+		data.TxStatus = txstore.TxStatus_Error
+	}
+
+	defer func(d *txstore.ProviderResInfo) {
+		txstore.ProviderCallBackTransRecord(d)
+	}(data)
+
+	res, err := p.respond(requestID, delivery.Response())
+	if err != nil {
+		data.TxStatus = txstore.TxStatus_Error
+		data.ErrMsg = fmt.Sprintf("respond to the hub failed :%s", err)
+
+		logging.Logger.Errorf("failed to respond to the service request %s: %s", requestID, err)
+
+		dead, retryErr := p.deliveries.Retry(delivery, err)
+		if retryErr != nil {
+			logging.Logger.Errorf("failed to reschedule the response to the service request %s: %s", requestID, retryErr)
+		} else if dead {
+			logging.Logger.Errorf("response to the service request %s given up after %d attempts", requestID, delivery.Attempts)
+		}
+
+		return
+	}
+
+	data.HUBResTxId = res.Hash
+
+	if err := p.store.Set(respondedKey(requestID), []byte(time.Now().Format(time.RFC3339))); err != nil {
+		logging.Logger.Errorf("failed to mark the service request %s responded: %s", requestID, err)
+	}
+
+	if err := p.deliveries.Done(requestID); err != nil {
+		logging.Logger.Errorf("failed to remove the delivery of the service request %s: %s", requestID, err)
+	}
+
+	logging.Logger.Infof("service request %s responded, hub tx: %s", requestID, res.Hash)
+}
+
+// retryResponses responds the deliveries whose retry time has come
+// The responded markers beyond the retention are pruned periodically as well
+func (p *Provider) retryResponses() {
+	ticker := time.NewTicker(core.DefaultQueuePollInterval)
+	defer ticker.Stop()
+
+	var pruned time.Time
+
+	for now := range ticker.C {
+		if now.Sub(pruned) >= core.DefaultPruneInterval {
+			p.prune(now)
+			pruned = now
+		}
+
+		deliveries, err := p.deliveries.Due()
+		if err != nil {
+			logging.Logger.Errorf("failed to load the deliveries to be responded: %s", err)
+			continue
+		}
+
+		for _, delivery := range deliveries {
+			if !p.acquire(delivery.RequestID) {
+				continue
+			}
+
+			if p.responded(delivery.RequestID) {
+				_ = p.deliveries.Done(delivery.RequestID)
+			} else {
+				p.respondDelivery(delivery)
+			}
+
+			p.release(delivery.RequestID)
+		}
+	}
+}
+
+// prune removes the responded markers beyond the retention
+func (p *Provider) prune(now time.Time) {
+	if p.Config.Retention <= 0 {
+		return
+	}
+
+	before := now.Add(-p.Config.Retention)
+	expired := make([][]byte, 0)
+
+	err := p.store.Iterate([]byte(KeyPrefixResponded+":"), func(key, value []byte) bool {
+		if respondedAt, err := time.Parse(time.RFC3339, string(value)); err == nil && respondedAt.Before(before) {
+			expired = append(expired, key)
+		}
+
+		return true
+	})
+	if err != nil {
+		logging.Logger.Errorf("failed to load the responded markers: %s", err)
+		return
+	}
+
+	for _, key := range expired {
+		if err := p.store.Delete(key); err != nil {
+			logging.Logger.Errorf("failed to prune the responded marker %s: %s", key, err)
+			return
+		}
+	}
+
+	if len(expired) > 0 {
+		logging.Logger.Infof("pruned %d responded markers older than %s", len(expired), p.Config.Retention)
+	}
+}
+
+// Deliver calls the destination contract of the given service input on the app chain
+func (p *Provider) Deliver(input hub.ServiceInput) (output string, txHash string, err error) {
+	chain, err := p.chains.GetChain(input.Dest.ChainID)
+	if err != nil && len(input.Dest.ID) > 0 {
+		chain, err = p.chains.GetChain(input.Dest.ID)
+	}
+
+	if err != nil {
+		return "", "", fmt.Errorf("destination chain %s not found", input.Dest.ChainID)
+	}
+
+	caller, ok := chain.(core.ContractCallerI)
+	if !ok {
+		return "", "", fmt.Errorf("destination chain %s can not be called as the provider", input.Dest.ChainID)
+	}
+
+	return caller.CallContract(input.Dest.EndpointAddress, input.Method, input.CallData)
+}
+
+// respond sends the service response to the Hub
+func (p *Provider) respond(requestID string, response service.InvokeServiceResponseRequest) (types.ResultTx, error) {
+	response.RequestId = requestID
+
+	endpoint := p.hub.Endpoints.ActiveEndpoint()
+
+	res, err := p.client().InvokeServiceResponse(response, p.hub.BuildBaseTx())
+	if err != nil {
+		p.hub.Endpoints.ReportError(endpoint, err)
+		return res, err
+	}
+
+	return res, nil
+}
+
+// BuildResponse builds the service response from the result of the contract call
+// The output is empty on failure as required by the Hub
+func BuildResponse(input hub.ServiceInput, output string, txHash string, err error) service.InvokeServiceResponseRequest {
+	if err != nil {
+		result, _ := json.Marshal(hub.ServiceResult{Code: ResultCodeError, Message: err.Error()})
+		return service.InvokeServiceResponseRequest{Result: string(result)}
+	}
+
+	result, _ := json.Marshal(hub.ServiceResult{Code: ResultCodeSuccess, Message: "success"})
+	serviceOutput, _ := json.Marshal(hub.ServiceOutput{
+		Header: hub.Header{
+			ReqSequence: input.Header.ReqSequence,
+			ChainID:     input.Dest.ChainID,
+		},
+		Body: hub.OutputBody{
+			TxHash: txHash,
+			Output: output,
+		},
+	})
+
+	return service.InvokeServiceResponseRequest{
+		Output: string(serviceOutput),
+		Result: string(result),
+	}
+}
+
+// ParseRequestIDs parses the IDs of the requests addressed to the given provider from the block events
+func ParseRequestIDs(events types.StringEvents, serviceName string, provider string) []string {
+	ids := make([]string, 0)
+
+	for _, e := range events {
+		if e.Type != EventTypeNewBatchRequestProvider {
+			continue
+		}
+
+		attributes := types.Attributes(e.Attributes)
+		if attributes.GetValue(AttributeKeyServiceName) != serviceName || attributes.GetValue(AttributeKeyProvider) != provider {
+			continue
+		}
+
+		var requestIDs []string
+		if err := json.Unmarshal([]byte(attributes.GetValue(AttributeKeyRequests)), &requestIDs); err != nil {
+			logging.Logger.Errorf("failed to parse the service requests of %s: %s", serviceName, err)
+			continue
+		}
+
+		ids = append(ids, requestIDs...)
+	}
+
+	return ids
+}
+
+func (p *Provider) client() servicesdk.ServiceClient {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	return p.serviceClient
+}
+
+// responded returns true if the request has been responded by the provider
+func (p *Provider) responded(requestID string) bool {
+	_, err := p.store.Get(respondedKey(requestID))
+	return err == nil
+}
+
+// acquire marks the request in flight, returning false if it is being delivered
+func (p *Provider) acquire(requestID string) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	key := strings.ToUpper(requestID)
+	if _, ok := p.inflight[key]; ok {
+		return false
+	}
+
+	p.inflight[key] = struct{}{}
+
+	return true
+}
+
+func (p *Provider) release(requestID string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	delete(p.inflight, strings.ToUpper(requestID))
+}
+
+func respondedKey(requestID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s", KeyPrefixResponded, strings.ToUpper(requestID)))
+}
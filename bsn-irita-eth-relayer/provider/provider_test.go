@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/irisnet/service-sdk-go/types"
+
+	"relayer/core"
+	"relayer/hub"
+	"relayer/store"
+)
+
+// mockChain is an app chain able to call the contracts
+type mockChain struct {
+	core.AppChainI
+	chainID string
+	calls   []string
+}
+
+func (c *mockChain) CallContract(endpointAddress string, method string, callData []byte) (string, string, error) {
+	c.calls = append(c.calls, fmt.Sprintf("%s.%s(%s)", endpointAddress, method, callData))
+	return "ok", "0xtx", nil
+}
+
+// mockChains retrieves the chains by ID
+type mockChains map[string]core.AppChainI
+
+func (m mockChains) GetChain(chainID string) (core.AppChainI, error) {
+	chain, ok := m[chainID]
+	if !ok {
+		return nil, fmt.Errorf("chain ID %s does not exist", chainID)
+	}
+
+	return chain, nil
+}
+
+// readOnlyChain is an app chain unable to call the contracts
+type readOnlyChain struct {
+	core.AppChainI
+}
+
+func TestParseRequestIDs(t *testing.T) {
+	events := types.StringEvents{
+		{
+			Type: EventTypeNewBatchRequestProvider,
+			Attributes: []types.Attribute{
+				{Key: AttributeKeyServiceName, Value: "cc-contract-call"},
+				{Key: AttributeKeyProvider, Value: "iaa1provider"},
+				{Key: AttributeKeyRequests, Value: `["req1","req2"]`},
+			},
+		},
+		{
+			Type: EventTypeNewBatchRequestProvider,
+			Attributes: []types.Attribute{
+				{Key: AttributeKeyServiceName, Value: "cc-contract-call"},
+				{Key: AttributeKeyProvider, Value: "iaa1other"},
+				{Key: AttributeKeyRequests, Value: `["req3"]`},
+			},
+		},
+		{
+			Type: "message",
+			Attributes: []types.Attribute{
+				{Key: AttributeKeyRequests, Value: `["req4"]`},
+			},
+		},
+	}
+
+	ids := ParseRequestIDs(events, "cc-contract-call", "iaa1provider")
+	if len(ids) != 2 || ids[0] != "req1" || ids[1] != "req2" {
+		t.Fatalf("expected the requests of the provider, got %v", ids)
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	eth := &mockChain{chainID: "ropsten"}
+	p := &Provider{chains: mockChains{"ropsten": eth, "fisco-1": readOnlyChain{}}}
+
+	input := hub.ServiceInput{
+		Body: hub.Body{
+			Dest:     hub.Dest{ID: "eth-ropsten", ChainID: "ropsten", EndpointAddress: "0xcontract"},
+			Method:   "hello",
+			CallData: []byte("data"),
+		},
+	}
+
+	output, txHash, err := p.Deliver(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "ok" || txHash != "0xtx" || len(eth.calls) != 1 || eth.calls[0] != "0xcontract.hello(data)" {
+		t.Fatalf("unexpected delivery: %s, %s, %v", output, txHash, eth.calls)
+	}
+
+	input.Dest = hub.Dest{ID: "fisco-1", ChainID: "1"}
+	if _, _, err := p.Deliver(input); err == nil {
+		t.Fatal("expected the chain unable to call the contracts to be rejected")
+	}
+
+	input.Dest = hub.Dest{ID: "eth-unknown", ChainID: "unknown"}
+	if _, _, err := p.Deliver(input); err == nil {
+		t.Fatal("expected the unknown chain to be rejected")
+	}
+}
+
+func TestBuildResponse(t *testing.T) {
+	input := hub.ServiceInput{
+		Header: hub.Header{ReqSequence: "req1"},
+		Body:   hub.Body{Dest: hub.Dest{ChainID: "ropsten"}},
+	}
+
+	response := BuildResponse(input, "ok", "0xtx", nil)
+
+	var result hub.ServiceResult
+	if err := json.Unmarshal([]byte(response.Result), &result); err != nil || result.Code != ResultCodeSuccess {
+		t.Fatalf("unexpected result: %s", response.Result)
+	}
+
+	var output hub.ServiceOutput
+	if err := json.Unmarshal([]byte(response.Output), &output); err != nil {
+		t.Fatal(err)
+	}
+	if output.Header.ReqSequence != "req1" || output.Header.ChainID != "ropsten" || output.Body.TxHash != "0xtx" || output.Body.Output != "ok" {
+		t.Fatalf("unexpected output: %s", response.Output)
+	}
+
+	response = BuildResponse(input, "", "", errors.New("reverted"))
+	if err := json.Unmarshal([]byte(response.Result), &result); err != nil || result.Code != ResultCodeError || result.Message != "reverted" {
+		t.Fatalf("unexpected result: %s", response.Result)
+	}
+	if len(response.Output) != 0 {
+		t.Fatalf("expected no output on failure, got %s", response.Output)
+	}
+}
+
+func TestPruneResponded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "provider-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Provider{Config: Config{Retention: time.Hour}, store: s}
+
+	now := time.Now()
+	if err := s.Set(respondedKey("req1"), []byte(now.Add(-2*time.Hour).Format(time.RFC3339))); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set(respondedKey("req2"), []byte(now.Format(time.RFC3339))); err != nil {
+		t.Fatal(err)
+	}
+
+	p.prune(now)
+
+	if p.responded("req1") {
+		t.Fatal("expected the marker beyond the retention to be pruned")
+	}
+	if !p.responded("req2") {
+		t.Fatal("expected the recent marker to be kept")
+	}
+}
+
+func TestQueueAndReconcile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "provider-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Provider{
+		store:      s,
+		pending:    NewPendingStore(s, DefaultDeliverMaxAttempts),
+		deliveries: NewDeliveryStore(s, DefaultRespondMaxAttempts),
+		notify:     make(chan struct{}, 1),
+	}
+
+	// queuing does not block without the dispatcher
+	for _, requestID := range []string{"req1", "req2", "req3"} {
+		p.queue(requestID)
+	}
+
+	due, err := p.pending.Due()
+	if err != nil || len(due) != 3 {
+		t.Fatalf("expected the queued requests to be due, got %d, %v", len(due), err)
+	}
+
+	for _, pending := range due {
+		pending.ChainID = "ropsten"
+		if err := p.pending.MarkDelivering(pending); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := p.deliveries.Save(&Delivery{RequestID: "req2", ChainID: "ropsten", TxHash: "0xtx"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.reconcile(); err != nil {
+		t.Fatal(err)
+	}
+
+	if interrupted, _ := p.pending.Interrupted(); len(interrupted) != 0 {
+		t.Fatalf("expected the interrupted requests to be resolved, got %d", len(interrupted))
+	}
+
+	delivery, ok, err := p.deliveries.Get("req1")
+	if err != nil || !ok || len(delivery.ErrMsg) == 0 || len(delivery.TxHash) != 0 {
+		t.Fatalf("expected the interrupted delivery to be responded as failed, got %+v, %v", delivery, err)
+	}
+
+	delivery, ok, err = p.deliveries.Get("req2")
+	if err != nil || !ok || len(delivery.ErrMsg) != 0 || delivery.TxHash != "0xtx" {
+		t.Fatalf("expected the completed delivery to be kept, got %+v, %v", delivery, err)
+	}
+}
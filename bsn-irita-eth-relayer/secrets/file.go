@@ -0,0 +1,73 @@
+package secrets
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// yamlFieldPattern matches the "key: value # comment" YAML lines
+var yamlFieldPattern = regexp.MustCompile(`^(\s*)([A-Za-z0-9_]+)(:\s*)(.*)$`)
+
+// EncryptFile encrypts the sensitive fields of the given YAML config file in place
+// The layout and comments of the file are preserved. The number of encrypted fields is returned
+func EncryptFile(path string, masterKey []byte) (int, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	lines := strings.Split(string(bz), "\n")
+	count := 0
+
+	for i, line := range lines {
+		matches := yamlFieldPattern.FindStringSubmatch(line)
+		if matches == nil || !IsSensitive(matches[2]) {
+			continue
+		}
+
+		value, comment := splitYAMLValue(matches[4])
+		if len(value) == 0 || IsEncrypted(value) {
+			continue
+		}
+
+		encrypted, err := Encrypt(masterKey, value)
+		if err != nil {
+			return 0, err
+		}
+
+		lines[i] = matches[1] + matches[2] + matches[3] + `"` + encrypted + `"` + comment
+		count++
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	return count, ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600)
+}
+
+// splitYAMLValue splits the scalar value and the trailing comment
+func splitYAMLValue(raw string) (value string, comment string) {
+	raw = strings.TrimRight(raw, " \t\r")
+
+	if strings.HasPrefix(raw, `"`) || strings.HasPrefix(raw, `'`) {
+		quote := raw[:1]
+
+		if end := strings.Index(raw[1:], quote); end >= 0 {
+			return raw[1 : end+1], raw[end+2:]
+		}
+
+		return raw, ""
+	}
+
+	if i := strings.Index(raw, " #"); i >= 0 {
+		return strings.TrimSpace(raw[:i]), raw[i:]
+	}
+
+	if strings.HasPrefix(raw, "#") {
+		return "", raw
+	}
+
+	return raw, ""
+}
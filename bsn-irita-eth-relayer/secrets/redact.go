@@ -0,0 +1,84 @@
+package secrets
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const Redacted = "******"
+
+var redactPatterns = buildRedactPatterns()
+
+// buildRedactPatterns builds the patterns matching the sensitive fields in JSON, YAML and key=value forms
+func buildRedactPatterns() []*regexp.Regexp {
+	keys := strings.Join(SensitiveKeys, "|")
+
+	return []*regexp.Regexp{
+		// "key": "value"
+		regexp.MustCompile(fmt.Sprintf(`(?i)("(?:%s)"\s*:\s*)"(?:[^"\\]|\\.)*"`, keys)),
+		// key: value or key=value
+		regexp.MustCompile(fmt.Sprintf(`(?i)(\b(?:%s)\s*[:=]\s*)[^\s,}"]+`, keys)),
+		// user:password@tcp(host) of the MySQL DSN
+		regexp.MustCompile(`([^\s:/@]+:)[^\s@]+(@tcp\()`),
+	}
+}
+
+// Redact masks the values of the sensitive fields in the given text
+func Redact(s string) string {
+	s = redactPatterns[0].ReplaceAllString(s, `$1"`+Redacted+`"`)
+	s = redactPatterns[1].ReplaceAllString(s, "${1}"+Redacted)
+	s = redactPatterns[2].ReplaceAllString(s, "${1}"+Redacted+"${2}")
+
+	return s
+}
+
+// RedactHook redacts the sensitive fields in the log entries
+type RedactHook struct{}
+
+// Levels implements log.Hook
+func (RedactHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire implements log.Hook
+func (RedactHook) Fire(entry *log.Entry) error {
+	entry.Message = Redact(entry.Message)
+
+	for k, v := range entry.Data {
+		if IsSensitive(k) {
+			entry.Data[k] = Redacted
+			continue
+		}
+
+		if s, ok := v.(string); ok {
+			entry.Data[k] = Redact(s)
+		}
+	}
+
+	return nil
+}
+
+// RedactWriter redacts the sensitive fields written to the underlying writer
+type RedactWriter struct {
+	w io.Writer
+}
+
+// NewRedactWriter constructs a new RedactWriter instance
+func NewRedactWriter(w io.Writer) *RedactWriter {
+	return &RedactWriter{
+		w: w,
+	}
+}
+
+// Write implements io.Writer
+func (rw *RedactWriter) Write(p []byte) (int, error) {
+	if _, err := rw.w.Write([]byte(Redact(string(p)))); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,195 @@
+package secrets
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+const testConfig = `hub:
+    key_name: node0
+    passphrase: 1234567890 # hub key passphrase
+eth:
+    key: "45760456b8181a0c"
+    signers:
+        hsm:
+            pin: '1234'
+            key_label: relayer
+mysql:
+    db_user_passphrase: 123456
+`
+
+func TestEncryptDecrypt(t *testing.T) {
+	masterKey := make([]byte, 32)
+
+	value, err := Encrypt(masterKey, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(value) || strings.Contains(value, "secret") {
+		t.Fatalf("unexpected encrypted value %s", value)
+	}
+
+	plaintext, err := Decrypt(masterKey, value)
+	if err != nil || plaintext != "secret" {
+		t.Fatalf("unexpected decrypted value %s: %v", plaintext, err)
+	}
+
+	wrongKey := make([]byte, 32)
+	wrongKey[0] = 1
+	if _, err := Decrypt(wrongKey, value); err == nil {
+		t.Fatal("expected the wrong master key to be rejected")
+	}
+
+	if plaintext, _ := Decrypt(masterKey, "plain"); plaintext != "plain" {
+		t.Fatal("expected the plain value to be returned as is")
+	}
+}
+
+func TestDerivedMasterKey(t *testing.T) {
+	material := []byte("master")
+
+	value, err := Encrypt(material, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(value, EncryptedScryptPrefix) {
+		t.Fatalf("expected the value encrypted with the derived key, got %s", value)
+	}
+
+	// a new salt is used for each value
+	if other, _ := Encrypt(material, "secret"); other == value {
+		t.Fatal("expected the values to be encrypted with different salts")
+	}
+
+	if plaintext, err := Decrypt(material, value); err != nil || plaintext != "secret" {
+		t.Fatalf("unexpected decrypted value %s: %v", plaintext, err)
+	}
+	if _, err := Decrypt([]byte("wrong"), value); err == nil {
+		t.Fatal("expected the wrong master key to be rejected")
+	}
+
+	// the raw key is used as is
+	rawKey := make([]byte, RawKeySize)
+	if value, _ := Encrypt(rawKey, "secret"); strings.HasPrefix(value, EncryptedScryptPrefix) {
+		t.Fatalf("expected the raw key not to be derived, got %s", value)
+	}
+
+	// the values encrypted with the digest of the material are still decrypted
+	legacyKey := sha256.Sum256(material)
+	legacy, err := Encrypt(legacyKey[:], "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext, err := Decrypt(material, legacy); err != nil || plaintext != "secret" {
+		t.Fatalf("unexpected decrypted legacy value %s: %v", plaintext, err)
+	}
+}
+
+func TestEncryptFile(t *testing.T) {
+	os.Setenv(EnvMasterKey, "master")
+	defer os.Unsetenv(EnvMasterKey)
+
+	masterKey, err := LoadMasterKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := ioutil.WriteFile(path, []byte(testConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := EncryptFile(path, masterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 4 {
+		t.Fatalf("expected 4 fields encrypted, got %d", count)
+	}
+
+	bz, _ := ioutil.ReadFile(path)
+	for _, secret := range []string{"1234567890", "45760456b8181a0c", "'1234'", "123456\n"} {
+		if strings.Contains(string(bz), secret) {
+			t.Fatalf("secret %s not encrypted:\n%s", secret, bz)
+		}
+	}
+	if !strings.Contains(string(bz), "# hub key passphrase") || !strings.Contains(string(bz), "key_label: relayer") {
+		t.Fatalf("layout not preserved:\n%s", bz)
+	}
+
+	// encrypting again is a no-op
+	if count, _ := EncryptFile(path, masterKey); count != 0 {
+		t.Fatalf("expected no field encrypted again, got %d", count)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := DecryptConfig(v); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"hub.passphrase":           "1234567890",
+		"eth.key":                  "45760456b8181a0c",
+		"eth.signers.hsm.pin":      "1234",
+		"mysql.db_user_passphrase": "123456",
+		"hub.key_name":             "node0",
+	}
+	for key, value := range expected {
+		if v.GetString(key) != value {
+			t.Fatalf("expected %s for %s, got %s", value, key, v.GetString(key))
+		}
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	os.Unsetenv(EnvMasterKey)
+
+	bz, err := Seal([]byte(`{"Key":"abc"}`))
+	if err != nil || string(bz) != `{"Key":"abc"}` {
+		t.Fatal("expected the bytes to be stored as is without the master key")
+	}
+
+	os.Setenv(EnvMasterKey, "master")
+	defer os.Unsetenv(EnvMasterKey)
+
+	sealed, err := Seal([]byte(`{"Key":"abc"}`))
+	if err != nil || !IsEncrypted(string(sealed)) {
+		t.Fatal("expected the bytes to be encrypted")
+	}
+
+	opened, err := Open(sealed)
+	if err != nil || string(opened) != `{"Key":"abc"}` {
+		t.Fatalf("unexpected opened bytes %s: %v", opened, err)
+	}
+
+	os.Unsetenv(EnvMasterKey)
+	if _, err := Open(sealed); err != ErrNoMasterKey {
+		t.Fatalf("expected ErrNoMasterKey, got %v", err)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	cases := map[string]string{
+		`AddChain Data is {"nodes":["a"],"passphrase":"wd941014","chainId":"1"}`: `AddChain Data is {"nodes":["a"],"passphrase":"******","chainId":"1"}`,
+		`config {Key:45760456 Passphrase:wd941014 GasLimit:2000000}`:             `config {Key:****** Passphrase:****** GasLimit:2000000}`,
+		`初始化Mysql : root:123456@tcp(localhost:3306)/relayer`:                     `初始化Mysql : root:******@tcp(localhost:3306)/relayer`,
+		`token=abc key_id=relayer`:                                               `token=****** key_id=relayer`,
+	}
+
+	for input, expected := range cases {
+		if output := Redact(input); output != expected {
+			t.Fatalf("expected %s, got %s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"relayer/core"
+)
+
+// ServiceTxResult defines the result of the service tx on the Hub
+type ServiceTxResult struct {
+	TxHash string `json:"tx_hash"`
+}
+
+// EarnedFees defines the fees earned by the provider on the Hub
+type EarnedFees struct {
+	Fees string `json:"fees"`
+}
+
+// ServiceBindingRequest defines the request to disable, enable or refund the deposit of the service binding
+type ServiceBindingRequest struct {
+	Provider string `json:"provider"` // default to the key address
+	Deposit  string `json:"deposit"`  // deposit added on enabling
+}
+
+// DefineService defines the service on the Hub
+func (srv *HTTPService) DefineService(c *gin.Context) {
+	var definition core.ServiceDefinition
+	if err := c.ShouldBindJSON(&definition); err != nil {
+		onError(c, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	txHash, err := srv.ChainManager.DefineService(definition)
+	if err != nil {
+		onError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	onSuccess(c, ServiceTxResult{TxHash: txHash})
+}
+
+// GetService gets the service definition and bindings on the Hub
+func (srv *HTTPService) GetService(c *gin.Context) {
+	status, err := srv.ChainManager.GetService(c.Param("svcname"))
+	if err != nil {
+		onError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	onSuccess(c, status)
+}
+
+// BindService binds the service on the Hub
+func (srv *HTTPService) BindService(c *gin.Context) {
+	var binding core.ServiceBinding
+	if err := c.ShouldBindJSON(&binding); err != nil {
+		onError(c, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	binding.ServiceName = c.Param("svcname")
+
+	txHash, err := srv.ChainManager.BindService(binding)
+	if err != nil {
+		onError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	onSuccess(c, ServiceTxResult{TxHash: txHash})
+}
+
+// UpdateServiceBinding updates the service binding on the Hub
+func (srv *HTTPService) UpdateServiceBinding(c *gin.Context) {
+	var binding core.ServiceBinding
+	if err := c.ShouldBindJSON(&binding); err != nil {
+		onError(c, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	binding.ServiceName = c.Param("svcname")
+
+	txHash, err := srv.ChainManager.UpdateServiceBinding(binding)
+	if err != nil {
+		onError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	onSuccess(c, ServiceTxResult{TxHash: txHash})
+}
+
+// DisableServiceBinding disables the service binding on the Hub
+func (srv *HTTPService) DisableServiceBinding(c *gin.Context) {
+	srv.handleServiceBinding(c, func(serviceName string, request ServiceBindingRequest) (string, error) {
+		return srv.ChainManager.DisableServiceBinding(serviceName, request.Provider)
+	})
+}
+
+// EnableServiceBinding enables the disabled service binding on the Hub
+func (srv *HTTPService) EnableServiceBinding(c *gin.Context) {
+	srv.handleServiceBinding(c, func(serviceName string, request ServiceBindingRequest) (string, error) {
+		return srv.ChainManager.EnableServiceBinding(serviceName, request.Provider, request.Deposit)
+	})
+}
+
+// RefundServiceDeposit refunds the deposit of the disabled service binding on the Hub
+func (srv *HTTPService) RefundServiceDeposit(c *gin.Context) {
+	srv.handleServiceBinding(c, func(serviceName string, request ServiceBindingRequest) (string, error) {
+		return srv.ChainManager.RefundServiceDeposit(serviceName, request.Provider)
+	})
+}
+
+// WithdrawEarnedFees withdraws the fees earned by the provider on the Hub
+func (srv *HTTPService) WithdrawEarnedFees(c *gin.Context) {
+	var request ServiceBindingRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			onError(c, http.StatusBadRequest, "invalid JSON payload")
+			return
+		}
+	}
+
+	txHash, err := srv.ChainManager.WithdrawEarnedFees(request.Provider)
+	if err != nil {
+		onError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	onSuccess(c, ServiceTxResult{TxHash: txHash})
+}
+
+// GetEarnedFees gets the fees earned by the provider on the Hub
+func (srv *HTTPService) GetEarnedFees(c *gin.Context) {
+	fees, err := srv.ChainManager.QueryEarnedFees(c.Query("provider"))
+	if err != nil {
+		onError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	onSuccess(c, EarnedFees{Fees: fees})
+}
+
+// handleServiceBinding sends the service binding tx built from the optional request body
+func (srv *HTTPService) handleServiceBinding(c *gin.Context, send func(serviceName string, request ServiceBindingRequest) (string, error)) {
+	var request ServiceBindingRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			onError(c, http.StatusBadRequest, "invalid JSON payload")
+			return
+		}
+	}
+
+	txHash, err := send(c.Param("svcname"), request)
+	if err != nil {
+		onError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	onSuccess(c, ServiceTxResult{TxHash: txHash})
+}
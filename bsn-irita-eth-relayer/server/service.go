@@ -7,12 +7,15 @@ import (
 // ChainManager defines a service for app chains management
 type ChainManager struct {
 	relayer *core.Relayer
+
+	core.ServiceManager // service definition and binding management on the Hub
 }
 
 // NewChainManager constructs a new ChainManager instance
-func NewChainManager(r *core.Relayer) *ChainManager {
+func NewChainManager(r *core.Relayer, serviceManager core.ServiceManager) *ChainManager {
 	return &ChainManager{
-		relayer: r,
+		relayer:        r,
+		ServiceManager: serviceManager,
 	}
 }
 
@@ -41,7 +44,27 @@ func (cm *ChainManager) GetChains() []string {
 }
 
 // GetChainStatus retrieves the status of the specified app chain
-func (cm *ChainManager) GetChainStatus(chainID string) (state bool, height int64, err error) {
+func (cm *ChainManager) GetChainStatus(chainID string) (state bool, height int64, node string, err error) {
 	return cm.relayer.GetChainStatus(chainID)
 }
 
+// GetChainType retrieves the type of the specified app chain
+func (cm *ChainManager) GetChainType(chainID string) string {
+	return cm.relayer.GetChainType(chainID)
+}
+
+
+// GetHubEndpoints retrieves the status of the Hub endpoints
+func (cm *ChainManager) GetHubEndpoints() []core.HubEndpointStatus {
+	return cm.relayer.GetHubEndpoints()
+}
+
+// GetDeadLetters gets the responses failed to be delivered
+func (cm *ChainManager) GetDeadLetters() ([]*core.ResponseEntry, error) {
+	return cm.relayer.GetDeadLetters()
+}
+
+// RedriveDeadLetter requeues the dead lettered response of the specified request
+func (cm *ChainManager) RedriveDeadLetter(chainID string, requestID string) error {
+	return cm.relayer.RedriveDeadLetter(chainID, requestID)
+}
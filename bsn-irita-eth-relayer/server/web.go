@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"relayer/logging"
+	"relayer/metrics"
 )
 
 // HTTPService represents an HTTP service
@@ -44,9 +45,26 @@ func (srv *HTTPService) createRouter() {
 		eth.POST("/chains/:chainid/stop", srv.StopChain)
 		eth.GET("/chains", srv.GetChains)
 		eth.GET("/chains/:chainid/status", srv.GetChainStatus)
+		eth.GET("/hub/endpoints", srv.GetHubEndpoints)
+		eth.POST("/hub/services", srv.DefineService)
+		eth.GET("/hub/services/:svcname", srv.GetService)
+		eth.POST("/hub/services/:svcname/bindings", srv.BindService)
+		eth.POST("/hub/services/:svcname/bindings/update", srv.UpdateServiceBinding)
+		eth.POST("/hub/services/:svcname/bindings/disable", srv.DisableServiceBinding)
+		eth.POST("/hub/services/:svcname/bindings/enable", srv.EnableServiceBinding)
+		eth.POST("/hub/services/:svcname/bindings/refund-fees", srv.RefundServiceDeposit)
+		eth.POST("/hub/services/:svcname/bindings/refund-deposit", srv.RefundServiceDeposit)
+		eth.GET("/hub/fees", srv.GetEarnedFees)
+		eth.POST("/hub/fees/withdraw", srv.WithdrawEarnedFees)
+		eth.GET("/txs", srv.ListTxs)
+		eth.GET("/txs/lookup", srv.LookupTxs)
+		eth.GET("/txs/stats", srv.GetTxStats)
+		eth.GET("/deadletters", srv.GetDeadLetters)
+		eth.POST("/deadletters/:chainid/:requestid/redrive", srv.RedriveDeadLetter)
 	}
 
 	r.GET("/health", srv.ShowHealth)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
 
 	srv.Router = r
 }
@@ -163,13 +181,54 @@ func (srv *HTTPService) GetChainStatus(c *gin.Context) {
 		return
 	}
 
-	state, height, err := srv.ChainManager.GetChainStatus(chainID)
+	state, height, node, err := srv.ChainManager.GetChainStatus(chainID)
 	if err != nil {
 		onError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	onSuccess(c, ChainStatus{State: state, Height: height})
+	onSuccess(c, ChainStatus{
+		ChainType: srv.ChainManager.GetChainType(chainID),
+		State:     state,
+		Height:    height,
+		Node:      node,
+	})
+}
+
+func (srv *HTTPService) GetHubEndpoints(c *gin.Context) {
+	onSuccess(c, srv.ChainManager.GetHubEndpoints())
+}
+
+func (srv *HTTPService) GetDeadLetters(c *gin.Context) {
+	deadLetters, err := srv.ChainManager.GetDeadLetters()
+	if err != nil {
+		onError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	onSuccess(c, deadLetters)
+}
+
+func (srv *HTTPService) RedriveDeadLetter(c *gin.Context) {
+	chainID := c.Param("chainid")
+	if err := ValidateChainID(chainID); err != nil {
+		onError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	requestID := c.Param("requestid")
+	if len(requestID) == 0 {
+		onError(c, http.StatusBadRequest, "request ID can not be empty")
+		return
+	}
+
+	err := srv.ChainManager.RedriveDeadLetter(chainID, requestID)
+	if err != nil {
+		onError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	onSuccess(c, nil)
 }
 
 // ShowHealth returns the health state
@@ -0,0 +1,152 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const DefaultRemoteTimeout = 10 * time.Second
+
+// RemoteSigner signs with the key held by a remote signing service
+// The service exposes:
+//
+//	GET  {url}/public_key?key_id={key_id}  => {"public_key": "<hex uncompressed public key>"}
+//	POST {url}/sign {"key_id", "digest"}    => {"signature": "<hex [R || S || V] or [R || S]>"}
+type RemoteSigner struct {
+	url    string
+	keyID  string
+	token  string
+	client *http.Client
+	pubKey *ecdsa.PublicKey
+}
+
+type remotePublicKeyResponse struct {
+	PublicKey string `json:"public_key"`
+}
+
+type remoteSignRequest struct {
+	KeyID  string `json:"key_id"`
+	Digest string `json:"digest"`
+}
+
+type remoteSignResponse struct {
+	Signature string `json:"signature"`
+}
+
+// NewRemoteSigner constructs a new RemoteSigner instance
+// The public key is retrieved from the service on construction
+func NewRemoteSigner(serviceURL string, keyID string, token string) (*RemoteSigner, error) {
+	s := &RemoteSigner{
+		url:    strings.TrimSuffix(serviceURL, "/"),
+		keyID:  keyID,
+		token:  token,
+		client: &http.Client{Timeout: DefaultRemoteTimeout},
+	}
+
+	var res remotePublicKeyResponse
+	if err := s.do(http.MethodGet, "/public_key?key_id="+url.QueryEscape(keyID), nil, &res); err != nil {
+		return nil, fmt.Errorf("failed to retrieve the public key of %s: %s", keyID, err)
+	}
+
+	bz, err := hex.DecodeString(strings.TrimPrefix(res.PublicKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key of %s: %s", keyID, err)
+	}
+
+	pubKey, err := crypto.UnmarshalPubkey(bz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key of %s: %s", keyID, err)
+	}
+
+	s.pubKey = pubKey
+
+	return s, nil
+}
+
+// Address implements Signer
+func (s *RemoteSigner) Address() ethcmn.Address {
+	return crypto.PubkeyToAddress(*s.pubKey)
+}
+
+// PublicKey implements Signer
+func (s *RemoteSigner) PublicKey() *ecdsa.PublicKey {
+	return s.pubKey
+}
+
+// Sign implements Signer
+// The returned signature is verified against the public key
+func (s *RemoteSigner) Sign(digest []byte) ([]byte, error) {
+	req := remoteSignRequest{
+		KeyID:  s.keyID,
+		Digest: hex.EncodeToString(digest),
+	}
+
+	var res remoteSignResponse
+	if err := s.do(http.MethodPost, "/sign", req, &res); err != nil {
+		return nil, fmt.Errorf("failed to sign with the remote signer: %s", err)
+	}
+
+	sig, err := hex.DecodeString(strings.TrimPrefix(res.Signature, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from the remote signer: %s", err)
+	}
+
+	if len(sig) == 65 {
+		sig = sig[:64]
+	}
+
+	return ToRecoverable(digest, sig, s.pubKey)
+}
+
+// do sends the request to the signing service and decodes the response
+func (s *RemoteSigner) do(method string, path string, body interface{}, result interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		bz, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reader = bytes.NewReader(bz)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, s.url+path, reader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if len(s.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bz, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(bz))
+	}
+
+	return json.Unmarshal(bz, result)
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/BSNDA/fabric-sdk-go-gm/pkg/client/channel"
 	"github.com/BSNDA/fabric-sdk-go-gm/pkg/client/event"
 	"github.com/BSNDA/fabric-sdk-go-gm/pkg/client/ledger"
+	"github.com/BSNDA/fabric-sdk-go-gm/pkg/common/providers/context"
+	"github.com/BSNDA/fabric-sdk-go-gm/pkg/fab/events/deliverclient/seek"
 	"github.com/BSNDA/fabric-sdk-go-gm/pkg/fabsdk"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 )
@@ -28,14 +30,16 @@ type FabricChain struct {
 	ChainInfo *entity.FabricRelayer
 	config    *config.FabricConfig
 
-	sdk           *fabsdk.FabricSDK
-	channelClient *channel.Client
-	eventClient   *event.Client
-	ledgerClient  *ledger.Client
+	sdk             *fabsdk.FabricSDK
+	channelProvider context.ChannelProvider
+	channelClient   *channel.Client
+	eventClient     *event.Client
+	ledgerClient    *ledger.Client
 
-	handler core.InterchainRequestHandler
-	isStop  bool
-	stop    chan bool
+	handler    core.InterchainRequestHandler
+	isStop     bool
+	stop       chan bool
+	startBlock *uint64 // block number to start from, overriding the checkpoint
 }
 
 // NewFabricChain constructs a new FabricChain instance
@@ -65,19 +69,13 @@ func NewFabricChain(chainInfo *entity.FabricRelayer, sdkConf *config.FabricConfi
 		return nil, errors.New("fabric channel client init failed %s", err)
 	}
 
-	ec, err := event.New(channelProvider, event.WithBlockEvents()) // event.WithSeekType(seek.Newest)
-	if err != nil {
-		logging.Logger.Errorf("fabric event client init failed %s", err)
-		return nil, errors.New("fabric event client init failed %s", err)
-	}
-
 	lc, err := ledger.New(channelProvider)
 	if err != nil {
 		logging.Logger.Errorf("fabric ledger client init failed %s", err)
 		return nil, errors.New("fabric ledger client init failed %s", err)
 	}
 
-	fabric.eventClient = ec
+	fabric.channelProvider = channelProvider
 	fabric.channelClient = client
 	fabric.ledgerClient = lc
 
@@ -120,6 +118,7 @@ func (f *FabricChain) Start(handler core.InterchainRequestHandler) error {
 			if err.HasError {
 				return err.Err
 			}
+			f.isStop = false
 		}
 	case <-time.After(60 * time.Second):
 		{
@@ -148,6 +147,14 @@ func (fc *FabricChain) InterchainEventListener(chanErr chan *errors.ChanError) {
 
 	logging.Logger.Infof("Into InterchainEventListener chainID：%s", fc.ChainInfo.GetChainId())
 
+	ec, err := fc.newEventClient()
+	if err != nil {
+		logging.Logger.Errorf("fabric event client init failed %s", err)
+		chanErr <- errors.NewChanError(errors.New("fabric event client init failed %s", err))
+		return
+	}
+	fc.eventClient = ec
+
 	reg, eventch, err := fc.eventClient.RegisterBlockEvent(fi) //channelClient.RegisterChaincodeEvent(fc.ChainInfo.CrossChainCode, "[\\S\\s]*")  //
 	if err != nil {
 		logging.Logger.Errorf("fabric event failed :%s", err)
@@ -162,6 +169,7 @@ func (fc *FabricChain) InterchainEventListener(chanErr chan *errors.ChanError) {
 		case eventch, ok := <-eventch:
 			if ok {
 				fc.blockevent(eventch)
+				fc.updateCheckpoint(eventch.Block.Header.Number)
 			}
 		case stop, ok := <-fc.stop:
 			{
@@ -177,6 +185,62 @@ func (fc *FabricChain) InterchainEventListener(chanErr chan *errors.ChanError) {
 
 }
 
+// Rescan restarts the event listener from the given block number
+func (fc *FabricChain) Rescan(blockNumber uint64) error {
+	if !fc.isStop {
+		err := fc.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	fc.startBlock = &blockNumber
+
+	return fc.Start(fc.handler)
+}
+
+// newEventClient creates the event client which seeks from the block next to the checkpoint
+func (fc *FabricChain) newEventClient() (*event.Client, error) {
+	opts := []event.ClientOption{event.WithBlockEvents()}
+
+	fromBlock, ok, err := fc.getStartBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		logging.Logger.Infof("the chainId %s fabric relayer event seeks from block %d", fc.ChainInfo.GetChainId(), fromBlock)
+		opts = append(opts, event.WithSeekType(seek.FromBlock), event.WithBlockNum(fromBlock))
+	}
+
+	return event.New(fc.channelProvider, opts...)
+}
+
+// getStartBlock returns the block number to start from if specified or checkpointed
+func (fc *FabricChain) getStartBlock() (uint64, bool, error) {
+	if fc.startBlock != nil {
+		fromBlock := *fc.startBlock
+		fc.startBlock = nil
+
+		return fromBlock, true, nil
+	}
+
+	blockNumber, found, err := store.GetBlockCheckpoint(fc.ChainInfo.GetChainId(), fc.ChainInfo.ChannelId)
+	if err != nil || !found {
+		return 0, false, err
+	}
+
+	return blockNumber + 1, true, nil
+}
+
+// updateCheckpoint records the given block number as processed
+func (fc *FabricChain) updateCheckpoint(blockNumber uint64) {
+	err := store.StoreBlockCheckpoint(fc.ChainInfo.GetChainId(), fc.ChainInfo.ChannelId, blockNumber)
+	if err != nil {
+		logging.Logger.Errorf("the chainId %s update checkpoint failed %s", fc.ChainInfo.GetChainId(), err)
+	}
+}
+
 func (fc *FabricChain) chainCodeEvent(event *eventfab.CCEvent) {
 
 	logging.Logger.Infof("event.EventName : %s", event.EventName)
@@ -2,22 +2,25 @@ package store
 
 import (
 	"fmt"
-	"relayer/common/mysql"
+
+	"relayer/common/ledger"
 	"relayer/logging"
 )
 
-// init mysql
-// created table if the table not exist
+// init the ledger
+// the tables are created by the migrations
 
 //tableName :
 //	tb_irita_crosschain_tx
-//	tb_irita_fabric_relayer
+//	tb_irita_fabric_relayer_gm
+//	tb_irita_fabric_block_checkpoint_gm
 
 const (
-	_TabName_Relayer = "tb_irita_fabric_relayer_gm"
-	_TabName_cc_Tx   = "tb_irita_crosschain_tx"
+	_TabName_Relayer          = "tb_irita_fabric_relayer_gm"
+	_TabName_cc_Tx            = "tb_irita_crosschain_tx"
+	_TabName_Block_Checkpoint = "tb_irita_fabric_block_checkpoint_gm"
 
-	_Create_CrossChain_Tx_Sql = `CREATE TABLE %s(
+	_Create_CrossChain_Tx_Sql = `CREATE TABLE tb_irita_crosschain_tx (
   funique_id bigint(20) NOT NULL AUTO_INCREMENT,
   request_id varchar(255) NOT NULL DEFAULT '' COMMENT '请求唯一id',
   from_chainid varchar(255) NOT NULL DEFAULT '' COMMENT '起始链ID',
@@ -36,7 +39,7 @@ const (
   PRIMARY KEY (funique_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;`
 
-	_Create_Relayer_Sql = `CREATE TABLE  %s (
+	_Create_Relayer_Sql = `CREATE TABLE tb_irita_fabric_relayer_gm (
   Id int(11) NOT NULL AUTO_INCREMENT,
   ChainId varchar(255) NOT NULL DEFAULT '0' COMMENT '链ID',
   AppCode varchar(255) DEFAULT NULL COMMENT '应用Code',
@@ -49,24 +52,109 @@ const (
   LastUpdateTime datetime NOT NULL DEFAULT '1991-01-01 00:00:00' COMMENT '最后修改时间',
   PRIMARY KEY (Id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='irita跨链fabric relayer注册信息表';`
+
+	_Create_Block_Checkpoint_Sql = `CREATE TABLE tb_irita_fabric_block_checkpoint_gm (
+  ChainId varchar(64) NOT NULL DEFAULT '0' COMMENT '链ID',
+  ChannelId varchar(64) NOT NULL DEFAULT '' COMMENT '通道名称',
+  BlockNumber bigint(20) NOT NULL DEFAULT '0' COMMENT '最后处理的区块号',
+  LastUpdateTime datetime NOT NULL DEFAULT '1991-01-01 00:00:00' COMMENT '最后修改时间',
+  PRIMARY KEY (ChainId, ChannelId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='irita跨链fabric relayer区块检查点表';`
+
+	// DDL of the tables in the SQL dialects other than MySQL
+	// the placeholders are the auto-increment primary key and the datetime type
+	_Create_CrossChain_Tx_Sql_Generic = `CREATE TABLE tb_irita_crosschain_tx (
+  funique_id %[1]s,
+  request_id varchar(255) NOT NULL DEFAULT '',
+  from_chainid varchar(255) NOT NULL DEFAULT '',
+  from_tx varchar(255) NOT NULL DEFAULT '',
+  hub_req_tx varchar(255) NOT NULL DEFAULT '',
+  ic_request_id varchar(255) NOT NULL DEFAULT '',
+  to_chainid varchar(255) NOT NULL DEFAULT '',
+  to_tx varchar(255) DEFAULT NULL,
+  hub_res_tx varchar(255) NOT NULL DEFAULT '',
+  from_res_tx varchar(255) DEFAULT NULL,
+  tx_status int NOT NULL DEFAULT 0,
+  tx_time %[2]s DEFAULT NULL,
+  tx_createtime %[2]s NOT NULL DEFAULT '1999-01-01 00:00:00',
+  error text DEFAULT NULL,
+  source_service int NOT NULL DEFAULT 0
+)`
+
+	_Create_Relayer_Sql_Generic = `CREATE TABLE tb_irita_fabric_relayer_gm (
+  Id %[1]s,
+  ChainId varchar(255) NOT NULL DEFAULT '0',
+  AppCode varchar(255) DEFAULT NULL,
+  ChannelId varchar(255) NOT NULL DEFAULT '',
+  CrossChainCode varchar(255) NOT NULL DEFAULT '',
+  NodeName varchar(255) NOT NULL DEFAULT '',
+  CityCode varchar(255) NOT NULL DEFAULT '',
+  Status int NOT NULL DEFAULT 0,
+  CreateTime %[2]s NOT NULL DEFAULT '1990-01-01 00:00:00',
+  LastUpdateTime %[2]s NOT NULL DEFAULT '1991-01-01 00:00:00'
+)`
+
+	_Create_Block_Checkpoint_Sql_Generic = `CREATE TABLE tb_irita_fabric_block_checkpoint_gm (
+  ChainId varchar(64) NOT NULL DEFAULT '0',
+  ChannelId varchar(64) NOT NULL DEFAULT '',
+  BlockNumber bigint NOT NULL DEFAULT 0,
+  LastUpdateTime %[2]s NOT NULL DEFAULT '1991-01-01 00:00:00',
+  PRIMARY KEY (ChainId, ChannelId)
+)`
+
+	// upsert of the block checkpoint
+	_Upsert_Block_Checkpoint_Sql = `INSERT INTO tb_irita_fabric_block_checkpoint_gm (ChainId,ChannelId,BlockNumber,LastUpdateTime) VALUES (?,?,?,?)
+		ON DUPLICATE KEY UPDATE BlockNumber = VALUES(BlockNumber), LastUpdateTime = VALUES(LastUpdateTime);`
+
+	_Upsert_Block_Checkpoint_Sql_Generic = `INSERT INTO tb_irita_fabric_block_checkpoint_gm (ChainId,ChannelId,BlockNumber,LastUpdateTime) VALUES (?,?,?,?)
+		ON CONFLICT (ChainId, ChannelId) DO UPDATE SET BlockNumber = excluded.BlockNumber, LastUpdateTime = excluded.LastUpdateTime`
 )
 
-func InitMysql(conn string) {
-	logging.Logger.Infof("初始化Mysql : %s", conn)
-	mysql.Init(conn)
-	checkTable(_Create_Relayer_Sql, _TabName_Relayer)
-	checkTable(_Create_CrossChain_Tx_Sql, _TabName_cc_Tx)
+// schema defines the DDL of the ledger tables in the SQL dialect of a driver
+type schema struct {
+	createTxTable         string
+	createRelayerTable    string
+	createCheckpointTable string
+	upsertCheckpoint      string
 }
 
-func checkTable(sql, tabName string) {
+// schemas defines the DDL of the ledger tables per driver
+var schemas = map[string]schema{
+	ledger.DriverMySQL: {
+		createTxTable:         _Create_CrossChain_Tx_Sql,
+		createRelayerTable:    _Create_Relayer_Sql,
+		createCheckpointTable: _Create_Block_Checkpoint_Sql,
+		upsertCheckpoint:      _Upsert_Block_Checkpoint_Sql,
+	},
+	ledger.DriverPostgres: newGenericSchema("BIGSERIAL PRIMARY KEY", "timestamp"),
+	ledger.DriverSQLite:   newGenericSchema("INTEGER PRIMARY KEY AUTOINCREMENT", "datetime"),
+}
 
-	sql = fmt.Sprintf(sql, tabName)
+func newGenericSchema(primaryKey string, datetime string) schema {
+	return schema{
+		createTxTable:         fmt.Sprintf(_Create_CrossChain_Tx_Sql_Generic, primaryKey, datetime),
+		createRelayerTable:    fmt.Sprintf(_Create_Relayer_Sql_Generic, primaryKey, datetime),
+		createCheckpointTable: fmt.Sprintf(_Create_Block_Checkpoint_Sql_Generic, primaryKey, datetime),
+		upsertCheckpoint:      _Upsert_Block_Checkpoint_Sql_Generic,
+	}
+}
+
+// InitLedger initializes the ledger backend of the given config and applies the pending migrations
+func InitLedger(config ledger.Config) error {
+	logging.Logger.Infof("初始化账本数据库 : %s", config.Driver)
+
+	if _, ok := schemas[config.Driver]; !ok {
+		return fmt.Errorf("unsupported ledger driver: %s", config.Driver)
+	}
+
+	if err := ledger.Init(config); err != nil {
+		return fmt.Errorf("failed to init the ledger backend: %s", err)
+	}
 
-	logging.Logger.Infof("检查数据库：%s", tabName)
-	if mysql.TabIsExit(tabName) {
-		logging.Logger.Infof("数据库已存在:%s", tabName)
-	} else {
-		logging.Logger.Infof("数据库不存在:%s", tabName)
-		mysql.CreateTable(sql, tabName)
+	applied, err := ledger.Migrate(Migrations(config.Driver))
+	for _, m := range applied {
+		logging.Logger.Infof("ledger migration %d applied: %s", m.Version, m.Name)
 	}
+
+	return err
 }
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"fmt"
+
+	"relayer/common/ledger"
+)
+
+// indexes of the cross-chain tx table on the columns filtered by the updates
+var txIndexes = []struct {
+	name   string
+	column string
+}{
+	{"idx_tx_request_id", "request_id"},
+	{"idx_tx_ic_request_id", "ic_request_id"},
+}
+
+// Migrations returns the ordered migrations of the ledger schema in the SQL dialect of the given driver
+// Append a new migration with the next version to change the schema, never modify the released ones
+// The tables created before the migrations are tracked are left as is
+func Migrations(driver string) []ledger.Migration {
+	s := schemas[driver]
+
+	return []ledger.Migration{
+		{
+			Version: 1,
+			Name:    "create the cross-chain tx table",
+			Up:      createTable(_TabName_cc_Tx, s.createTxTable),
+		},
+		{
+			Version: 2,
+			Name:    "index the request IDs of the cross-chain tx",
+			Up:      createTxIndexes,
+		},
+		{
+			Version: 3,
+			Name:    "create the fabric relayer table",
+			Up:      createTable(_TabName_Relayer, s.createRelayerTable),
+		},
+		{
+			Version: 4,
+			Name:    "create the fabric block checkpoint table",
+			Up:      createTable(_TabName_Block_Checkpoint, s.createCheckpointTable),
+		},
+	}
+}
+
+// createTable creates the table by the given statement unless it exists
+func createTable(table string, sql string) func(b ledger.Backend) error {
+	return func(b ledger.Backend) error {
+		exists, err := b.TableExists(table)
+		if err != nil || exists {
+			return err
+		}
+
+		_, _, err = b.Exec(sql)
+		return err
+	}
+}
+
+func createTxIndexes(b ledger.Backend) error {
+	for _, index := range txIndexes {
+		exists, err := b.IndexExists(_TabName_cc_Tx, index.name)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			continue
+		}
+
+		if _, _, err := b.Exec(fmt.Sprintf("CREATE INDEX %s ON %s (%s)", index.name, _TabName_cc_Tx, index.column)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"fmt"
 	"relayer/appchains/fabric/entity"
+	"relayer/common/ledger"
 	"testing"
 	"time"
 )
@@ -11,7 +12,7 @@ func TestStoreRelayer(t *testing.T) {
 
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	relayer := entity.FabricRelayer{
 		ChainBase:      entity.ChainBase{ChainId: 1},
@@ -36,7 +37,7 @@ func TestGetRelayerAppInfos(t *testing.T) {
 
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	queryResult, err := GetRelayerAppInfos()
 
@@ -54,7 +55,7 @@ func TestUpdateRelayerAppInfo(t *testing.T) {
 
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	data := entity.FabricRelayer{
 		ChainBase:      entity.ChainBase{ChainId: 1},
@@ -75,7 +76,7 @@ func TestDeleteRelayerAppInfo(t *testing.T) {
 
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	err := DeleteRelayerAppInfo("2")
 
@@ -88,7 +89,7 @@ func TestDeleteRelayerAppInfo(t *testing.T) {
 func TestStoreRelayerTxReqInfo(t *testing.T) {
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	relayerTx := entity.FabricRelayerTx{
 		Request_id:    "123",
@@ -113,7 +114,7 @@ func TestStoreRelayerTxReqInfo(t *testing.T) {
 func TestStoreRelayerTxResInfo(t *testing.T) {
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	relayerTx := entity.FabricRelayerTx{
 		Request_id:    "123",
@@ -135,52 +136,147 @@ func TestStoreRelayerTxResInfo(t *testing.T) {
 		t.Errorf(err.Error())
 	}
 }
+//
+//func TestInsertInterchainRequestInfo(t *testing.T) {
+//
+//	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
+//
+//	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
+//
+//	relayerTx := entity.FabricRelayerTx{
+//		Request_id:    "1995",
+//		From_chainid:  "11",
+//		From_tx:       "rong",
+//		Tx_createtime: time.Now(),
+//	}
+//
+//	InsertInterchainRequestInfo(&relayerTx)
+//}
+//
+//func TestSendHUBRequestInfo(t *testing.T) {
+//
+//	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
+//
+//	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
+//
+//	relayerTx := entity.FabricRelayerTx{
+//		Request_id:    "1995",
+//		Hub_req_tx:    "11",
+//		Ic_request_id: "rong",
+//	}
+//
+//	SendHUBRequestInfo(&relayerTx)
+//
+//}
+//
+//func TestCallBackSendResponse(t *testing.T) {
+//
+//	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
+//
+//	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
+//
+//	relayerTx := entity.FabricRelayerTx{
+//		Request_id:    "1995",
+//		Ic_request_id: "rong",
+//		From_res_tx:   "222",
+//		Tx_time:       time.Now(),
+//	}
+//
+//	CallBackSendResponse(&relayerTx)
+//
+//}
+
+func TestRelayerResponeRecord(t *testing.T) {
+	data :=&RelayerResInfo{
+		RequestId: "requestID",
+		TxStatus: TxStatus_Success,
+		ErrMsg: "",
+	}
+	defer func(d *RelayerResInfo) {
 
-func TestInsertInterchainRequestInfo(t *testing.T) {
+		fmt.Println(d.RequestId)
+	}(data)
 
-	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	data.RequestId = "123456"
 
-	relayerTx := entity.FabricRelayerTx{
-		Request_id:    "1995",
-		From_chainid:  "11",
-		From_tx:       "rong",
-		Tx_createtime: time.Now(),
+}
+
+func TestSQLiteLedger(t *testing.T) {
+	if err := InitLedger(ledger.Config{Driver: ledger.DriverSQLite, DSN: ":memory:"}); err != nil {
+		t.Fatal(err)
 	}
+	defer ledger.Close()
 
-	InsertInterchainRequestInfo(&relayerTx)
-}
+	// the migrations are applied once
+	applied, err := ledger.Migrate(Migrations(ledger.DriverSQLite))
+	if err != nil || len(applied) != 0 {
+		t.Fatalf("expected no pending migrations, got %d, err: %v", len(applied), err)
+	}
 
-func TestSendHUBRequestInfo(t *testing.T) {
+	relayer := entity.FabricRelayer{
+		ChainBase:      entity.ChainBase{ChainId: 1},
+		AppCode:        "app1",
+		ChannelId:      "channel1",
+		CrossChainCode: "cc1",
+		NodeName:       "peer1",
+		CreateTime:     time.Now(),
+		LastUpdateTime: time.Now(),
+	}
 
-	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
+	if err := StoreRelayerAppInfo(&relayer); err != nil {
+		t.Fatal(err)
+	}
 
-	InitMysql(db)
+	relayer.ChannelId = "channel2"
+	if err := UpdateRelayerAppInfo(&relayer); err != nil {
+		t.Fatal(err)
+	}
 
-	relayerTx := entity.FabricRelayerTx{
-		Request_id:    "1995",
-		Hub_req_tx:    "11",
-		Ic_request_id: "rong",
+	relayers, err := GetRelayerAppInfos()
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	SendHUBRequestInfo(&relayerTx)
+	if len(relayers) != 1 || relayers[0].ChainId != 1 || relayers[0].ChannelId != "channel2" || relayers[0].CreateTime.IsZero() {
+		t.Fatalf("unexpected relayers: %+v", relayers)
+	}
 
-}
+	if err := DeleteRelayerAppInfo("1"); err != nil {
+		t.Fatal(err)
+	}
 
-func TestCallBackSendResponse(t *testing.T) {
+	if relayers, err = GetRelayerAppInfos(); err != nil || len(relayers) != 0 {
+		t.Fatalf("expected the relayer to be deleted, got %d, err: %v", len(relayers), err)
+	}
 
-	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
+	if _, found, err := GetBlockCheckpoint("1", "channel1"); err != nil || found {
+		t.Fatalf("expected no checkpoint, found: %v, err: %v", found, err)
+	}
+
+	for _, number := range []uint64{10, 12} {
+		if err := StoreBlockCheckpoint("1", "channel1", number); err != nil {
+			t.Fatal(err)
+		}
+	}
 
-	InitMysql(db)
+	if number, found, err := GetBlockCheckpoint("1", "channel1"); err != nil || !found || number != 12 {
+		t.Fatalf("expected the checkpoint 12, got %d, found: %v, err: %v", number, found, err)
+	}
 
-	relayerTx := entity.FabricRelayerTx{
-		Request_id:    "1995",
-		Ic_request_id: "rong",
-		From_res_tx:   "222",
-		Tx_time:       time.Now(),
+	InitRelayerTransRecord("req1", "1", "tx1", "eth-1", "hubtx", "icreq1", TxStatus_Unknow, "")
+	RelayerResponeRecord(&RelayerResInfo{RequestId: "req1", FromResTxId: "tx2", TxStatus: TxStatus_Success})
+
+	txs, total, err := ListCrossChainTxs(TxFilter{ChainID: "1", Status: TxStatus_Success})
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	CallBackSendResponse(&relayerTx)
+	if total != 1 || len(txs) != 1 || txs[0].IcRequestID != "icreq1" || txs[0].FromResTx != "tx2" {
+		t.Fatalf("unexpected txs: %d %+v", total, txs)
+	}
 
+	if txs, err = GetCrossChainTxs(ColumnFromTx, "tx1"); err != nil || len(txs) != 1 {
+		t.Fatalf("expected the tx to be found by the source tx, got %+v, err: %v", txs, err)
+	}
 }
@@ -2,7 +2,7 @@ package store
 
 import (
 	"fmt"
-	"relayer/common/mysql"
+	"relayer/common/ledger"
 	"relayer/logging"
 	"time"
 )
@@ -61,7 +61,7 @@ func InitRelayerTransRecord(
 		"source_service ) "+
 		"VALUES ( ?, ?, ?,?, ?, ?, ?,?,?,?);", _TabName_cc_Tx)
 
-	lastId, rows, err := mysql.Exec(insertsql,
+	lastId, rows, err := ledger.Exec(insertsql,
 		requestId,
 		fromChainId,
 		fromTxId,
@@ -98,7 +98,7 @@ func RelayerResponeRecord(data *RelayerResInfo) {
 	logging.Logger.Infof("set relayer callback record , requestId is %s,tx status is %d",data.RequestId,data.TxStatus)
 	sql :=fmt.Sprintf("update %s set from_res_tx = ? ,error = ? ,tx_time =? ,tx_status = ? where request_id = ? and source_service = %d",_TabName_cc_Tx,source_service)
 
-	lastId, rows, err := mysql.Exec(sql,
+	lastId, rows, err := ledger.Exec(sql,
 		data.FromResTxId,
 		data.ErrMsg,
 		NowTime(),
@@ -134,7 +134,7 @@ func InitProviderTransRecord(requestId ,to_chainid,ic_request_id ,to_tx ,error s
 
 
 
-	lastId, rows, err := mysql.Exec(insertsql,
+	lastId, rows, err := ledger.Exec(insertsql,
 		requestId,
 		ic_request_id,
 		to_chainid,
@@ -168,7 +168,7 @@ func ProviderCallBackTransRecord(data *ProviderResInfo )  {
 	}
 	sql :=fmt.Sprintf("update %s set hub_res_tx = ? ,error = ? ,tx_time =? ,tx_status = ? where ic_request_id = ? and source_service = %d",_TabName_cc_Tx,source_service)
 
-	lastId, rows, err := mysql.Exec(sql,
+	lastId, rows, err := ledger.Exec(sql,
 		data.HUBResTxId,
 		data.ErrMsg,
 		NowTime(),
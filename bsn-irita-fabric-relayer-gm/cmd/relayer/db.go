@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"relayer/common/ledger"
+	cfg "relayer/config"
+
+	txstore "relayer/appchains/fabric/store"
+)
+
+var (
+	DBCmd = &cobra.Command{
+		Use:   "db",
+		Short: "Ledger database commands",
+	}
+)
+
+// DBMigrateCmd implements the db migrate command
+func DBMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [config-file]",
+		Short: "Apply the pending migrations to the ledger database",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := openLedger(args)
+			if err != nil {
+				return err
+			}
+			defer ledger.Close()
+
+			applied, err := ledger.Migrate(txstore.Migrations(config.Driver))
+			for _, m := range applied {
+				fmt.Printf("applied %d: %s\n", m.Version, m.Name)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if len(applied) == 0 {
+				fmt.Println("the ledger database is up to date")
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// DBStatusCmd implements the db status command
+func DBStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [config-file]",
+		Short: "Show the applied and pending migrations of the ledger database",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := openLedger(args)
+			if err != nil {
+				return err
+			}
+			defer ledger.Close()
+
+			statuses, err := ledger.GetMigrationStatus(txstore.Migrations(config.Driver))
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%-8s %-10s %-20s %s\n", "VERSION", "STATUS", "APPLIED TIME", "NAME")
+
+			for _, status := range statuses {
+				state := "pending"
+				if status.Applied {
+					state = "applied"
+				}
+
+				fmt.Printf("%-8d %-10s %-20s %s\n", status.Version, state, status.AppliedTime, status.Name)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// openLedger initializes the ledger backend by the config file in the args
+func openLedger(args []string) (ledger.Config, error) {
+	configFileName := cfg.DefaultConfigFileName
+	if len(args) > 0 {
+		configFileName = args[0]
+	}
+
+	config, err := cfg.LoadYAMLConfig(configFileName)
+	if err != nil {
+		return ledger.Config{}, err
+	}
+
+	ledgerConfig := ledger.NewConfig(config)
+
+	if err := ledger.Init(ledgerConfig); err != nil {
+		return ledgerConfig, err
+	}
+
+	return ledgerConfig, nil
+}
+
+func init() {
+	DBCmd.AddCommand(
+		DBMigrateCmd(),
+		DBStatusCmd(),
+	)
+}
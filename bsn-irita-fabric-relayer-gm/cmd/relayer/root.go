@@ -20,6 +20,7 @@ func main() {
 
 	rootCmd.AddCommand(StartCmd())
 	rootCmd.AddCommand(HubCmd)
+	rootCmd.AddCommand(DBCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
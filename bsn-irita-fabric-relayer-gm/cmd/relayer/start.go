@@ -3,10 +3,13 @@ package main
 import (
 	"github.com/spf13/cobra"
 	"relayer/appchains"
+	"relayer/common/ledger"
 	cfg "relayer/config"
 	"relayer/hub"
 	"relayer/logging"
 	"relayer/server"
+
+	txstore "relayer/appchains/fabric/store"
 )
 
 // StartCmd implements the start command
@@ -31,6 +34,11 @@ func StartCmd() *cobra.Command {
 
 			appChainType := config.GetString(cfg.ConfigKeyAppChainType)
 
+			if err := txstore.InitLedger(ledger.NewConfig(config)); err != nil {
+				return err
+			}
+			defer ledger.Close()
+
 			//path :=config.GetString(cfg.ConfigKeyStorePath)
 			//store, err := store.NewStore(path)
 			//if err != nil {
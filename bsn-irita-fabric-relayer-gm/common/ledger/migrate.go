@@ -0,0 +1,194 @@
+package ledger
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// table tracking the applied migrations, apart from the other relayers as the fabric tables are versioned along
+	MigrationTable = "tb_irita_fabric_gm_schema_migration"
+
+	createMigrationTableSql = `CREATE TABLE tb_irita_fabric_gm_schema_migration (
+  version int NOT NULL PRIMARY KEY,
+  name varchar(255) NOT NULL DEFAULT '',
+  applied_time varchar(32) NOT NULL DEFAULT ''
+)`
+)
+
+// Migration defines a versioned change of the ledger schema
+// Up should be idempotent as the DDL is not transactional in all the drivers,
+// so that the migration interrupted before being recorded can be applied again
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(b Backend) error
+}
+
+// MigrationStatus defines the status of the migration in the ledger
+type MigrationStatus struct {
+	Version     int    `json:"version"`
+	Name        string `json:"name"`
+	Applied     bool   `json:"applied"`
+	AppliedTime string `json:"applied_time,omitempty"`
+}
+
+// Migrate applies the pending migrations to the ledger backend in order
+// The applied migrations are returned
+func Migrate(migrations []Migration) ([]Migration, error) {
+	if backend == nil {
+		return nil, errNotInitialized
+	}
+
+	return migrate(backend, migrations)
+}
+
+// GetMigrationStatus returns the status of the migrations in the ledger backend
+// The applied versions which are unknown are included as well
+func GetMigrationStatus(migrations []Migration) ([]MigrationStatus, error) {
+	if backend == nil {
+		return nil, errNotInitialized
+	}
+
+	return getMigrationStatus(backend, migrations)
+}
+
+func migrate(b Backend, migrations []Migration) ([]Migration, error) {
+	migrations, err := sortMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedMigrations(b)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := latestVersion(migrations)
+	for version := range applied {
+		if version > latest {
+			return nil, fmt.Errorf("ledger schema version %d is newer than the latest supported version %d", version, latest)
+		}
+	}
+
+	done := make([]Migration, 0)
+
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		if err := m.Up(b); err != nil {
+			return done, fmt.Errorf("failed to apply the migration %d %s: %s", m.Version, m.Name, err)
+		}
+
+		_, _, err := b.Exec(
+			fmt.Sprintf("INSERT INTO %s (version, name, applied_time) VALUES (?, ?, ?)", MigrationTable),
+			m.Version, m.Name, time.Now().Format("2006-01-02 15:04:05"),
+		)
+		if err != nil {
+			return done, fmt.Errorf("failed to record the migration %d %s: %s", m.Version, m.Name, err)
+		}
+
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+func getMigrationStatus(b Backend, migrations []Migration) ([]MigrationStatus, error) {
+	migrations, err := sortMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedMigrations(b)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+
+	for _, m := range migrations {
+		status, ok := applied[m.Version]
+		if !ok {
+			status = MigrationStatus{Version: m.Version, Name: m.Name}
+		}
+
+		statuses = append(statuses, status)
+		delete(applied, m.Version)
+	}
+
+	for _, status := range applied {
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// appliedMigrations loads the applied migrations by version, the tracking table is created if not exist
+func appliedMigrations(b Backend) (map[int]MigrationStatus, error) {
+	exists, err := b.TableExists(MigrationTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the migration table: %s", err)
+	}
+
+	if !exists {
+		if _, _, err := b.Exec(createMigrationTableSql); err != nil {
+			return nil, fmt.Errorf("failed to create the migration table: %s", err)
+		}
+	}
+
+	list, err := b.Query(func(rows *sql.Rows) (interface{}, error) {
+		status := MigrationStatus{Applied: true}
+		err := rows.Scan(&status.Version, &status.Name, &status.AppliedTime)
+		return status, err
+	}, fmt.Sprintf("SELECT version, name, applied_time FROM %s", MigrationTable))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the applied migrations: %s", err)
+	}
+
+	applied := make(map[int]MigrationStatus, len(list))
+	for _, item := range list {
+		status := item.(MigrationStatus)
+		applied[status.Version] = status
+	}
+
+	return applied, nil
+}
+
+// sortMigrations sorts the migrations by version, which should be positive and unique
+func sortMigrations(migrations []Migration) ([]Migration, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("invalid migration version %d of %s", m.Version, m.Name)
+		}
+
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("duplicate migration version %d", m.Version)
+		}
+	}
+
+	return sorted, nil
+}
+
+func latestVersion(migrations []Migration) int {
+	if len(migrations) == 0 {
+		return 0
+	}
+
+	return migrations[len(migrations)-1].Version
+}
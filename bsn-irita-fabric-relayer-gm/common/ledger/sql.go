@@ -0,0 +1,86 @@
+package ledger
+
+import (
+	"database/sql"
+)
+
+// sqlBackend is the backend over a connection pool of database/sql
+type sqlBackend struct {
+	driver string
+	db     *sql.DB
+
+	rebind          func(query string) string // rebinds the placeholders for the driver
+	tableExistsSql  string                    // counts the table by name
+	columnExistsSql string                    // counts the column by table and name
+	indexExistsSql  string                    // counts the index by table and name
+}
+
+// Driver implements Backend
+func (b *sqlBackend) Driver() string {
+	return b.driver
+}
+
+// Exec implements Backend
+func (b *sqlBackend) Exec(query string, args ...interface{}) (int64, int64, error) {
+	ret, err := b.db.Exec(b.rebind(query), args...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// not all the drivers support the last inserted ID
+	lastId, _ := ret.LastInsertId()
+	rows, _ := ret.RowsAffected()
+
+	return lastId, rows, nil
+}
+
+// Query implements Backend
+func (b *sqlBackend) Query(option QueryOption, query string, args ...interface{}) ([]interface{}, error) {
+	rows, err := b.db.Query(b.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []interface{}
+
+	for rows.Next() {
+		data, err := option(rows)
+		if err != nil {
+			return list, err
+		}
+
+		list = append(list, data)
+	}
+
+	return list, rows.Err()
+}
+
+// TableExists implements Backend
+func (b *sqlBackend) TableExists(table string) (bool, error) {
+	return b.exists(b.tableExistsSql, table)
+}
+
+// ColumnExists implements Backend
+func (b *sqlBackend) ColumnExists(table string, column string) (bool, error) {
+	return b.exists(b.columnExistsSql, table, column)
+}
+
+// IndexExists implements Backend
+func (b *sqlBackend) IndexExists(table string, index string) (bool, error) {
+	return b.exists(b.indexExistsSql, table, index)
+}
+
+// Close implements Backend
+func (b *sqlBackend) Close() error {
+	return b.db.Close()
+}
+
+func (b *sqlBackend) exists(query string, args ...interface{}) (bool, error) {
+	var count int64
+	if err := b.db.QueryRow(b.rebind(query), args...).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
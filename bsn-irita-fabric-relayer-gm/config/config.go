@@ -12,7 +12,8 @@ const (
 	ConfigKeyAppChainType = "base.app_chain_type"
 	ConfigKeyStorePath    = "base.store_path"
 
-	ConfigKeyHttpPort = "base.http_port"
+	ConfigKeyHttpPort  = "base.http_port"
+	ConfigKeyMySQLConn = "base.mysql_conn"
 
 	DefaultStorePath = ".db"
 )
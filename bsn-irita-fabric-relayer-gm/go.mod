@@ -18,6 +18,8 @@ require (
 	github.com/hyperledger/fabric v1.4.3
 	github.com/hyperledger/fabric-protos-go v0.0.0-20200124220212-e9cfc186ba7b
 	github.com/irisnet/service-sdk-go v1.0.1-0.20210416090657-1bdf41efe743
+	github.com/lib/pq v1.10.0
+	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/op/go-logging v0.0.0-20160315200505-970db520ece7 // indirect
 	github.com/pelletier/go-toml v1.6.0 // indirect
 	github.com/pkg/errors v0.9.1
@@ -13,4 +13,7 @@ type AppChainHandlerI interface {
 
 	//修改
 	UpdateChain(data []byte) error
+
+	//从指定区块重新扫描
+	RescanChain(data []byte) error
 }
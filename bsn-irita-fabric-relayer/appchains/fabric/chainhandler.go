@@ -17,14 +17,11 @@ const (
 	fabric_sdk_config    = "fabric.sdk_config"
 	fabric_msp_user_name = "fabric.msp_user_name"
 	fabric_org_name      = "fabric.org_name"
-	base_mysql_conn      = "base.mysql_conn"
 	base_city_code       = "base.city_code"
 )
 
 func NewFabricHandler(hub core.HubChainI, log *log.Logger, v *viper.Viper) *fabricHandler {
 
-	store.InitMysql(v.GetString(base_mysql_conn))
-
 	logging.Logger.Info("new fabric handler ")
 	conf := &config.FabricConfig{
 		SdkConfig:   v.GetString(fabric_sdk_config),
@@ -222,6 +219,37 @@ func (f *fabricHandler) UpdateChain(data []byte) error {
 	return nil
 }
 
+func (f *fabricHandler) RescanChain(data []byte) error {
+	rc := &entity.RescanChain{}
+
+	err := json.Unmarshal(data, rc)
+	if err != nil {
+		logging.Logger.Errorf("invalid JSON Params %s", err.Error())
+		return errors.New("invalid JSON Params")
+	}
+	logging.Logger.Infof("this RescanChain data is %v", rc)
+
+	// Check if it already exists
+	appChain, ok := f.AppChains[rc.GetChainId()]
+	if !ok {
+		return errors.New("the fabric chain not already exists")
+	}
+
+	chain, ok := appChain.(*FabricChain)
+	if !ok {
+		return errors.New("the chain %s is not a fabric chain", rc.GetChainId())
+	}
+
+	// Restart FabricChain from the given block
+	err = chain.Rescan(rc.BlockNumber)
+	if err != nil {
+		logging.Logger.Errorf("the fabric chain rescan failed %s", err.Error())
+		return errors.New("the fabric chain rescan failed")
+	}
+
+	return nil
+}
+
 func (r *fabricHandler) HandleInterchainRequest(chainID string, request core.InterchainRequest, txHash string) error {
 
 	r.Logger.Infof("got the interchain request on %s: %+v", chainID, request)
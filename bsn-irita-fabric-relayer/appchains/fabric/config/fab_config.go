@@ -2,8 +2,8 @@ package config
 
 import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
-	"relayer/appchains/fabric/config/redconfig"
-	"relayer/appchains/fabric/config/redconfig/configbackend"
+	"relayer/appchains/fabric/redconfig"
+	"relayer/appchains/fabric/redconfig/configbackend"
 )
 
 type FabricConfig struct {
@@ -76,3 +76,9 @@ func (u *UpdateChain) UpdateStoreData() *FabricRelayer {
 	data.SetNodes(u.Nodes)
 	return data
 }
+
+type RescanChain struct {
+	ChainBase
+
+	BlockNumber uint64 `json:"blockNumber"`
+}
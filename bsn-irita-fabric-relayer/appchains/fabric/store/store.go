@@ -4,7 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"relayer/appchains/fabric/entity"
-	"relayer/common/mysql"
+	"relayer/common/ledger"
 	"relayer/errors"
 	"relayer/logging"
 	"time"
@@ -22,7 +22,7 @@ func StoreRelayerAppInfo(data *entity.FabricRelayer) error {
 	creatTime := data.CreateTime.Format("2006-01-02 15:04:05")
 	lastUpdateTime := data.LastUpdateTime.Format("2006-01-02 15:04:05")
 
-	lastID, rows, err := mysql.Exec(
+	lastID, rows, err := ledger.Exec(
 		insertSql,
 		data.GetChainId(),
 		data.AppCode,
@@ -93,8 +93,8 @@ func GetRelayerAppInfos() ([]*entity.FabricRelayer, error) {
 
 		// Time format
 		DefaultTimeLoc := time.Local
-		c, _ := time.ParseInLocation("2006-01-02 15:04:05", createTime, DefaultTimeLoc)
-		l, _ := time.ParseInLocation("2006-01-02 15:04:05", lastUpdateTime, DefaultTimeLoc)
+		c := parseTime(createTime, DefaultTimeLoc)
+		l := parseTime(lastUpdateTime, DefaultTimeLoc)
 		data.CreateTime = c
 		data.LastUpdateTime = l
 
@@ -103,7 +103,7 @@ func GetRelayerAppInfos() ([]*entity.FabricRelayer, error) {
 
 	logging.Logger.Infoln("start query RelayerAppInfoList：")
 
-	RelayerAppInfoList, err := mysql.Query(queryData, querySql)
+	RelayerAppInfoList, err := ledger.Query(queryData, querySql)
 	if err != nil {
 		logging.Logger.Errorf("query err：%s\n", err.Error())
 		return nil, err
@@ -123,7 +123,7 @@ func UpdateRelayerAppInfo(data *entity.FabricRelayer) error {
 	logging.Logger.Infoln("Into UpdateRelayerAppInfo method：")
 
 	updateSql := fmt.Sprintf(`UPDATE %s SET ChannelId=?,CrossChainCode=?,NodeName =? WHERE ChainId = ?;`, _TabName_Relayer)
-	lastId, rows, err := mysql.Exec(updateSql, data.ChannelId, data.CrossChainCode, data.NodeName, data.GetChainId())
+	lastId, rows, err := ledger.Exec(updateSql, data.ChannelId, data.CrossChainCode, data.NodeName, data.GetChainId())
 	if err != nil {
 		logging.Logger.Errorf("UPDATE err: %s", err.Error())
 		return err
@@ -139,9 +139,9 @@ func DeleteRelayerAppInfo(chainId string) error {
 
 	logging.Logger.Infoln("Into DeleteRelayerAppInfo method：")
 
-	deleteSql := fmt.Sprintf("DELETE FROM %s WHERE ChainId = %s;", _TabName_Relayer, chainId)
+	deleteSql := fmt.Sprintf("DELETE FROM %s WHERE ChainId = ?;", _TabName_Relayer)
 
-	lastId, rows, err := mysql.Exec(deleteSql)
+	lastId, rows, err := ledger.Exec(deleteSql, chainId)
 	if err != nil {
 		logging.Logger.Errorf("DELETE err: %s", err.Error())
 		return err
@@ -162,7 +162,7 @@ func StoreRelayerTxReqInfo(date *entity.FabricRelayerTx) error {
 		VALUES (?,?,?,?,?,?,?,?,?,?,?);`, _TabName_cc_Tx)
 	Tx_time := date.Tx_time.Format("2006-01-02 15:04:05")
 	Tx_createtime := date.Tx_createtime.Format("2006-01-02 15:04:05")
-	lastID, rows, err := mysql.Exec(
+	lastID, rows, err := ledger.Exec(
 		insterSql,
 		date.Request_id,
 		date.From_chainid,
@@ -192,7 +192,7 @@ func StoreRelayerTxResInfo(date *entity.FabricRelayerTx) error {
 		WHERE Request_id =?`, _TabName_cc_Tx)
 	Tx_time := date.Tx_time.Format("2006-01-02 15:04:05")
 	Tx_createtime := date.Tx_createtime.Format("2006-01-02 15:04:05")
-	lastID, rows, err := mysql.Exec(
+	lastID, rows, err := ledger.Exec(
 		updateStr,
 		date.From_chainid,
 		date.From_tx,
@@ -215,3 +215,50 @@ func StoreRelayerTxResInfo(date *entity.FabricRelayerTx) error {
 	logging.Logger.Infof("StoreRelayerTxResInfo lastID：%d, Number of rows affected：%d\n", lastID, rows)
 	return nil
 }
+
+// GetBlockCheckpoint retrieves the last processed block number of the given chain and channel
+func GetBlockCheckpoint(chainId, channelId string) (blockNumber uint64, found bool, err error) {
+	querySql := fmt.Sprintf("SELECT BlockNumber FROM %s WHERE ChainId = ? AND ChannelId = ?;", _TabName_Block_Checkpoint)
+
+	queryData := func(rows *sql.Rows) (interface{}, error) {
+		var number uint64
+		err := rows.Scan(&number)
+
+		return number, err
+	}
+
+	list, err := ledger.Query(queryData, querySql, chainId, channelId)
+	if err != nil {
+		logging.Logger.Errorf("query block checkpoint of ChainId %s err: %s", chainId, err.Error())
+		return 0, false, err
+	}
+
+	if len(list) == 0 {
+		return 0, false, nil
+	}
+
+	return list[0].(uint64), true, nil
+}
+
+// StoreBlockCheckpoint stores the last processed block number of the given chain and channel
+func StoreBlockCheckpoint(chainId, channelId string, blockNumber uint64) error {
+	upsertSql := schemas[ledger.Driver()].upsertCheckpoint
+
+	_, _, err := ledger.Exec(upsertSql, chainId, channelId, blockNumber, NowTime())
+	if err != nil {
+		logging.Logger.Errorf("store block checkpoint of ChainId %s err: %s", chainId, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// parseTime parses the datetime column, which is scanned as RFC3339 by the drivers returning time values
+func parseTime(value string, loc *time.Location) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", value, loc)
+	if err != nil {
+		t, _ = time.Parse(time.RFC3339Nano, value)
+	}
+
+	return t
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"fmt"
 	"relayer/appchains/fabric/entity"
+	"relayer/common/ledger"
 	"testing"
 	"time"
 )
@@ -11,7 +12,7 @@ func TestStoreRelayer(t *testing.T) {
 
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	relayer := entity.FabricRelayer{
 		ChainBase:      entity.ChainBase{ChainId: 1},
@@ -36,7 +37,7 @@ func TestGetRelayerAppInfos(t *testing.T) {
 
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	queryResult, err := GetRelayerAppInfos()
 
@@ -54,7 +55,7 @@ func TestUpdateRelayerAppInfo(t *testing.T) {
 
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	data := entity.FabricRelayer{
 		ChainBase:      entity.ChainBase{ChainId: 1},
@@ -75,7 +76,7 @@ func TestDeleteRelayerAppInfo(t *testing.T) {
 
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	err := DeleteRelayerAppInfo("2")
 
@@ -88,7 +89,7 @@ func TestDeleteRelayerAppInfo(t *testing.T) {
 func TestStoreRelayerTxReqInfo(t *testing.T) {
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	relayerTx := entity.FabricRelayerTx{
 		Request_id:    "123",
@@ -113,7 +114,7 @@ func TestStoreRelayerTxReqInfo(t *testing.T) {
 func TestStoreRelayerTxResInfo(t *testing.T) {
 	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 
-	InitMysql(db)
+	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 
 	relayerTx := entity.FabricRelayerTx{
 		Request_id:    "123",
@@ -140,7 +141,7 @@ func TestStoreRelayerTxResInfo(t *testing.T) {
 //
 //	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 //
-//	InitMysql(db)
+//	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 //
 //	relayerTx := entity.FabricRelayerTx{
 //		Request_id:    "1995",
@@ -156,7 +157,7 @@ func TestStoreRelayerTxResInfo(t *testing.T) {
 //
 //	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 //
-//	InitMysql(db)
+//	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 //
 //	relayerTx := entity.FabricRelayerTx{
 //		Request_id:    "1995",
@@ -172,7 +173,7 @@ func TestStoreRelayerTxResInfo(t *testing.T) {
 //
 //	db := "root:123456@tcp(192.168.1.60:3306)/bsnflowdb?charset=utf8"
 //
-//	InitMysql(db)
+//	InitLedger(ledger.Config{Driver: ledger.DriverMySQL, DSN: db})
 //
 //	relayerTx := entity.FabricRelayerTx{
 //		Request_id:    "1995",
@@ -199,4 +200,83 @@ func TestRelayerResponeRecord(t *testing.T) {
 
 	data.RequestId = "123456"
 
-}
\ No newline at end of file
+}
+
+func TestSQLiteLedger(t *testing.T) {
+	if err := InitLedger(ledger.Config{Driver: ledger.DriverSQLite, DSN: ":memory:"}); err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	// the migrations are applied once
+	applied, err := ledger.Migrate(Migrations(ledger.DriverSQLite))
+	if err != nil || len(applied) != 0 {
+		t.Fatalf("expected no pending migrations, got %d, err: %v", len(applied), err)
+	}
+
+	relayer := entity.FabricRelayer{
+		ChainBase:      entity.ChainBase{ChainId: 1},
+		AppCode:        "app1",
+		ChannelId:      "channel1",
+		CrossChainCode: "cc1",
+		NodeName:       "peer1",
+		CreateTime:     time.Now(),
+		LastUpdateTime: time.Now(),
+	}
+
+	if err := StoreRelayerAppInfo(&relayer); err != nil {
+		t.Fatal(err)
+	}
+
+	relayer.ChannelId = "channel2"
+	if err := UpdateRelayerAppInfo(&relayer); err != nil {
+		t.Fatal(err)
+	}
+
+	relayers, err := GetRelayerAppInfos()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(relayers) != 1 || relayers[0].ChainId != 1 || relayers[0].ChannelId != "channel2" || relayers[0].CreateTime.IsZero() {
+		t.Fatalf("unexpected relayers: %+v", relayers)
+	}
+
+	if err := DeleteRelayerAppInfo("1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if relayers, err = GetRelayerAppInfos(); err != nil || len(relayers) != 0 {
+		t.Fatalf("expected the relayer to be deleted, got %d, err: %v", len(relayers), err)
+	}
+
+	if _, found, err := GetBlockCheckpoint("1", "channel1"); err != nil || found {
+		t.Fatalf("expected no checkpoint, found: %v, err: %v", found, err)
+	}
+
+	for _, number := range []uint64{10, 12} {
+		if err := StoreBlockCheckpoint("1", "channel1", number); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if number, found, err := GetBlockCheckpoint("1", "channel1"); err != nil || !found || number != 12 {
+		t.Fatalf("expected the checkpoint 12, got %d, found: %v, err: %v", number, found, err)
+	}
+
+	InitRelayerTransRecord("req1", "1", "tx1", "eth-1", "hubtx", "icreq1", TxStatus_Unknow, "")
+	RelayerResponeRecord(&RelayerResInfo{RequestId: "req1", FromResTxId: "tx2", TxStatus: TxStatus_Success})
+
+	txs, total, err := ListCrossChainTxs(TxFilter{ChainID: "1", Status: TxStatus_Success})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 1 || len(txs) != 1 || txs[0].IcRequestID != "icreq1" || txs[0].FromResTx != "tx2" {
+		t.Fatalf("unexpected txs: %d %+v", total, txs)
+	}
+
+	if txs, err = GetCrossChainTxs(ColumnFromTx, "tx1"); err != nil || len(txs) != 1 {
+		t.Fatalf("expected the tx to be found by the source tx, got %+v, err: %v", txs, err)
+	}
+}
@@ -0,0 +1,35 @@
+package ledger
+
+import (
+	"github.com/spf13/viper"
+
+	cfg "relayer/config"
+)
+
+const (
+	Prefix    = "ledger"
+	DriverKey = "driver"
+	DSNKey    = "dsn"
+)
+
+// Config represents the ledger backend config
+type Config struct {
+	Driver string `yaml:"driver"` // one of mysql, postgres and sqlite
+	DSN    string `yaml:"dsn"`    // data source name of the driver
+}
+
+// NewConfig constructs a new Config from viper
+// The mysql connection of the base config is used if the ledger driver is not configured
+func NewConfig(v *viper.Viper) Config {
+	config := Config{
+		Driver: v.GetString(cfg.GetConfigKey(Prefix, DriverKey)),
+		DSN:    v.GetString(cfg.GetConfigKey(Prefix, DSNKey)),
+	}
+
+	if len(config.Driver) == 0 {
+		config.Driver = DriverMySQL
+		config.DSN = v.GetString(cfg.ConfigKeyMySQLConn)
+	}
+
+	return config
+}
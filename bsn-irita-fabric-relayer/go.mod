@@ -23,6 +23,8 @@ require (
 	github.com/hyperledger/fabric-lib-go v1.0.0 // indirect
 	github.com/hyperledger/fabric-sdk-go v1.0.0-alpha5
 	github.com/irisnet/service-sdk-go v1.0.1-0.20210416090657-1bdf41efe743
+	github.com/lib/pq v1.10.0
+	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/miekg/pkcs11 v1.0.3 // indirect
 	github.com/op/go-logging v0.0.0-20160315200505-970db520ece7 // indirect
 	github.com/pelletier/go-toml v1.6.0 // indirect
@@ -36,6 +38,8 @@ require (
 	github.com/spf13/viper v1.7.1
 	github.com/stretchr/testify v1.7.0
 	github.com/sykesm/zap-logfmt v0.0.4 // indirect
+	relayer/appchains/fabric/blockparser v0.0.0
+	relayer/appchains/fabric/redconfig v0.0.0
 )
 
 replace (
@@ -48,4 +52,6 @@ replace (
 	github.com/ugorji/go => github.com/ugorji/go v1.1.2
 	google.golang.org/genproto => google.golang.org/genproto v0.0.0-20200513103714-09dca8ec2884
 	google.golang.org/grpc => google.golang.org/grpc v1.31.0
+	relayer/appchains/fabric/blockparser => ../bsn-irita-appchains/fabric/blockparser
+	relayer/appchains/fabric/redconfig => ../bsn-irita-appchains/fabric/redconfig
 )
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	txstore "relayer/appchains/fabric/store"
+)
+
+// TxList defines a page of the cross-chain txs
+type TxList struct {
+	Total int64                  `json:"total"`
+	Page  int                    `json:"page"`
+	Size  int                    `json:"size"`
+	Txs   []txstore.CrossChainTx `json:"txs"`
+}
+
+// LookupTxs looks up the cross-chain txs by request_id, ic_request_id or tx_hash of the source tx
+func (srv *HTTPService) LookupTxs(c *gin.Context) {
+	lookups := []struct {
+		param  string
+		column string
+	}{
+		{"request_id", txstore.ColumnRequestID},
+		{"ic_request_id", txstore.ColumnIcRequestID},
+		{"tx_hash", txstore.ColumnFromTx},
+	}
+
+	for _, lookup := range lookups {
+		value := c.Query(lookup.param)
+		if len(value) == 0 {
+			continue
+		}
+
+		txs, err := txstore.GetCrossChainTxs(lookup.column, value)
+		if err != nil {
+			onStatusError(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		if len(txs) == 0 {
+			onStatusError(c, http.StatusNotFound, fmt.Errorf("no cross-chain tx found by %s %s", lookup.param, value))
+			return
+		}
+
+		onSuccess(c, txs)
+		return
+	}
+
+	onStatusError(c, http.StatusBadRequest, errors.New("one of request_id, ic_request_id and tx_hash must be specified"))
+}
+
+// ListTxs lists the cross-chain txs filtered by chain_id, status, start_time and end_time with pagination
+func (srv *HTTPService) ListTxs(c *gin.Context) {
+	filter, err := parseTxFilter(c)
+	if err != nil {
+		onStatusError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	txs, total, err := txstore.ListCrossChainTxs(filter)
+	if err != nil {
+		onStatusError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	onSuccess(c, TxList{Total: total, Page: filter.Page, Size: filter.Size, Txs: txs})
+}
+
+// GetTxStats returns the number of the cross-chain txs per status filtered by chain_id, start_time and end_time
+func (srv *HTTPService) GetTxStats(c *gin.Context) {
+	filter, err := parseTxFilter(c)
+	if err != nil {
+		onStatusError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	counts, err := txstore.CountCrossChainTxsByStatus(filter)
+	if err != nil {
+		onStatusError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	onSuccess(c, counts)
+}
+
+// parseTxFilter parses the tx filter from the query params
+// The times are in unix seconds
+func parseTxFilter(c *gin.Context) (txstore.TxFilter, error) {
+	filter := txstore.TxFilter{
+		ChainID: c.Query("chain_id"),
+		Status:  -1,
+		Page:    1,
+		Size:    txstore.DefaultPageSize,
+	}
+
+	var err error
+
+	if status := c.Query("status"); len(status) > 0 {
+		if filter.Status, err = strconv.Atoi(status); err != nil || filter.Status < 0 {
+			return filter, fmt.Errorf("invalid status: %s", status)
+		}
+	}
+
+	if filter.StartTime, err = parseUnixTime(c.Query("start_time")); err != nil {
+		return filter, fmt.Errorf("invalid start_time: %s", err)
+	}
+
+	if filter.EndTime, err = parseUnixTime(c.Query("end_time")); err != nil {
+		return filter, fmt.Errorf("invalid end_time: %s", err)
+	}
+
+	if page := c.Query("page"); len(page) > 0 {
+		if filter.Page, err = strconv.Atoi(page); err != nil || filter.Page < 1 {
+			return filter, fmt.Errorf("invalid page: %s", page)
+		}
+	}
+
+	if size := c.Query("size"); len(size) > 0 {
+		if filter.Size, err = strconv.Atoi(size); err != nil || filter.Size < 1 || filter.Size > txstore.MaxPageSize {
+			return filter, fmt.Errorf("invalid size: %s, should be between 1 and %d", size, txstore.MaxPageSize)
+		}
+	}
+
+	return filter, nil
+}
+
+func parseUnixTime(s string) (time.Time, error) {
+	if len(s) == 0 {
+		return time.Time{}, nil
+	}
+
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(seconds, 0), nil
+}
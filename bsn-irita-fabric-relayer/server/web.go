@@ -48,6 +48,11 @@ func (srv *HTTPService) createRouter() {
 		fabric.POST("/regSideChain", srv.AddChain)
 		fabric.POST("/removeAppChain", srv.DeleteChain)
 		fabric.POST("/updateAppChain", srv.UpdateChain)
+		fabric.POST("/rescanAppChain", srv.RescanChain)
+
+		fabric.GET("/txs", srv.ListTxs)
+		fabric.GET("/txs/lookup", srv.LookupTxs)
+		fabric.GET("/txs/stats", srv.GetTxStats)
 	}
 
 	//r.POST("/chains", srv.AddChain)
@@ -149,15 +154,42 @@ func (srv *HTTPService) UpdateChain(c *gin.Context) {
 	onSuccess(c, nil)
 }
 
+func (srv *HTTPService) RescanChain(c *gin.Context) {
+	var bodyBytes []byte // 我们需要的body内容
+
+	// 从原有Request.Body读取
+	bodyBytes, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		logging.Logger.Errorf(err.Error())
+		c.JSON(http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	logging.Logger.Infof("RescanChain data is %s", string(bodyBytes))
+
+	err = srv.AppChain.RescanChain(bodyBytes)
+	if err != nil {
+		onError(c, err)
+		return
+	}
+
+	onSuccess(c, nil)
+}
+
 // ShowHealth returns the health state
 func (srv *HTTPService) ShowHealth(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"result": true})
 }
 
 func onError(c *gin.Context, err error) {
+	onStatusError(c, http.StatusInternalServerError, err)
+}
+
+// onStatusError responds the error with the given HTTP status code
+func onStatusError(c *gin.Context, statusCode int, err error) {
 	logging.Logger.Errorf(err.Error())
 
-	c.JSON(http.StatusInternalServerError, ErrorResponse{
+	c.JSON(statusCode, ErrorResponse{
 		Code:  CODE_ERROR,
 		Error: err.Error(),
 	})
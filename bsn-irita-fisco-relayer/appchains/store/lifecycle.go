@@ -0,0 +1,157 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"relayer/common/ledger"
+	"relayer/logging"
+)
+
+// lifecycle states of the interchain request
+const (
+	TxState_Detected          = "detected"           // detected on the source chain
+	TxState_Submitted         = "submitted"          // submitted to the Hub
+	TxState_HubResponded      = "hub_responded"      // responded on the Hub
+	TxState_Expired           = "expired"            // expired on the Hub without response
+	TxState_ResponseSent      = "response_sent"      // response tx sent to the source chain
+	TxState_ResponseConfirmed = "response_confirmed" // response tx confirmed on the source chain
+	TxState_Failed            = "failed"             // response failed to be delivered after the maximum attempts
+)
+
+// txStateSources defines the states from which each state can be transited to
+// The empty state stands for the records created before the lifecycle is tracked
+var txStateSources = map[string][]string{
+	TxState_Submitted:         {"", TxState_Detected},
+	TxState_HubResponded:      {"", TxState_Submitted},
+	TxState_Expired:           {"", TxState_Submitted},
+	TxState_ResponseSent:      {"", TxState_HubResponded, TxState_Expired, TxState_ResponseSent, TxState_Failed},
+	TxState_ResponseConfirmed: {"", TxState_ResponseSent},
+	TxState_Failed:            {"", TxState_Detected, TxState_Submitted, TxState_HubResponded, TxState_Expired, TxState_ResponseSent},
+}
+
+// TxTransition defines the transition of the interchain request to a lifecycle state
+type TxTransition struct {
+	State  string `json:"state"`
+	TxHash string `json:"tx_hash,omitempty"`
+	Error  string `json:"error,omitempty"`
+	Time   string `json:"time"`
+}
+
+// CanTransit returns true if the request in the state from can be transited to the state to
+func CanTransit(from string, to string) bool {
+	for _, source := range txStateSources[to] {
+		if source == from {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsValidTxState returns true if the given state is a lifecycle state
+func IsValidTxState(state string) bool {
+	_, ok := txStateSources[state]
+	return ok || state == TxState_Detected
+}
+
+// RelayerSubmitRecord records the Hub request of the interchain request and transits it to submitted
+func RelayerSubmitRecord(requestId string, hubReqTxId string, icRequestId string) {
+	logging.Logger.Infof("set relayer submit record , requestId is %s,ic requestId is %s", requestId, icRequestId)
+
+	where, args := txStateCondition(requestId, TxState_Submitted)
+	updateSql := fmt.Sprintf("update %s set hub_req_tx = ? ,ic_request_id = ? ,tx_state = ? where %s", _TabName_cc_Tx, where)
+
+	_, rows, err := ledger.Exec(updateSql, append([]interface{}{hubReqTxId, icRequestId, TxState_Submitted}, args...)...)
+	if err != nil {
+		logging.Logger.Errorf("set relayer submit record Failed :%s", err.Error())
+		return
+	}
+
+	if rows == 0 {
+		logging.Logger.Warnf("interchain request %s can not be transited to %s", requestId, TxState_Submitted)
+		return
+	}
+
+	insertTxTransition(requestId, TxState_Submitted, hubReqTxId, "")
+}
+
+// RecordTxTransition transits the interchain request to the given state
+// The transition which is invalid from the current state is ignored
+func RecordTxTransition(requestId string, state string, txHash string, errMsg string) {
+	logging.Logger.Infof("transit relayer trans record , requestId is %s,tx state is %s", requestId, state)
+
+	where, args := txStateCondition(requestId, state)
+	updateSql := fmt.Sprintf("update %s set tx_state = ? where %s", _TabName_cc_Tx, where)
+
+	_, rows, err := ledger.Exec(updateSql, append([]interface{}{state}, args...)...)
+	if err != nil {
+		logging.Logger.Errorf("transit relayer trans record Failed :%s", err.Error())
+		return
+	}
+
+	if rows == 0 {
+		logging.Logger.Warnf("interchain request %s can not be transited to %s", requestId, state)
+		return
+	}
+
+	insertTxTransition(requestId, state, txHash, errMsg)
+}
+
+// GetTxTransitions retrieves the transitions of the interchain request in order
+func GetTxTransitions(requestId string) ([]TxTransition, error) {
+	querySql := fmt.Sprintf(
+		"SELECT tx_state, tx_hash, error, transition_time FROM %s WHERE request_id = ? AND source_service = ? ORDER BY funique_id",
+		_TabName_cc_Tx_Transition,
+	)
+
+	list, err := ledger.Query(func(rows *sql.Rows) (interface{}, error) {
+		var transition TxTransition
+		var errMsg sql.NullString
+
+		err := rows.Scan(&transition.State, &transition.TxHash, &errMsg, &transition.Time)
+		transition.Error = errMsg.String
+
+		return transition, err
+	}, querySql, requestId, source_service)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions := make([]TxTransition, len(list))
+	for i, item := range list {
+		transitions[i] = item.(TxTransition)
+	}
+
+	return transitions, nil
+}
+
+// txStateCondition builds the condition to update the record which can be transited to the given state
+func txStateCondition(requestId string, state string) (string, []interface{}) {
+	sources := txStateSources[state]
+
+	args := []interface{}{requestId, source_service}
+	for _, source := range sources {
+		args = append(args, source)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(sources)), ",")
+	if len(placeholders) == 0 {
+		// no state can be transited to the given state
+		placeholders = "NULL"
+	}
+
+	return fmt.Sprintf("request_id = ? and source_service = ? and tx_state in (%s)", placeholders), args
+}
+
+func insertTxTransition(requestId string, state string, txHash string, errMsg string) {
+	insertsql := fmt.Sprintf(
+		"INSERT INTO %s (request_id, tx_state, tx_hash, error, transition_time, source_service) VALUES (?, ?, ?, ?, ?, ?);",
+		_TabName_cc_Tx_Transition,
+	)
+
+	if _, _, err := ledger.Exec(insertsql, requestId, state, txHash, errMsg, NowTime(), source_service); err != nil {
+		logging.Logger.Errorf("insert relayer trans transition Failed :%s", err.Error())
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cfg "relayer/config"
+	"relayer/secrets"
+)
+
+var (
+	SecretsCmd = &cobra.Command{
+		Use:   "secrets",
+		Short: "Secret encryption commands, the master key is read from $" + secrets.EnvMasterKey + " or the file at $" + secrets.EnvMasterKeyFile,
+	}
+)
+
+// SecretsEncryptCmd implements the secrets encrypt command
+func SecretsEncryptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encrypt [value]",
+		Short: "Encrypt the value to be placed in the config file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			masterKey, err := secrets.LoadMasterKey()
+			if err != nil {
+				return err
+			}
+
+			value, err := secrets.Encrypt(masterKey, args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%s\n", value)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// SecretsDecryptCmd implements the secrets decrypt command
+func SecretsDecryptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decrypt [value]",
+		Short: "Decrypt the encrypted value",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			masterKey, err := secrets.LoadMasterKey()
+			if err != nil {
+				return err
+			}
+
+			value, err := secrets.Decrypt(masterKey, args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%s\n", value)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// SecretsEncryptConfigCmd implements the secrets encrypt-config command
+func SecretsEncryptConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encrypt-config [config-file]",
+		Short: "Encrypt the sensitive fields of the config file in place",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configFileName := ""
+
+			if len(args) == 0 {
+				configFileName = cfg.DefaultConfigFileName
+			} else {
+				configFileName = args[0]
+			}
+
+			masterKey, err := secrets.LoadMasterKey()
+			if err != nil {
+				return err
+			}
+
+			count, err := secrets.EncryptFile(configFileName, masterKey)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%d fields encrypted in %s\n", count, configFileName)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func init() {
+	SecretsCmd.AddCommand(
+		SecretsEncryptCmd(),
+		SecretsDecryptCmd(),
+		SecretsEncryptConfigCmd(),
+	)
+}
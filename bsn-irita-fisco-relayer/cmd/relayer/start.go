@@ -5,15 +5,15 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"relayer/appchains"
+	"relayer/common/ledger"
 	cfg "relayer/config"
 	"relayer/core"
 	"relayer/hub"
 	"relayer/logging"
-	"relayer/mysql"
 	"relayer/server"
 	"relayer/store"
 
-	txstore "relayer/appchains/fisco/store"
+	txstore "relayer/appchains/store"
 )
 
 const (
@@ -47,15 +47,18 @@ func StartCmd() *cobra.Command {
 				return err
 			}
 
-			mysqlConfig := mysql.NewConfig(config)
-
-			txstore.InitMysql(mysqlConfig.DSN())
-			//mysql.NewDB(mysqlConfig)
-			//defer mysql.Close()
+			if err := txstore.InitLedger(ledger.NewConfig(config)); err != nil {
+				return err
+			}
+			defer ledger.Close()
 
 			appChainFactory := appchains.NewAppChainFactory(store)
 			hubChain := hub.BuildIritaHubChain(hub.NewConfig(config))
-			relayerInstance := core.NewRelayer(appChainType, hubChain, appChainFactory, logging.Logger)
+			hubChain.Endpoints.StartHealthCheck()
+			relayerInstance := core.NewRelayer(appChainType, hubChain, appChainFactory, store, logging.Logger)
+			if retention := cfg.GetRetention(config); retention != 0 {
+				relayerInstance.Retention = retention
+			}
 
 			baseConfigFactory := appchains.NewBaseConfigFactory(config)
 			BaseConfig, err := baseConfigFactory.NewBaseConfig(appChainType)
@@ -95,6 +98,12 @@ func StartCmd() *cobra.Command {
 				}
 			}
 
+			// resume the response subscriptions interrupted by the last shutdown
+			relayerInstance.RecoverSubscriptions()
+
+			// resubmit the interchain requests and responses left in the queues
+			relayerInstance.StartQueues()
+
 			chainManager := server.NewChainManager(relayerInstance)
 
 
@@ -166,6 +166,26 @@ func TabIsExit(tableName string) bool {
 	}
 }
 
+// ColumnIsExist checks if the column exists in the table of the current database
+func ColumnIsExist(tableName string, columnName string) bool {
+	sqlstr := "SELECT column_name FROM information_schema.COLUMNS WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?"
+
+	exist := func(rows *sql.Rows) (interface{}, error) {
+		var column_name string
+		err := rows.Scan(&column_name)
+
+		return column_name, err
+	}
+
+	list, err := Query(exist, sqlstr, tableName, columnName)
+	if err != nil {
+		logging.Logger.Errorf("failed to query the column %s of %s: %s", columnName, tableName, err)
+		return false
+	}
+
+	return len(list) > 0
+}
+
 func CreateTable(sql string, tabName string) {
 
 	id, rows, err := Exec(sql)
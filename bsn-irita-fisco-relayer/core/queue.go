@@ -0,0 +1,192 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"relayer/store"
+)
+
+const (
+	KeyPrefixRequestQueue      = "queue:request"
+	KeyPrefixRequestDeadLetter = "deadletter:request"
+
+	DefaultRequestMaxAttempts = 20 // maximum attempts to submit a request before it is given up
+
+	DefaultQueueRetryInterval    = 5 * time.Second // initial interval to retry the hub submission
+	DefaultQueueMaxRetryInterval = 5 * time.Minute // maximum interval to retry the hub submission
+	DefaultQueuePollInterval     = time.Second     // interval to poll the due entries
+)
+
+// QueueEntry defines the interchain request pending to be submitted to the Hub chain
+type QueueEntry struct {
+	ChainID   string            `json:"chain_id"`
+	Request   InterchainRequest `json:"request"`
+	Attempts  int               `json:"attempts"`
+	NextRetry int64             `json:"next_retry"`
+	LastError string            `json:"last_error"`
+}
+
+// RequestQueue is a persistent queue of the interchain requests detected on the app chains
+// The requests which exceed the maximum attempts are moved to the dead letters
+type RequestQueue struct {
+	store       *store.Store
+	maxAttempts int
+	inflight    inflightKeys
+}
+
+// NewRequestQueue constructs a new RequestQueue instance
+func NewRequestQueue(store *store.Store, maxAttempts int) *RequestQueue {
+	return &RequestQueue{
+		store:       store,
+		maxAttempts: maxAttempts,
+		inflight:    inflightKeys{keys: map[string]bool{}},
+	}
+}
+
+// RequestQueueKey returns the queue key of the given request
+func RequestQueueKey(chainID string, requestID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s", KeyPrefixRequestQueue, chainID, requestID))
+}
+
+// RequestDeadLetterKey returns the dead letter key of the given request
+func RequestDeadLetterKey(chainID string, requestID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s", KeyPrefixRequestDeadLetter, chainID, requestID))
+}
+
+// Push persists the given request which is due immediately
+func (q *RequestQueue) Push(chainID string, request InterchainRequest) (*QueueEntry, error) {
+	entry := &QueueEntry{
+		ChainID:   chainID,
+		Request:   request,
+		NextRetry: time.Now().Unix(),
+	}
+
+	if err := q.save(RequestQueueKey(chainID, request.ID), entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Has returns true if the given request is queued
+func (q *RequestQueue) Has(chainID string, requestID string) bool {
+	_, err := q.store.Get(RequestQueueKey(chainID, requestID))
+	return err == nil
+}
+
+// Acquire marks the given entry as in flight
+// False is returned if the entry is being processed or no longer queued
+func (q *RequestQueue) Acquire(entry *QueueEntry) bool {
+	return q.inflight.acquire(q.store, RequestQueueKey(entry.ChainID, entry.Request.ID))
+}
+
+// Release marks the given entry as not in flight
+func (q *RequestQueue) Release(entry *QueueEntry) {
+	q.inflight.release(RequestQueueKey(entry.ChainID, entry.Request.ID))
+}
+
+// Done removes the given entry from the queue
+func (q *RequestQueue) Done(entry *QueueEntry) error {
+	return q.store.Delete(RequestQueueKey(entry.ChainID, entry.Request.ID))
+}
+
+// Retry records the failed attempt and reschedules the given entry with exponential backoff
+// The entry is moved to the dead letters if the maximum attempts are exceeded, in which case true is returned
+func (q *RequestQueue) Retry(entry *QueueEntry, err error) (bool, error) {
+	entry.Attempts++
+	entry.LastError = err.Error()
+	entry.NextRetry = time.Now().Add(Backoff(entry.Attempts)).Unix()
+
+	if entry.Attempts < q.maxAttempts {
+		return false, q.save(RequestQueueKey(entry.ChainID, entry.Request.ID), entry)
+	}
+
+	if err := q.save(RequestDeadLetterKey(entry.ChainID, entry.Request.ID), entry); err != nil {
+		return false, err
+	}
+
+	return true, q.Done(entry)
+}
+
+// Due returns the entries whose retry time has come
+func (q *RequestQueue) Due() ([]*QueueEntry, error) {
+	now := time.Now().Unix()
+	entries := make([]*QueueEntry, 0)
+
+	err := q.store.Iterate([]byte(KeyPrefixRequestQueue+":"), func(key, value []byte) bool {
+		var entry QueueEntry
+		if err := json.Unmarshal(value, &entry); err != nil {
+			return true
+		}
+
+		if entry.NextRetry <= now {
+			entries = append(entries, &entry)
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (q *RequestQueue) save(key []byte, entry *QueueEntry) error {
+	bz, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return q.store.Set(key, bz)
+}
+
+// inflightKeys tracks the queue keys being processed
+type inflightKeys struct {
+	keys map[string]bool
+	mtx  sync.Mutex
+}
+
+// acquire marks the given key as in flight if it still exists in the store
+func (k *inflightKeys) acquire(store *store.Store, key []byte) bool {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	if k.keys[string(key)] {
+		return false
+	}
+
+	// the entry may be done since it was loaded
+	if _, err := store.Get(key); err != nil {
+		return false
+	}
+
+	k.keys[string(key)] = true
+
+	return true
+}
+
+// release marks the given key as not in flight
+func (k *inflightKeys) release(key []byte) {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	delete(k.keys, string(key))
+}
+
+// Backoff returns the retry interval after the given number of attempts
+func Backoff(attempts int) time.Duration {
+	interval := DefaultQueueRetryInterval
+	for i := 1; i < attempts && interval < DefaultQueueMaxRetryInterval; i++ {
+		interval *= 2
+	}
+
+	if interval > DefaultQueueMaxRetryInterval {
+		interval = DefaultQueueMaxRetryInterval
+	}
+
+	return interval
+}
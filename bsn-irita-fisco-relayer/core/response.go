@@ -0,0 +1,215 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"relayer/store"
+)
+
+const (
+	KeyPrefixResponseQueue = "queue:response"
+	KeyPrefixDeadLetter    = "deadletter:response"
+
+	DefaultResponseMaxAttempts = 10 // maximum attempts to deliver a response before it is dead lettered
+)
+
+// ResponseEntry defines the Hub response pending to be delivered to the app chain
+type ResponseEntry struct {
+	ChainID   string          `json:"chain_id"`
+	RequestID string          `json:"request_id"`
+	Response  ResponseAdaptor `json:"response"`
+	Attempts  int             `json:"attempts"`
+	NextRetry int64           `json:"next_retry"`
+	LastError string          `json:"last_error"`
+
+	Queued bool `json:"-"` // whether the entry is persisted in the queue
+}
+
+// ResponseQueue is a persistent queue of the Hub responses failed to be delivered
+// The responses which exceed the maximum attempts are moved to the dead letters
+type ResponseQueue struct {
+	store       *store.Store
+	maxAttempts int
+	inflight    inflightKeys
+}
+
+// NewResponseQueue constructs a new ResponseQueue instance
+func NewResponseQueue(store *store.Store, maxAttempts int) *ResponseQueue {
+	return &ResponseQueue{
+		store:       store,
+		maxAttempts: maxAttempts,
+		inflight:    inflightKeys{keys: map[string]bool{}},
+	}
+}
+
+// ResponseQueueKey returns the queue key of the given response
+func ResponseQueueKey(chainID string, requestID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s", KeyPrefixResponseQueue, chainID, requestID))
+}
+
+// DeadLetterKey returns the dead letter key of the given response
+func DeadLetterKey(chainID string, requestID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s", KeyPrefixDeadLetter, chainID, requestID))
+}
+
+// NewResponseEntry builds the entry of the given response
+func NewResponseEntry(chainID string, requestID string, response ResponseI) *ResponseEntry {
+	adaptor, ok := response.(ResponseAdaptor)
+	if !ok {
+		adaptor = ResponseAdaptor{StatusCode: 200, Output: response.GetOutput()}
+
+		if len(response.GetErrMsg()) > 0 {
+			adaptor = ResponseAdaptor{StatusCode: 500, Result: response.GetErrMsg()}
+		}
+	}
+
+	return &ResponseEntry{
+		ChainID:   chainID,
+		RequestID: requestID,
+		Response:  adaptor,
+	}
+}
+
+// Push persists the given entry which is due immediately
+func (q *ResponseQueue) Push(entry *ResponseEntry) error {
+	entry.NextRetry = time.Now().Unix()
+
+	if err := q.save(ResponseQueueKey(entry.ChainID, entry.RequestID), entry); err != nil {
+		return err
+	}
+
+	entry.Queued = true
+
+	return nil
+}
+
+// Acquire marks the given entry as in flight
+// False is returned if the entry is being processed or no longer queued
+func (q *ResponseQueue) Acquire(entry *ResponseEntry) bool {
+	return q.inflight.acquire(q.store, ResponseQueueKey(entry.ChainID, entry.RequestID))
+}
+
+// Release marks the given entry as not in flight
+func (q *ResponseQueue) Release(entry *ResponseEntry) {
+	q.inflight.release(ResponseQueueKey(entry.ChainID, entry.RequestID))
+}
+
+// Done removes the given entry from the queue
+func (q *ResponseQueue) Done(entry *ResponseEntry) error {
+	if err := q.store.Delete(ResponseQueueKey(entry.ChainID, entry.RequestID)); err != nil {
+		return err
+	}
+
+	entry.Queued = false
+
+	return nil
+}
+
+// Retry records the failed attempt and reschedules the given entry with exponential backoff
+// The entry is moved to the dead letters if the maximum attempts are exceeded, in which case true is returned
+func (q *ResponseQueue) Retry(entry *ResponseEntry, err error) (bool, error) {
+	entry.Attempts++
+	entry.LastError = err.Error()
+	entry.NextRetry = time.Now().Add(Backoff(entry.Attempts)).Unix()
+
+	if entry.Attempts < q.maxAttempts {
+		if err := q.save(ResponseQueueKey(entry.ChainID, entry.RequestID), entry); err != nil {
+			return false, err
+		}
+
+		entry.Queued = true
+
+		return false, nil
+	}
+
+	if err := q.save(DeadLetterKey(entry.ChainID, entry.RequestID), entry); err != nil {
+		return false, err
+	}
+
+	return true, q.Done(entry)
+}
+
+// Due returns the entries whose retry time has come
+func (q *ResponseQueue) Due() ([]*ResponseEntry, error) {
+	now := time.Now().Unix()
+
+	entries, err := q.list(KeyPrefixResponseQueue)
+	if err != nil {
+		return nil, err
+	}
+
+	due := make([]*ResponseEntry, 0)
+	for _, entry := range entries {
+		if entry.NextRetry <= now {
+			due = append(due, entry)
+		}
+	}
+
+	return due, nil
+}
+
+// DeadLetters returns all the dead lettered responses
+func (q *ResponseQueue) DeadLetters() ([]*ResponseEntry, error) {
+	return q.list(KeyPrefixDeadLetter)
+}
+
+// Redrive moves the specified dead letter back to the queue which is due immediately
+func (q *ResponseQueue) Redrive(chainID string, requestID string) error {
+	key := DeadLetterKey(chainID, requestID)
+
+	bz, err := q.store.Get(key)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return fmt.Errorf("dead letter of request %s on %s does not exist", requestID, chainID)
+		}
+
+		return err
+	}
+
+	var entry ResponseEntry
+	if err := json.Unmarshal(bz, &entry); err != nil {
+		return err
+	}
+
+	entry.Attempts = 0
+	entry.NextRetry = time.Now().Unix()
+
+	if err := q.save(ResponseQueueKey(chainID, requestID), &entry); err != nil {
+		return err
+	}
+
+	return q.store.Delete(key)
+}
+
+func (q *ResponseQueue) list(prefix string) ([]*ResponseEntry, error) {
+	entries := make([]*ResponseEntry, 0)
+
+	err := q.store.Iterate([]byte(prefix+":"), func(key, value []byte) bool {
+		var entry ResponseEntry
+		if err := json.Unmarshal(value, &entry); err != nil {
+			return true
+		}
+
+		entry.Queued = prefix == KeyPrefixResponseQueue
+
+		entries = append(entries, &entry)
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (q *ResponseQueue) save(key []byte, entry *ResponseEntry) error {
+	bz, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return q.store.Set(key, bz)
+}
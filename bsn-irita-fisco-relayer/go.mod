@@ -9,13 +9,20 @@ require (
 	github.com/gin-gonic/gin v1.4.0
 	github.com/go-sql-driver/mysql v1.4.0
 	github.com/irisnet/service-sdk-go v1.0.1-0.20210416090657-1bdf41efe743
+	github.com/lib/pq v1.10.0
+	github.com/mattn/go-sqlite3 v1.14.6
+	github.com/miekg/pkcs11 v1.0.3
+	github.com/pborman/uuid v1.2.0
 	github.com/pelletier/go-toml v1.6.0 // indirect
+	github.com/prometheus/client_golang v1.8.0
 	github.com/sirupsen/logrus v1.6.0
 	github.com/spf13/afero v1.2.2 // indirect
 	github.com/spf13/cast v1.3.1 // indirect
 	github.com/spf13/cobra v1.1.1
 	github.com/spf13/jwalterweatherman v1.1.0 // indirect
 	github.com/spf13/viper v1.7.1
+	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
+	relayer/appchains/fisco v0.0.0
 )
 
 replace (
@@ -23,4 +30,5 @@ replace (
 	github.com/keybase/go-keychain => github.com/99designs/go-keychain v0.0.0-20191008050251-8e49817e8af4
 	github.com/tendermint/tendermint => github.com/bianjieai/tendermint v0.34.1-irita-210113
 	github.com/ugorji/go => github.com/ugorji/go v1.1.2
+	relayer/appchains/fisco => ../bsn-irita-appchains/fisco
 )
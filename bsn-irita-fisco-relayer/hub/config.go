@@ -7,6 +7,7 @@ import (
 
 	cmn "relayer/common"
 	cfg "relayer/config"
+	"relayer/logging"
 )
 
 // default config variables
@@ -23,6 +24,7 @@ var (
 	defaultSchemas       = ""
 	defaultProvider      = "iaa1fe6gm5kyam6xfs0wngw3d23l9djlyw82xxcjm2"
 	defaultServiceFee    = "1000000upoint"
+	defaultTimeout       = uint(100)
 	defaultQoS           = uint64(100)
 )
 
@@ -32,6 +34,7 @@ const (
 	ChainID      = "chain_id"
 	NodeRPCAddr  = "node_rpc_addr"
 	NodeGRPCAddr = "node_grpc_addr"
+	Endpoints    = "endpoints"
 	KeyPath      = "key_path"
 	KeyName      = "key_name"
 	Passphrase   = "passphrase"
@@ -39,6 +42,7 @@ const (
 	Schemas      = "schemas"
 	Provider     = "provider"
 	ServiceFee   = "service_fee"
+	Timeout      = "timeout"
 	QoS          = "qos"
 )
 
@@ -47,6 +51,7 @@ type Config struct {
 	ChainID      string `yaml:"chain_id"`
 	NodeRPCAddr  string `yaml:"node_rpc_addr"`
 	NodeGRPCAddr string `yaml:"node_grpc_addr"`
+	Endpoints    []Endpoint `yaml:"endpoints"` // RPC/gRPC address pairs to fail over among, in place of the single node
 	KeyPath      string `yaml:"key_path"`
 	KeyName      string `yaml:"key_name"`
 	Passphrase   string `yaml:"passphrase"`
@@ -54,15 +59,22 @@ type Config struct {
 	Schemas      string `yaml:"chain_id"` // input and output schemas
 	Provider     string `yaml:"chain_id"` // service provider
 	ServiceFee   string `yaml:"chain_id"` // service fee
+	Timeout      uint   `yaml:"timeout"`  // service timeout in blocks
 	QoS          uint64 `yaml:"chain_id"`  // quality of service, in terms of the minimum response time
 }
 
 // NewConfig constructs a new Config from viper
 func NewConfig(v *viper.Viper) Config {
+	var endpoints []Endpoint
+	if err := v.UnmarshalKey(cfg.GetConfigKey(Prefix, Endpoints), &endpoints); err != nil {
+		logging.Logger.Errorf("failed to parse the hub endpoints: %s", err)
+	}
+
 	return Config{
 		ChainID:      v.GetString(cfg.GetConfigKey(Prefix, ChainID)),
 		NodeRPCAddr:  v.GetString(cfg.GetConfigKey(Prefix, NodeRPCAddr)),
 		NodeGRPCAddr: v.GetString(cfg.GetConfigKey(Prefix, NodeGRPCAddr)),
+		Endpoints:    endpoints,
 		KeyPath:      v.GetString(cfg.GetConfigKey(Prefix, KeyPath)),
 		KeyName:      v.GetString(cfg.GetConfigKey(Prefix, KeyName)),
 		Passphrase:   v.GetString(cfg.GetConfigKey(Prefix, Passphrase)),
@@ -70,6 +82,7 @@ func NewConfig(v *viper.Viper) Config {
 		Schemas:      v.GetString(cfg.GetConfigKey(ServicePrefix, Schemas)),
 		Provider:     v.GetString(cfg.GetConfigKey(ServicePrefix, Provider)),
 		ServiceFee:   v.GetString(cfg.GetConfigKey(ServicePrefix, ServiceFee)),
+		Timeout:      v.GetUint(cfg.GetConfigKey(ServicePrefix, Timeout)),
 		QoS:          v.GetUint64(cfg.GetConfigKey(ServicePrefix, QoS)),
 	}
 }
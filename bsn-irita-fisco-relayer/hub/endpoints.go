@@ -0,0 +1,266 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	servicesdk "github.com/irisnet/service-sdk-go"
+
+	"relayer/core"
+	"relayer/logging"
+)
+
+const (
+	DefaultHealthCheckInterval = 30               // interval in seconds to check the health of the endpoints
+	DefaultProbeTimeout        = 10 * time.Second // timeout to probe an endpoint
+	DefaultMaxHeightLag        = 5                // blocks the active endpoint may lag behind the best one before switching
+)
+
+// connection error patterns which trigger the failover
+var connectionErrors = []string{
+	"connection refused",
+	"connection reset",
+	"broken pipe",
+	"no such host",
+	"i/o timeout",
+	"deadline exceeded",
+	"unavailable",
+	"eof",
+}
+
+// Endpoint defines a pair of the RPC and gRPC addresses of a Hub node
+type Endpoint struct {
+	RPCAddr  string `yaml:"rpc_addr" mapstructure:"rpc_addr"`
+	GRPCAddr string `yaml:"grpc_addr" mapstructure:"grpc_addr"`
+}
+
+// EndpointPool holds the service clients of the Hub endpoints
+// The active endpoint is kept as long as it is healthy and not lagging behind
+type EndpointPool struct {
+	chainID   string
+	endpoints []core.HubEndpointStatus
+	clients   []servicesdk.ServiceClient
+	active    int
+
+	onSwitch func(client servicesdk.ServiceClient) // called when the active endpoint is switched
+
+	mtx sync.RWMutex
+}
+
+// NewEndpointPool constructs a new EndpointPool instance with the clients built by the given function
+func NewEndpointPool(chainID string, endpoints []Endpoint, build func(Endpoint) servicesdk.ServiceClient) *EndpointPool {
+	pool := &EndpointPool{
+		chainID:   chainID,
+		endpoints: make([]core.HubEndpointStatus, len(endpoints)),
+		clients:   make([]servicesdk.ServiceClient, len(endpoints)),
+	}
+
+	for i, endpoint := range endpoints {
+		pool.endpoints[i] = core.HubEndpointStatus{
+			RPCAddr:  endpoint.RPCAddr,
+			GRPCAddr: endpoint.GRPCAddr,
+			Healthy:  true,
+		}
+		pool.clients[i] = build(endpoint)
+	}
+
+	return pool
+}
+
+// Client returns the service client of the active endpoint
+func (p *EndpointPool) Client() servicesdk.ServiceClient {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	return p.clients[p.active]
+}
+
+// ActiveEndpoint returns the RPC address of the active endpoint
+func (p *EndpointPool) ActiveEndpoint() string {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	return p.endpoints[p.active].RPCAddr
+}
+
+// Endpoints returns the status of all the endpoints
+func (p *EndpointPool) Endpoints() []core.HubEndpointStatus {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	endpoints := append([]core.HubEndpointStatus{}, p.endpoints...)
+	endpoints[p.active].Active = true
+
+	return endpoints
+}
+
+// OnSwitch sets the callback invoked with the new client when the active endpoint is switched
+func (p *EndpointPool) OnSwitch(cb func(client servicesdk.ServiceClient)) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.onSwitch = cb
+}
+
+// ReportError fails over from the given endpoint if the error is a connection error
+// It is a no-op if the active endpoint has been switched by others
+func (p *EndpointPool) ReportError(endpoint string, err error) {
+	if err == nil || !IsConnectionError(err) {
+		return
+	}
+
+	p.mtx.Lock()
+
+	if p.endpoints[p.active].RPCAddr != endpoint {
+		p.mtx.Unlock()
+		return
+	}
+
+	p.markUnhealthy(p.active, err)
+	p.mtx.Unlock()
+
+	p.failover()
+}
+
+// CheckHealth probes all the endpoints and switches to the healthiest one
+// if the active endpoint is unhealthy or lagging behind
+func (p *EndpointPool) CheckHealth() {
+	p.mtx.RLock()
+	clients := append([]servicesdk.ServiceClient{}, p.clients...)
+	p.mtx.RUnlock()
+
+	for i, client := range clients {
+		height, latency, err := probe(client)
+
+		p.mtx.Lock()
+		if err != nil {
+			p.markUnhealthy(i, err)
+		} else {
+			p.endpoints[i].Healthy = true
+			p.endpoints[i].Height = height
+			p.endpoints[i].Latency = latency.Milliseconds()
+			p.endpoints[i].Error = ""
+		}
+		p.mtx.Unlock()
+	}
+
+	p.failover()
+}
+
+// StartHealthCheck checks the health of the endpoints periodically
+func (p *EndpointPool) StartHealthCheck() {
+	if len(p.endpoints) < 2 {
+		return
+	}
+
+	go func() {
+		for {
+			time.Sleep(DefaultHealthCheckInterval * time.Second)
+			p.CheckHealth()
+		}
+	}()
+}
+
+// failover switches to the healthiest endpoint if the active one should be left
+func (p *EndpointPool) failover() {
+	p.mtx.Lock()
+
+	next := selectEndpoint(p.endpoints, p.active)
+	if next == p.active {
+		p.mtx.Unlock()
+		return
+	}
+
+	from := p.endpoints[p.active].RPCAddr
+	p.active = next
+	client := p.clients[next]
+	onSwitch := p.onSwitch
+
+	p.mtx.Unlock()
+
+	logging.Logger.Warnf("hub %s failed over from %s to %s", p.chainID, from, p.endpoints[next].RPCAddr)
+
+	if onSwitch != nil {
+		onSwitch(client)
+	}
+}
+
+func (p *EndpointPool) markUnhealthy(i int, cause error) {
+	p.endpoints[i].Healthy = false
+	p.endpoints[i].Failures++
+	if cause != nil {
+		p.endpoints[i].Error = cause.Error()
+	}
+}
+
+// selectEndpoint returns the index of the endpoint to use
+// The active endpoint is sticky unless it is unhealthy or lags behind the best one by more than DefaultMaxHeightLag
+// Otherwise the healthiest endpoint is preferred, i.e. the highest height, then the lowest latency and the fewest failures
+func selectEndpoint(endpoints []core.HubEndpointStatus, active int) int {
+	best := -1
+	for i, endpoint := range endpoints {
+		if !endpoint.Healthy {
+			continue
+		}
+
+		if best < 0 || healthier(endpoint, endpoints[best]) {
+			best = i
+		}
+	}
+
+	if best < 0 {
+		// all unhealthy, try the next one in turn
+		return (active + 1) % len(endpoints)
+	}
+
+	current := endpoints[active]
+	if current.Healthy && current.Height+DefaultMaxHeightLag >= endpoints[best].Height {
+		return active
+	}
+
+	return best
+}
+
+// healthier returns true if the endpoint a is healthier than b
+func healthier(a, b core.HubEndpointStatus) bool {
+	if a.Height != b.Height {
+		return a.Height > b.Height
+	}
+
+	if a.Latency != b.Latency {
+		return a.Latency < b.Latency
+	}
+
+	return a.Failures < b.Failures
+}
+
+// probe retrieves the latest height and the response latency of the given client
+func probe(client servicesdk.ServiceClient) (int64, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+
+	status, err := client.Status(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query the node status: %s", err)
+	}
+
+	return status.SyncInfo.LatestBlockHeight, time.Since(start), nil
+}
+
+// IsConnectionError returns true if the given error is caused by the connection to the node
+func IsConnectionError(err error) bool {
+	msg := strings.ToLower(err.Error())
+
+	for _, pattern := range connectionErrors {
+		if strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -41,7 +41,22 @@ func (cm *ChainManager) GetChains() []string {
 }
 
 // GetChainStatus retrieves the status of the specified app chain
-func (cm *ChainManager) GetChainStatus(chainID string) (state bool, height int64, err error) {
+func (cm *ChainManager) GetChainStatus(chainID string) (state bool, height int64, node string, err error) {
 	return cm.relayer.GetChainStatus(chainID)
 }
 
+
+// GetHubEndpoints retrieves the status of the Hub endpoints
+func (cm *ChainManager) GetHubEndpoints() []core.HubEndpointStatus {
+	return cm.relayer.GetHubEndpoints()
+}
+
+// GetDeadLetters gets the responses failed to be delivered
+func (cm *ChainManager) GetDeadLetters() ([]*core.ResponseEntry, error) {
+	return cm.relayer.GetDeadLetters()
+}
+
+// RedriveDeadLetter requeues the dead lettered response of the specified request
+func (cm *ChainManager) RedriveDeadLetter(chainID string, requestID string) error {
+	return cm.relayer.RedriveDeadLetter(chainID, requestID)
+}
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"relayer/logging"
+	"relayer/metrics"
 )
 
 // HTTPService represents an HTTP service
@@ -44,9 +45,16 @@ func (srv *HTTPService) createRouter() {
 		fiscobcos.POST("/chains/:chainid/stop", srv.StopChain)
 		fiscobcos.GET("/chains", srv.GetChains)
 		fiscobcos.GET("/chains/:chainid/status", srv.GetChainStatus)
+		fiscobcos.GET("/hub/endpoints", srv.GetHubEndpoints)
+		fiscobcos.GET("/txs", srv.ListTxs)
+		fiscobcos.GET("/txs/lookup", srv.LookupTxs)
+		fiscobcos.GET("/txs/stats", srv.GetTxStats)
+		fiscobcos.GET("/deadletters", srv.GetDeadLetters)
+		fiscobcos.POST("/deadletters/:chainid/:requestid/redrive", srv.RedriveDeadLetter)
 	}
 
 	r.GET("/health", srv.ShowHealth)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
 
 	srv.Router = r
 }
@@ -163,13 +171,49 @@ func (srv *HTTPService) GetChainStatus(c *gin.Context) {
 		return
 	}
 
-	state, height, err := srv.ChainManager.GetChainStatus(chainID)
+	state, height, node, err := srv.ChainManager.GetChainStatus(chainID)
 	if err != nil {
 		onError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	onSuccess(c, ChainStatus{State: state, Height: height})
+	onSuccess(c, ChainStatus{State: state, Height: height, Node: node})
+}
+
+func (srv *HTTPService) GetHubEndpoints(c *gin.Context) {
+	onSuccess(c, srv.ChainManager.GetHubEndpoints())
+}
+
+func (srv *HTTPService) GetDeadLetters(c *gin.Context) {
+	deadLetters, err := srv.ChainManager.GetDeadLetters()
+	if err != nil {
+		onError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	onSuccess(c, deadLetters)
+}
+
+func (srv *HTTPService) RedriveDeadLetter(c *gin.Context) {
+	chainID := c.Param("chainid")
+	if err := ValidateChainID(chainID); err != nil {
+		onError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	requestID := c.Param("requestid")
+	if len(requestID) == 0 {
+		onError(c, http.StatusBadRequest, "request ID can not be empty")
+		return
+	}
+
+	err := srv.ChainManager.RedriveDeadLetter(chainID, requestID)
+	if err != nil {
+		onError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	onSuccess(c, nil)
 }
 
 // ShowHealth returns the health state
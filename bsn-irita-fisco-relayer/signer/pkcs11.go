@@ -0,0 +1,178 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"fmt"
+	"sync"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/miekg/pkcs11"
+)
+
+// PKCS11Signer signs with the secp256k1 key held by a PKCS#11 token, e.g. an HSM or SoftHSM
+type PKCS11Signer struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	privKey pkcs11.ObjectHandle
+	pubKey  *ecdsa.PublicKey
+
+	mtx sync.Mutex // the session can not be used concurrently
+}
+
+// NewPKCS11Signer constructs a new PKCS11Signer instance
+// The private and public keys are looked up on the token by the given label
+func NewPKCS11Signer(library string, tokenLabel string, pin string, keyLabel string) (*PKCS11Signer, error) {
+	ctx := pkcs11.New(library)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load the PKCS#11 library %s", library)
+	}
+
+	if err := ctx.Initialize(); err != nil {
+		return nil, fmt.Errorf("failed to initialize the PKCS#11 library: %s", err)
+	}
+
+	slot, err := findSlot(ctx, tokenLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the PKCS#11 session: %s", err)
+	}
+
+	if err := ctx.Login(session, pkcs11.CKU_USER, pin); err != nil {
+		return nil, fmt.Errorf("failed to log in to the PKCS#11 token: %s", err)
+	}
+
+	privKey, err := findObject(ctx, session, pkcs11.CKO_PRIVATE_KEY, keyLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKeyObj, err := findObject(ctx, session, pkcs11.CKO_PUBLIC_KEY, keyLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := ctx.GetAttributeValue(session, pubKeyObj, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the public key %s: %s", keyLabel, err)
+	}
+
+	pubKey, err := parseECPoint(attrs[0].Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PKCS11Signer{
+		ctx:     ctx,
+		session: session,
+		privKey: privKey,
+		pubKey:  pubKey,
+	}, nil
+}
+
+// Address implements Signer
+func (s *PKCS11Signer) Address() ethcmn.Address {
+	return crypto.PubkeyToAddress(*s.pubKey)
+}
+
+// PublicKey implements Signer
+func (s *PKCS11Signer) PublicKey() *ecdsa.PublicKey {
+	return s.pubKey
+}
+
+// Sign implements Signer
+func (s *PKCS11Signer) Sign(digest []byte) ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	err := s.ctx.SignInit(s.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, s.privKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init the PKCS#11 signing: %s", err)
+	}
+
+	sig, err := s.ctx.Sign(s.session, digest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with the PKCS#11 token: %s", err)
+	}
+
+	return ToRecoverable(digest, sig, s.pubKey)
+}
+
+// Close logs out and releases the PKCS#11 library
+func (s *PKCS11Signer) Close() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	_ = s.ctx.Logout(s.session)
+	_ = s.ctx.CloseSession(s.session)
+	_ = s.ctx.Finalize()
+	s.ctx.Destroy()
+}
+
+// findSlot returns the slot of the token with the given label
+func findSlot(ctx *pkcs11.Ctx, tokenLabel string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the PKCS#11 slots: %s", err)
+	}
+
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+
+		if info.Label == tokenLabel {
+			return slot, nil
+		}
+	}
+
+	return 0, fmt.Errorf("PKCS#11 token %s not found", tokenLabel)
+}
+
+// findObject returns the object of the given class and label
+func findObject(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, class uint, label string) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+
+	if err := ctx.FindObjectsInit(session, template); err != nil {
+		return 0, fmt.Errorf("failed to search the PKCS#11 objects: %s", err)
+	}
+	defer ctx.FindObjectsFinal(session)
+
+	objects, _, err := ctx.FindObjects(session, 1)
+	if err != nil {
+		return 0, fmt.Errorf("failed to search the PKCS#11 objects: %s", err)
+	}
+
+	if len(objects) == 0 {
+		return 0, fmt.Errorf("PKCS#11 key %s not found", label)
+	}
+
+	return objects[0], nil
+}
+
+// parseECPoint parses the DER encoded uncompressed EC point of a secp256k1 public key
+func parseECPoint(bz []byte) (*ecdsa.PublicKey, error) {
+	var point []byte
+	if rest, err := asn1.Unmarshal(bz, &point); err != nil || len(rest) != 0 {
+		// some tokens return the raw point
+		point = bz
+	}
+
+	pubKey, err := crypto.UnmarshalPubkey(point)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the PKCS#11 public key: %s", err)
+	}
+
+	return pubKey, nil
+}
@@ -0,0 +1,151 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/viper"
+)
+
+const (
+	BackendKeystore = "keystore" // encrypted go-ethereum keystore file
+	BackendPKCS11   = "pkcs11"   // key held by a PKCS#11 token
+	BackendRemote   = "remote"   // key held by a remote signing service
+
+	// config keys
+	Backend      = "backend"
+	KeystoreFile = "keystore_file"
+	Passphrase   = "passphrase"
+	Library      = "library"
+	TokenLabel   = "token_label"
+	Pin          = "pin"
+	KeyLabel     = "key_label"
+	URL          = "url"
+	KeyID        = "key_id"
+	Token        = "token"
+)
+
+// Signer signs the digests of the app chain txs with a secp256k1 key
+type Signer interface {
+	// Address returns the address of the signing key
+	Address() ethcmn.Address
+
+	// PublicKey returns the public key of the signing key
+	PublicKey() *ecdsa.PublicKey
+
+	// Sign signs the given 32-byte digest and returns the signature in the [R || S || V] format
+	Sign(digest []byte) ([]byte, error)
+}
+
+// Config defines the config of a signer backend
+type Config struct {
+	Backend string `yaml:"backend"`
+
+	// keystore backend
+	KeystoreFile string `yaml:"keystore_file"`
+	Passphrase   string `yaml:"passphrase"`
+
+	// pkcs11 backend
+	Library    string `yaml:"library"`
+	TokenLabel string `yaml:"token_label"`
+	Pin        string `yaml:"pin"`
+	KeyLabel   string `yaml:"key_label"`
+
+	// remote backend
+	URL   string `yaml:"url"`
+	KeyID string `yaml:"key_id"`
+	Token string `yaml:"token"`
+}
+
+// LoadConfigs loads the named signer configs under the given key from viper
+func LoadConfigs(v *viper.Viper, key string) map[string]Config {
+	configs := make(map[string]Config)
+
+	for name := range v.GetStringMap(key) {
+		prefix := fmt.Sprintf("%s.%s.", key, name)
+
+		configs[name] = Config{
+			Backend:      v.GetString(prefix + Backend),
+			KeystoreFile: v.GetString(prefix + KeystoreFile),
+			Passphrase:   v.GetString(prefix + Passphrase),
+			Library:      v.GetString(prefix + Library),
+			TokenLabel:   v.GetString(prefix + TokenLabel),
+			Pin:          v.GetString(prefix + Pin),
+			KeyLabel:     v.GetString(prefix + KeyLabel),
+			URL:          v.GetString(prefix + URL),
+			KeyID:        v.GetString(prefix + KeyID),
+			Token:        v.GetString(prefix + Token),
+		}
+	}
+
+	return configs
+}
+
+// NewSigner constructs a new Signer from the given config
+func NewSigner(config Config) (Signer, error) {
+	switch config.Backend {
+	case BackendKeystore:
+		return NewKeystoreSigner(config.KeystoreFile, config.Passphrase)
+
+	case BackendPKCS11:
+		return NewPKCS11Signer(config.Library, config.TokenLabel, config.Pin, config.KeyLabel)
+
+	case BackendRemote:
+		return NewRemoteSigner(config.URL, config.KeyID, config.Token)
+
+	default:
+		return nil, fmt.Errorf("signer backend %s is not supported", config.Backend)
+	}
+}
+
+// Select returns the signer of the given name from the configs
+func Select(configs map[string]Config, name string) (Signer, error) {
+	config, ok := configs[name]
+	if !ok {
+		return nil, fmt.Errorf("signer %s is not configured", name)
+	}
+
+	s, err := NewSigner(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signer %s: %s", name, err)
+	}
+
+	return s, nil
+}
+
+// ToRecoverable converts the given [R || S] signature to the [R || S || V] format
+// S is normalized to the lower half of the curve order and V is found by recovering the given public key
+func ToRecoverable(digest []byte, sig []byte, pubKey *ecdsa.PublicKey) ([]byte, error) {
+	if len(sig) != 64 {
+		return nil, fmt.Errorf("invalid signature length %d", len(sig))
+	}
+
+	n := crypto.S256().Params().N
+
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+	}
+
+	recoverable := make([]byte, 65)
+	copy(recoverable[32-len(r.Bytes()):32], r.Bytes())
+	copy(recoverable[64-len(s.Bytes()):64], s.Bytes())
+
+	address := crypto.PubkeyToAddress(*pubKey)
+
+	for v := byte(0); v < 2; v++ {
+		recoverable[64] = v
+
+		recovered, err := crypto.SigToPub(digest, recoverable)
+		if err == nil && crypto.PubkeyToAddress(*recovered) == address {
+			return recoverable, nil
+		}
+	}
+
+	return nil, fmt.Errorf("signature does not match the public key of %s", address.Hex())
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/cockroachdb/pebble"
 )
 
+// ErrNotFound is returned when the requested key does not exist
+var ErrNotFound = pebble.ErrNotFound
+
 // Store defines a struct for data store
 type Store struct {
 	db *pebble.DB
@@ -45,7 +48,11 @@ func (s *Store) Get(key []byte) ([]byte, error) {
 
 	defer closer.Close()
 
-	return value, nil
+	// the value is only valid until the closer is closed
+	bz := make([]byte, len(value))
+	copy(bz, value)
+
+	return bz, nil
 }
 
 // GetInt64 is a convenience to get the int64 typed value
@@ -67,4 +74,41 @@ func (s *Store) Delete(key []byte) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// Iterate calls the given function for each key-value with the given prefix in key order
+// The iteration stops when the function returns false
+func (s *Store) Iterate(prefix []byte, fn func(key, value []byte) bool) error {
+	iter := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		key := make([]byte, len(iter.Key()))
+		copy(key, iter.Key())
+
+		value := make([]byte, len(iter.Value()))
+		copy(value, iter.Value())
+
+		if !fn(key, value) {
+			break
+		}
+	}
+
+	return iter.Close()
+}
+
+// prefixUpperBound returns the smallest key greater than all the keys with the given prefix
+func prefixUpperBound(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,307 @@
+package opb
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sync"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"relayer/appchains/adapter/adapterpb"
+	cfg "relayer/config"
+	"relayer/core"
+	"relayer/logging"
+	"relayer/store"
+)
+
+const (
+	AdapterPrefix = "adapter"
+
+	AdapterListenAddr   = "listen_addr"
+	AdapterCertFile     = "cert_file"
+	AdapterKeyFile      = "key_file"
+	AdapterClientCAFile = "client_ca_file"
+)
+
+// AdapterConfig defines the config of the adapter server
+type AdapterConfig struct {
+	ListenAddr   string `yaml:"listen_addr"`    // address to serve the adapter protocol on, disabled if empty
+	CertFile     string `yaml:"cert_file"`      // server certificate, plaintext if empty
+	KeyFile      string `yaml:"key_file"`       // server key
+	ClientCAFile string `yaml:"client_ca_file"` // CA to verify the relayer certificates, not verified if empty
+}
+
+// NewAdapterConfig constructs a new AdapterConfig instance from viper
+func NewAdapterConfig(v *viper.Viper) AdapterConfig {
+	return AdapterConfig{
+		ListenAddr:   v.GetString(cfg.GetConfigKey(AdapterPrefix, AdapterListenAddr)),
+		CertFile:     v.GetString(cfg.GetConfigKey(AdapterPrefix, AdapterCertFile)),
+		KeyFile:      v.GetString(cfg.GetConfigKey(AdapterPrefix, AdapterKeyFile)),
+		ClientCAFile: v.GetString(cfg.GetConfigKey(AdapterPrefix, AdapterClientCAFile)),
+	}
+}
+
+// AdapterServer serves the opb chains to the relayers hosting the opb type through the adapter protocol
+// The chain params passed through by the relayers are the opb chain params. The chains are built
+// with the base config of this relayer, without being registered to it
+type AdapterServer struct {
+	store *store.Store
+
+	mtx    sync.Mutex
+	chains map[string]*adaptedChain // chains of the unary calls by chain ID
+}
+
+// adaptedChain defines the chain built from the params passed through
+type adaptedChain struct {
+	params []byte
+	chain  *OpbChain
+}
+
+// NewAdapterServer constructs a new AdapterServer instance
+func NewAdapterServer(store *store.Store) *AdapterServer {
+	return &AdapterServer{
+		store:  store,
+		chains: make(map[string]*adaptedChain),
+	}
+}
+
+// Serve serves the adapter protocol on the configured address in the background
+func (s *AdapterServer) Serve(config AdapterConfig) error {
+	var opts []grpc.ServerOption
+
+	if len(config.CertFile) > 0 {
+		tlsConfig, err := loadServerTLSConfig(config)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	lis, err := net.Listen("tcp", config.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %s", config.ListenAddr, err)
+	}
+
+	srv := grpc.NewServer(opts...)
+	adapterpb.RegisterAppChainAdapterServer(srv, s)
+
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			logging.Logger.Errorf("adapter server stopped: %s", err)
+		}
+	}()
+
+	logging.Logger.Infof("adapter server listening on %s", config.ListenAddr)
+
+	return nil
+}
+
+// loadServerTLSConfig loads the TLS config to authenticate the server and verify the relayers
+func loadServerTLSConfig(config AdapterConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the server certificate: %s", err)
+	}
+
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	if len(config.ClientCAFile) > 0 {
+		ca, err := ioutil.ReadFile(config.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the client CA file: %s", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no valid certificate in the client CA file %s", config.ClientCAFile)
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// Start implements AppChainAdapterServer
+// Each stream scans a chain instance of its own, which is stopped when the stream is closed
+func (s *AdapterServer) Start(req *adapterpb.StartRequest, stream adapterpb.AppChainAdapter_StartServer) error {
+	chain, err := s.buildChain(req.Chain)
+	if err != nil {
+		return err
+	}
+
+	var mtx sync.Mutex
+	closed := false
+
+	// the requests are handled while the block next to the last scanned height is scanned
+	handler := func(chainID string, request core.InterchainRequest, txHash string) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		if closed {
+			return fmt.Errorf("request stream of chain %s closed", chain.ChainID)
+		}
+
+		return stream.Send(&adapterpb.InterchainRequest{
+			Id:              request.ID,
+			DestChainId:     request.DestChainID,
+			DestSubChainId:  request.DestSubChainID,
+			DestChainType:   request.DestChainType,
+			EndpointAddress: request.EndpointAddress,
+			EndpointType:    request.EndpointType,
+			Method:          request.Method,
+			CallData:        request.CallData,
+			TxHash:          txHash,
+			Sender:          request.Sender,
+			Timeout:         request.Timeout,
+			Timestamp:       request.Timestamp,
+			Height:          chain.lastHeight + 1,
+		})
+	}
+
+	chain.startHeight = req.StartHeight
+	if err := chain.start(handler); err != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	<-stream.Context().Done()
+
+	mtx.Lock()
+	closed = true
+	mtx.Unlock()
+
+	return chain.Stop()
+}
+
+// SendResponse implements AppChainAdapterServer
+func (s *AdapterServer) SendResponse(ctx context.Context, req *adapterpb.SendResponseRequest) (*adapterpb.SendResponseReply, error) {
+	chain, err := s.getChain(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	response := core.ResponseAdaptor{StatusCode: 200, Output: req.Output}
+	if len(req.ErrMsg) > 0 {
+		response = core.ResponseAdaptor{StatusCode: 500, Result: req.ErrMsg}
+	}
+
+	txHash, err := chain.sendResponse(req.RequestId, response)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &adapterpb.SendResponseReply{TxHash: txHash}, nil
+}
+
+// GetHeight implements AppChainAdapterServer
+func (s *AdapterServer) GetHeight(ctx context.Context, req *adapterpb.GetHeightRequest) (*adapterpb.GetHeightReply, error) {
+	chain, err := s.getChain(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	height, err := chain.getBlockNumber()
+	if err != nil {
+		chain.failover(chain.GetActiveNode(), err)
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &adapterpb.GetHeightReply{Height: height, Node: chain.GetActiveNode()}, nil
+}
+
+// Health implements AppChainAdapterServer
+func (s *AdapterServer) Health(ctx context.Context, req *adapterpb.HealthRequest) (*adapterpb.HealthReply, error) {
+	chain, err := s.getChain(req.Chain)
+	if err != nil {
+		return &adapterpb.HealthReply{Healthy: false, Error: err.Error()}, nil
+	}
+
+	if _, err := chain.getBlockNumber(); err != nil {
+		chain.failover(chain.GetActiveNode(), err)
+		return &adapterpb.HealthReply{Healthy: false, Error: err.Error()}, nil
+	}
+
+	return &adapterpb.HealthReply{Healthy: true}, nil
+}
+
+// CallContract implements AppChainAdapterServer
+// The failed calls are not reported as unavailable, as the tx may have been sent
+func (s *AdapterServer) CallContract(ctx context.Context, req *adapterpb.CallContractRequest) (*adapterpb.CallContractReply, error) {
+	chain, err := s.getChain(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	output, txHash, err := chain.CallContract(req.EndpointAddress, req.Method, req.CallData)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &adapterpb.CallContractReply{Output: output, TxHash: txHash}, nil
+}
+
+// getChain returns the chain of the unary calls, which is rebuilt if the params are changed
+func (s *AdapterServer) getChain(c *adapterpb.Chain) (*OpbChain, error) {
+	if c == nil {
+		return nil, status.Error(codes.InvalidArgument, "chain not specified")
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if adapted, ok := s.chains[c.ChainId]; ok && bytes.Equal(adapted.params, c.Params) {
+		return adapted.chain, nil
+	}
+
+	chain, err := s.buildChain(c)
+	if err != nil {
+		return nil, err
+	}
+
+	s.chains[c.ChainId] = &adaptedChain{params: c.Params, chain: chain}
+
+	return chain, nil
+}
+
+// buildChain builds the chain from the params passed through, identified by the chain ID of the relayer
+func (s *AdapterServer) buildChain(c *adapterpb.Chain) (*OpbChain, error) {
+	if c == nil {
+		return nil, status.Error(codes.InvalidArgument, "chain not specified")
+	}
+
+	var params ChainParams
+	if err := json.Unmarshal(c.Params, &params); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid params of chain %s: %s", c.ChainId, err)
+	}
+
+	baseConfig, err := loadBaseConfig(s.store)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to load the base config: %s", err)
+	}
+
+	config := Config{
+		BaseConfig:  baseConfig,
+		ChainParams: params,
+	}
+
+	chain, err := newOpbChain(config, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to build chain %s: %s", c.ChainId, err)
+	}
+
+	chain.ChainID = c.ChainId
+
+	return chain, nil
+}
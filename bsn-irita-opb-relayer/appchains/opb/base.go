@@ -20,6 +20,10 @@ type ChainParams struct {
 	NodeURLs         []string `json:"nodes"`
 	ChainID          int64    `json:"chainId"`
 	IServiceCoreAddr string   `json:"iserviceCoreAddr"`
+	StartHeight      int64    `json:"startHeight,omitempty"` // height to start scanning from when it is beyond the persisted height
+	FromLatest       bool     `json:"fromLatest,omitempty"`  // whether to skip the missed blocks and start from the latest block
+	Timeout          int64    `json:"timeout,omitempty"`     // service timeout in blocks on the Hub for the requests of the chain
+	Signer           string   `json:"signer,omitempty"`      // name of the configured signer, the key in the base config is used if empty
 }
 
 type EndpointInfo struct {
@@ -20,17 +20,22 @@ import (
 
 	"relayer/core"
 	"relayer/logging"
+	"relayer/metrics"
+	"relayer/secrets"
+	"relayer/signer"
 	"relayer/store"
 )
 
 // opbChain defines the opb chain
 type OpbChain struct {
-	Config    Config
-	OpbClient *hub.ServiceClient
-	ChainID   string // unique chain ID
+	Config  Config
+	ChainID string // unique chain ID
 
-	store      *store.Store // store backend instance
-	lastHeight int64        // last height
+	nodes *NodeClient // client of the active node
+
+	store       *store.Store // store backend instance
+	lastHeight  int64        // last height
+	startHeight int64        // height to start scanning from, 0 for the latest block
 
 	done    bool                          // indicates if the chain monitor is done
 	handler core.InterchainRequestHandler // handler for the interchain request
@@ -41,20 +46,36 @@ func NewOpbChain(
 	config Config,
 	store *store.Store,
 ) (*OpbChain, error) {
-	//将接口传递的节点名称通过配置转换为 节点地址，如果不在配置中，不转换
-	//随机取一个传入的node
-	nodeName := randURL(config.ChainParams.NodeURLs)
-	var rpcAddr string
-	var grpcAddr string
-	//获取配置的nodeURL
-	rpcAddrStr, ok := config.RpcAddrsMap[nodeName]
-	if ok {
-		rpcAddr = rpcAddrStr
-	}
-	grpcAddrStr, ok := config.GrpcAddrsMap[nodeName]
-	if ok {
-		grpcAddr = grpcAddrStr
+	opb, err := newOpbChain(config, store)
+	if err != nil {
+		return nil, err
 	}
+
+	err = opb.storeChainParams()
+	if err != nil {
+		return nil, err
+	}
+
+	err = opb.storeChainID()
+	if err != nil {
+		return nil, err
+	}
+
+	//chainHeight, err := store.GetInt64(HeightKey(chainID))
+	//if err != nil {
+	//	log.WithFields(log.Fields{"err_info": err.Error(), "chain_id": chainID}).Error("get chain height err when opb chain client is initializing")
+	//	return nil, err
+	//}
+	//log.WithFields(log.Fields{"chain_height": chainHeight, "chain_id": chainID}).Info("finish initializing opb chain client")
+
+	return opb, nil
+}
+
+// newOpbChain constructs a new OpbChain instance without registering it to the store
+func newOpbChain(
+	config Config,
+	store *store.Store,
+) (*OpbChain, error) {
 	fees, _ := sdktypes.ParseDecCoins(config.DefaultFee)
 
 	var keyDAO sdkstore.KeyDAO
@@ -70,53 +91,62 @@ func NewOpbChain(
 		sdktypes.FeeOption(fees),
 		sdktypes.GasOption(config.DefaultGas),
 		sdktypes.TimeoutOption(config.Timeout),
-		sdktypes.AlgoOption(defaultAlgo),
 	}
 
-	clientConfig, err := sdktypes.NewClientConfig(
-		rpcAddr,
-		grpcAddr,
-		config.BaseConfig.ChainId,
-		options...,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to init clientConfig: %s", err)
-	}
-	opbClient := hub.NewServiceClient(clientConfig)
-	chainID := GetChainID(config.ChainParams)
+	// sign with the selected signer in place of the local keys
+	if len(config.ChainParams.Signer) > 0 {
+		keySigner, err := signer.Select(config.Signers, config.ChainParams.Signer)
+		if err != nil {
+			return nil, err
+		}
 
-	opb := &OpbChain{
-		Config:    config,
-		OpbClient: opbClient,
-		ChainID:   chainID,
-		store:     store,
-		done:      true,
+		options = append(
+			options,
+			sdktypes.AlgoOption(signerAlgo),
+			sdktypes.KeyManagerOption(newSignerKeyManager(keySigner)),
+		)
+	} else {
+		options = append(options, sdktypes.AlgoOption(defaultAlgo))
 	}
 
-	// import opb key
-	if config.KeyMode == "mem" {
-		addr, err := opb.OpbClient.Import(config.KeyName, config.Passphrase, config.KeyArmor)
+	// the clients of all the nodes share the key DAO
+	buildClient := func(rpcAddr, grpcAddr string) (*hub.ServiceClient, error) {
+		clientConfig, err := sdktypes.NewClientConfig(
+			rpcAddr,
+			grpcAddr,
+			config.BaseConfig.ChainId,
+			options...,
+		)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to init clientConfig: %s", err)
 		}
-		log.WithFields(log.Fields{"addr": addr}).Info("import opb account success")
+
+		return hub.NewServiceClient(clientConfig), nil
 	}
-	err = opb.storeChainParams()
+
+	chainID := GetChainID(config.ChainParams)
+
+	nodes, err := NewNodeClient(chainID, config, buildClient)
 	if err != nil {
 		return nil, err
 	}
 
-	err = opb.storeChainID()
-	if err != nil {
-		return nil, err
+	opb := &OpbChain{
+		Config:  config,
+		ChainID: chainID,
+		nodes:   nodes,
+		store:   store,
+		done:    true,
 	}
 
-	//chainHeight, err := store.GetInt64(HeightKey(chainID))
-	//if err != nil {
-	//	log.WithFields(log.Fields{"err_info": err.Error(), "chain_id": chainID}).Error("get chain height err when opb chain client is initializing")
-	//	return nil, err
-	//}
-	//log.WithFields(log.Fields{"chain_height": chainHeight, "chain_id": chainID}).Info("finish initializing opb chain client")
+	// import opb key
+	if config.KeyMode == "mem" && len(config.ChainParams.Signer) == 0 {
+		addr, err := opb.nodes.Client().Import(config.KeyName, config.Passphrase, config.KeyArmor)
+		if err != nil {
+			return nil, err
+		}
+		log.WithFields(log.Fields{"addr": addr}).Info("import opb account success")
+	}
 
 	return opb, nil
 }
@@ -126,19 +156,18 @@ func BuildOpbChain(
 	chainParams []byte,
 	store *store.Store,
 ) (*OpbChain, error) {
-	var params ChainParams
-	err := json.Unmarshal(chainParams, &params)
+	chainParams, err := secrets.Open(chainParams)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to decrypt the chain params: %s", err)
 	}
 
-	baseCfgBz, err := store.Get(BaseConfigKey())
+	var params ChainParams
+	err = json.Unmarshal(chainParams, &params)
 	if err != nil {
 		return nil, err
 	}
 
-	var baseConfig BaseConfig
-	err = json.Unmarshal(baseCfgBz, &baseConfig)
+	baseConfig, err := loadBaseConfig(store)
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +180,25 @@ func BuildOpbChain(
 	return NewOpbChain(config, store)
 }
 
+// loadBaseConfig loads the base config from the store
+func loadBaseConfig(store *store.Store) (BaseConfig, error) {
+	var baseConfig BaseConfig
+
+	baseCfgBz, err := store.Get(BaseConfigKey())
+	if err != nil {
+		return baseConfig, err
+	}
+
+	baseCfgBz, err = secrets.Open(baseCfgBz)
+	if err != nil {
+		return baseConfig, fmt.Errorf("failed to decrypt the base config: %s", err)
+	}
+
+	err = json.Unmarshal(baseCfgBz, &baseConfig)
+
+	return baseConfig, err
+}
+
 // GetChainID implements AppChainI
 func (opb *OpbChain) GetChainID() string {
 	return opb.ChainID
@@ -162,6 +210,18 @@ func (opb *OpbChain) Start(handler core.InterchainRequestHandler) error {
 		return fmt.Errorf("chain %s has been started", opb.ChainID)
 	}
 
+	if opb.lastHeight == 0 {
+		err := opb.loadStartHeight()
+		if err != nil {
+			return err
+		}
+	}
+
+	return opb.start(handler)
+}
+
+// start starts the chain monitor from the start height
+func (opb *OpbChain) start(handler core.InterchainRequestHandler) error {
 	opb.done = false
 	opb.handler = handler
 
@@ -185,8 +245,28 @@ func (opb *OpbChain) GetHeight() int64 {
 	return opb.lastHeight
 }
 
+// GetActiveNode implements AppChainI
+func (opb *OpbChain) GetActiveNode() string {
+	return opb.nodes.ActiveNode()
+}
+
+// failover switches to the next node if the given node is still active
+func (opb *OpbChain) failover(node string, cause error) {
+	logging.Logger.Errorf("node %s of chain %s failed: %s", node, opb.ChainID, cause)
+
+	if err := opb.nodes.Failover(node, cause); err != nil {
+		logging.Logger.Errorf("failed to fail over on %s: %s", opb.ChainID, err)
+	}
+}
+
 // SendResponse implements AppChainI
 func (opb *OpbChain) SendResponse(requestID string, response core.ResponseI) error {
+	_, err := opb.sendResponse(requestID, response)
+	return err
+}
+
+// sendResponse sends the response and returns the hash of the response tx
+func (opb *OpbChain) sendResponse(requestID string, response core.ResponseI) (string, error) {
 	execAbi := wasm.NewContractABI().
 		WithMethod("set_response").
 		WithArgs("request_id", requestID).
@@ -203,36 +283,35 @@ func (opb *OpbChain) SendResponse(requestID string, response core.ResponseI) err
 		txstore.RelayerResponeRecord(d)
 	}(data)
 
-	resultTx, err := opb.OpbClient.WASM.Execute(opb.Config.ChainParams.IServiceCoreAddr, execAbi, nil, opb.BuildBaseTx())
+	resultTx, err := opb.nodes.Client().WASM.Execute(opb.Config.ChainParams.IServiceCoreAddr, execAbi, nil, opb.BuildBaseTx())
 	if err != nil {
 		data.TxStatus = txstore.TxStatus_Error
 		data.ErrMsg = fmt.Sprintf("call opb setResponse failed :%s", err)
 		//mysql.TxErrCollection(requestID, err.Error())
-		return err
+		return "", err
 	}
 	data.FromResTxId = resultTx.Hash.String()
-	// TODO
-	//mysql.OnInterchainRequestResponseSent(requestID, resultTx.Hash)
+
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseSent, data.FromResTxId, "")
 
 	err = opb.waitForSuccess(resultTx.Hash.String(), "SetResponse")
 	if err != nil {
 		//mysql.TxErrCollection(requestID, err.Error())
 		data.TxStatus = txstore.TxStatus_Error
 		data.ErrMsg = fmt.Sprintf("call opb setResponse failed :%s", err)
-		return err
+		return data.FromResTxId, err
 	}
 
-	// TODO
-	//mysql.OnInterchainRequestSucceeded(requestID)
+	txstore.RecordTxTransition(requestID, txstore.TxState_ResponseConfirmed, data.FromResTxId, "")
 
-	return nil
+	return data.FromResTxId, nil
 }
 
 // waitForSuccess waits for the receipt of the given tx
 func (opb *OpbChain) waitForSuccess(txHash string, name string) error {
 	logging.Logger.Infof("%s: transaction sent to %s, hash: %s", name, opb.GetChainID(), txHash)
 
-	tx, _ := opb.OpbClient.QueryTx(txHash)
+	tx, _ := opb.nodes.Client().QueryTx(txHash)
 	if tx.TxResult.Code != 0 {
 		return fmt.Errorf("transaction %s execution failed: %s", txHash, tx.TxResult.Log)
 	}
@@ -286,6 +365,7 @@ func (opb *OpbChain) buildInterchainRequest(e abci.Event) core.InterchainRequest
 		EndpointType:    endpointInfo.EndpointType,
 		Method:          method,
 		CallData:        callDataBytes,
+		Timeout:         opb.Config.ChainParams.Timeout,
 	}
 }
 
@@ -303,9 +383,16 @@ func convCallData(data string) []byte {
 
 // monitor is responsible for monitoring the chain
 func (opb *OpbChain) monitor() {
+	lastCheck := time.Now()
+
 	for {
 		opb.scan()
 
+		if time.Since(lastCheck) >= DefaultHealthCheckInterval*time.Second {
+			opb.nodes.CheckHealth()
+			lastCheck = time.Now()
+		}
+
 		if opb.done {
 			return
 		}
@@ -316,14 +403,22 @@ func (opb *OpbChain) monitor() {
 
 // scan performs chain scanning
 func (opb *OpbChain) scan() {
+	node := opb.nodes.ActiveNode()
+
 	currentHeight, err := opb.getBlockNumber()
 	if err != nil {
-		logging.Logger.Errorf("failed to get the current block height: %s", err)
+		opb.failover(node, fmt.Errorf("failed to get the current block height: %s", err))
 		return
 	}
 
+	metrics.SetHeadHeight(opb.ChainID, currentHeight)
+
 	if opb.lastHeight == 0 {
-		opb.lastHeight = currentHeight - 1
+		if opb.startHeight > 0 {
+			opb.lastHeight = opb.startHeight - 1
+		} else {
+			opb.lastHeight = currentHeight - 1
+		}
 	}
 
 	if currentHeight <= opb.lastHeight {
@@ -336,15 +431,22 @@ func (opb *OpbChain) scan() {
 // scanBlocks scans the blocks of the specified range
 func (opb *OpbChain) scanBlocks(startHeight int64, endHeight int64) {
 	for h := startHeight; h <= endHeight; {
-		blockResult, err := opb.OpbClient.BlockResults(context.Background(), &h)
+		if opb.done {
+			return
+		}
+
+		node := opb.nodes.ActiveNode()
+		client := opb.nodes.Client()
+
+		blockResult, err := client.BlockResults(context.Background(), &h)
 		if err != nil {
-			logging.Logger.Errorf(err.Error())
+			opb.failover(node, err)
 			time.Sleep(time.Duration(10) * time.Second)
 			continue
 		}
-		block, err := opb.OpbClient.Block(context.Background(), &h)
+		block, err := client.Block(context.Background(), &h)
 		if err != nil {
-			logging.Logger.Errorf(err.Error())
+			opb.failover(node, err)
 			time.Sleep(time.Duration(10) * time.Second)
 			continue
 		}
@@ -361,7 +463,7 @@ func (opb *OpbChain) scanBlocks(startHeight int64, endHeight int64) {
 
 // getBlockNumber retrieves the current block number
 func (opb *OpbChain) getBlockNumber() (int64, error) {
-	resultState, err := opb.OpbClient.Status(context.Background())
+	resultState, err := opb.nodes.Client().Status(context.Background())
 	if err != nil {
 		return -1, err
 	}
@@ -377,6 +479,7 @@ func (opb *OpbChain) parseCrossChainRequest(txResults []*abci.ResponseDeliverTx,
 				contractAddr, _ := opb.getAttributeValue(e, "_contract_address")
 				if contractAddr == opb.Config.ChainParams.IServiceCoreAddr {
 					request := opb.buildInterchainRequest(e)
+					request.Timestamp = block.Block.Time.UnixNano() / int64(time.Millisecond)
 					err := opb.handler(opb.ChainID, request, strings.ToUpper(hex.EncodeToString(block.Block.Txs[i].Hash())))
 					if err != nil {
 
@@ -404,6 +507,11 @@ func (opb *OpbChain) storeChainParams() error {
 		return err
 	}
 
+	bz, err = secrets.Seal(bz)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the chain params: %s", err)
+	}
+
 	return opb.store.Set(ChainParamsKey(opb.ChainID), bz)
 }
 
@@ -422,8 +530,46 @@ func (opb *OpbChain) storeChainID() error {
 	return opb.store.Set([]byte("chainIDs"), bz)
 }
 
+// loadStartHeight determines the height to start scanning from
+// The scanning resumes from the persisted height unless the chain params specify
+// a greater start height or to start from the latest block
+func (opb *OpbChain) loadStartHeight() error {
+	opb.startHeight = 0
+
+	if opb.Config.FromLatest {
+		logging.Logger.Infof("chain %s starts scanning from the latest block", opb.ChainID)
+		return nil
+	}
+
+	height, err := opb.store.GetInt64(HeightKey(opb.ChainID))
+	if err != nil && err != store.ErrNotFound {
+		return fmt.Errorf("failed to load the height of chain %s: %s", opb.ChainID, err)
+	}
+
+	if err == nil {
+		opb.startHeight = height + 1
+	}
+
+	if opb.Config.StartHeight > opb.startHeight {
+		opb.startHeight = opb.Config.StartHeight
+	}
+
+	if opb.startHeight > 0 {
+		logging.Logger.Infof("chain %s starts scanning from height %d", opb.ChainID, opb.startHeight)
+	}
+
+	return nil
+}
+
 // updateHeight updates the height
 func (opb *OpbChain) updateHeight(height int64) error {
 	opb.lastHeight = height
+	metrics.SetScannedHeight(opb.ChainID, height)
+
+	// the height of the chain served to an adapter client is kept by the client
+	if opb.store == nil {
+		return nil
+	}
+
 	return opb.store.SetInt64(HeightKey(opb.ChainID), height)
 }
@@ -3,9 +3,9 @@ package opb
 import (
 	"encoding/json"
 	"github.com/spf13/viper"
-	"math/rand"
 
 	cfg "relayer/config"
+	"relayer/signer"
 )
 
 const (
@@ -24,6 +24,7 @@ const (
 	DefaultFee      = "default_fee"
 	DefaultGas      = "default_gas"
 	Timeout         = "timeout"
+	Signers         = "signers"
 )
 
 const (
@@ -44,6 +45,7 @@ type BaseConfig struct {
 	Timeout         uint
 	DefaultGas      uint64
 	MonitorInterval uint64
+	Signers         map[string]signer.Config // named signers selectable by the chains
 }
 
 func (bc *BaseConfig) PrintConfig() {
@@ -70,17 +72,9 @@ func NewBaseConfig(v *viper.Viper) (*BaseConfig, error) {
 	config.KeyName = v.GetString(cfg.GetConfigKey(Prefix, KeyName))
 	config.Passphrase = v.GetString(cfg.GetConfigKey(Prefix, Passphrase))
 	config.KeyArmor = v.GetString(cfg.GetConfigKey(Prefix, KeyArmor))
+	config.Signers = signer.LoadConfigs(v, cfg.GetConfigKey(Prefix, Signers))
 	return config, nil
 }
-func randURL(m []string) string {
-	if len(m) == 0 {
-		return ""
-	}
-	for _, index := range rand.Perm(len(m)) {
-		return m[index]
-	}
-	return ""
-}
 
 // ValidBaseConfig validates if the given bytes is valid BaseConfig
 func ValidateBaseConfig(baseCfg []byte) error {
@@ -0,0 +1,238 @@
+package opb
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"relayer/hub"
+	"relayer/logging"
+)
+
+const (
+	DefaultHealthCheckInterval = 30 // interval in seconds to check the health of the nodes
+)
+
+// NodeStatus defines the health status of a node
+type NodeStatus struct {
+	Name     string `json:"name"`
+	RpcAddr  string `json:"rpc_addr"`
+	GrpcAddr string `json:"grpc_addr"`
+	Healthy  bool   `json:"healthy"`
+	Height   int64  `json:"height"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ClientBuilder builds the service client connected to the given node
+type ClientBuilder func(rpcAddr, grpcAddr string) (*hub.ServiceClient, error)
+
+// NodeClient holds the client of the active node of the chain
+// It fails over to the other nodes on connection or query errors
+type NodeClient struct {
+	chainID string
+	nodes   []NodeStatus
+	active  int
+	build   ClientBuilder
+
+	client *hub.ServiceClient
+
+	mtx sync.RWMutex
+}
+
+// NewNodeClient constructs a new NodeClient instance and connects to the first available node
+// The node names are resolved by the rpc and grpc address maps and the connecting starts from a random node
+func NewNodeClient(chainID string, config Config, build ClientBuilder) (*NodeClient, error) {
+	if len(config.ChainParams.NodeURLs) == 0 {
+		return nil, fmt.Errorf("no node specified for chain %s", chainID)
+	}
+
+	nodes := make([]NodeStatus, len(config.ChainParams.NodeURLs))
+	for i, name := range config.ChainParams.NodeURLs {
+		nodes[i] = NodeStatus{
+			Name:     name,
+			RpcAddr:  config.RpcAddrsMap[name],
+			GrpcAddr: config.GrpcAddrsMap[name],
+			Healthy:  true,
+		}
+	}
+
+	nc := &NodeClient{
+		chainID: chainID,
+		nodes:   nodes,
+		active:  rand.Intn(len(nodes)),
+		build:   build,
+	}
+
+	nc.mtx.Lock()
+	defer nc.mtx.Unlock()
+
+	if err := nc.connect(); err != nil {
+		return nil, err
+	}
+
+	return nc, nil
+}
+
+// Client returns the client of the active node
+func (nc *NodeClient) Client() *hub.ServiceClient {
+	nc.mtx.RLock()
+	defer nc.mtx.RUnlock()
+
+	return nc.client
+}
+
+// ActiveNode returns the name of the active node
+func (nc *NodeClient) ActiveNode() string {
+	nc.mtx.RLock()
+	defer nc.mtx.RUnlock()
+
+	return nc.nodes[nc.active].Name
+}
+
+// Nodes returns the status of all the nodes
+func (nc *NodeClient) Nodes() []NodeStatus {
+	nc.mtx.RLock()
+	defer nc.mtx.RUnlock()
+
+	return append([]NodeStatus{}, nc.nodes...)
+}
+
+// Failover switches to the next available node if the given node is still active
+// It is a no-op if the active node has been switched by others
+func (nc *NodeClient) Failover(node string, cause error) error {
+	nc.mtx.Lock()
+	defer nc.mtx.Unlock()
+
+	if nc.nodes[nc.active].Name != node {
+		return nil
+	}
+
+	nc.markUnhealthy(nc.active, cause)
+
+	return nc.switchNode()
+}
+
+// CheckHealth probes all the nodes and fails over if the active node is unhealthy
+func (nc *NodeClient) CheckHealth() {
+	nodes := nc.Nodes()
+	active := nc.ActiveNode()
+
+	for i, node := range nodes {
+		var height int64
+		var err error
+
+		if node.Name == active {
+			height, err = probe(nc.Client())
+		} else {
+			height, err = nc.probeNode(node)
+		}
+
+		nc.mtx.Lock()
+		if err != nil {
+			nc.markUnhealthy(i, err)
+		} else {
+			nc.nodes[i].Healthy = true
+			nc.nodes[i].Height = height
+			nc.nodes[i].Error = ""
+		}
+		nc.mtx.Unlock()
+	}
+
+	nc.mtx.RLock()
+	healthy := nc.nodes[nc.active].Healthy
+	nc.mtx.RUnlock()
+
+	if !healthy {
+		if err := nc.Failover(active, fmt.Errorf("health check failed")); err != nil {
+			logging.Logger.Errorf("failed to fail over on %s: %s", nc.chainID, err)
+		}
+	}
+}
+
+// switchNode connects to the next available node
+// The healthy nodes are tried before the unhealthy ones
+func (nc *NodeClient) switchNode() error {
+	from := nc.nodes[nc.active].Name
+
+	next := nc.active
+	for i := 1; i <= len(nc.nodes); i++ {
+		j := (nc.active + i) % len(nc.nodes)
+		if nc.nodes[j].Healthy {
+			next = j
+			break
+		}
+	}
+
+	if next == nc.active {
+		next = (nc.active + 1) % len(nc.nodes)
+	}
+
+	nc.active = next
+
+	if err := nc.connect(); err != nil {
+		return err
+	}
+
+	logging.Logger.Warnf("chain %s failed over from %s to %s", nc.chainID, from, nc.nodes[nc.active].Name)
+
+	return nil
+}
+
+// connect builds the client of the active node, and the following nodes in turn on failure
+func (nc *NodeClient) connect() error {
+	var err error
+
+	for i := 0; i < len(nc.nodes); i++ {
+		node := nc.nodes[nc.active]
+
+		client, connErr := nc.build(node.RpcAddr, node.GrpcAddr)
+		if connErr == nil {
+			var height int64
+			if height, connErr = probe(client); connErr == nil {
+				nc.client = client
+				nc.nodes[nc.active].Healthy = true
+				nc.nodes[nc.active].Height = height
+				nc.nodes[nc.active].Error = ""
+
+				return nil
+			}
+		}
+
+		err = connErr
+		nc.markUnhealthy(nc.active, connErr)
+
+		logging.Logger.Errorf("failed to connect to node %s of %s: %s", node.Name, nc.chainID, connErr)
+
+		nc.active = (nc.active + 1) % len(nc.nodes)
+	}
+
+	return fmt.Errorf("failed to connect to opb node: %s", err)
+}
+
+func (nc *NodeClient) markUnhealthy(i int, cause error) {
+	nc.nodes[i].Healthy = false
+	if cause != nil {
+		nc.nodes[i].Error = cause.Error()
+	}
+}
+
+// probeNode builds a client of the given node and retrieves the latest height
+func (nc *NodeClient) probeNode(node NodeStatus) (int64, error) {
+	client, err := nc.build(node.RpcAddr, node.GrpcAddr)
+	if err != nil {
+		return 0, err
+	}
+
+	return probe(client)
+}
+
+// probe retrieves the latest height by the given client
+func probe(client *hub.ServiceClient) (int64, error) {
+	status, err := client.Status(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	return status.SyncInfo.LatestBlockHeight, nil
+}
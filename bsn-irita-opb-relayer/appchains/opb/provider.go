@@ -0,0 +1,43 @@
+package opb
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/bianjieai/iritamod-sdk-go/wasm"
+)
+
+// CallContract implements ContractCallerI
+// The call data is the JSON object of the method arguments, which are passed to the WASM contract by name
+func (opb *OpbChain) CallContract(endpointAddress string, method string, callData []byte) (output string, txHash string, err error) {
+	var args map[string]interface{}
+	if err := json.Unmarshal(callData, &args); err != nil {
+		return "", "", fmt.Errorf("invalid call data of %s: %s", method, err)
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	execAbi := wasm.NewContractABI().WithMethod(method)
+	for _, name := range names {
+		execAbi = execAbi.WithArgs(name, args[name])
+	}
+
+	resultTx, err := opb.nodes.Client().WASM.Execute(endpointAddress, execAbi, nil, opb.BuildBaseTx())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to call %s of %s: %s", method, endpointAddress, err)
+	}
+
+	txHash = resultTx.Hash.String()
+
+	if err := opb.waitForSuccess(txHash, method); err != nil {
+		return "", txHash, err
+	}
+
+	return "", txHash, nil
+}
@@ -0,0 +1,76 @@
+package opb
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/irisnet/core-sdk-go/crypto/keys/secp256k1"
+	sdktypes "github.com/irisnet/core-sdk-go/types"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+
+	"relayer/signer"
+)
+
+const signerAlgo = "secp256k1"
+
+// signerKeyManager signs the opb txs with the configured signer in place of the local keys
+type signerKeyManager struct {
+	keySigner signer.Signer
+}
+
+// newSignerKeyManager constructs a new signerKeyManager instance
+func newSignerKeyManager(keySigner signer.Signer) *signerKeyManager {
+	return &signerKeyManager{
+		keySigner: keySigner,
+	}
+}
+
+// Sign implements KeyManager
+// The signature is in the [R || S] format over the SHA256 digest of the data
+func (k *signerKeyManager) Sign(name, password string, data []byte) ([]byte, tmcrypto.PubKey, error) {
+	digest := sha256.Sum256(data)
+
+	sig, err := k.keySigner.Sign(digest[:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sig[:64], k.pubKey(), nil
+}
+
+// Find implements KeyManager
+func (k *signerKeyManager) Find(name, password string) (tmcrypto.PubKey, sdktypes.AccAddress, error) {
+	pubKey := k.pubKey()
+	return pubKey, sdktypes.AccAddress(pubKey.Address()), nil
+}
+
+// Insert implements KeyManager
+func (k *signerKeyManager) Insert(name, password string) (string, string, error) {
+	return "", "", fmt.Errorf("key management is not supported by the signer")
+}
+
+// Recover implements KeyManager
+func (k *signerKeyManager) Recover(name, password, mnemonic, hdPath string) (string, error) {
+	return "", fmt.Errorf("key management is not supported by the signer")
+}
+
+// Import implements KeyManager
+func (k *signerKeyManager) Import(name, password string, privKeyArmor string) (string, error) {
+	return "", fmt.Errorf("key management is not supported by the signer")
+}
+
+// Export implements KeyManager
+func (k *signerKeyManager) Export(name, password string) (string, error) {
+	return "", fmt.Errorf("key management is not supported by the signer")
+}
+
+// Delete implements KeyManager
+func (k *signerKeyManager) Delete(name, password string) error {
+	return fmt.Errorf("key management is not supported by the signer")
+}
+
+// pubKey returns the compressed secp256k1 public key of the signer
+func (k *signerKeyManager) pubKey() *secp256k1.PubKey {
+	return &secp256k1.PubKey{Key: crypto.CompressPubkey(k.keySigner.PublicKey())}
+}
@@ -1,19 +1,23 @@
 package store
 
 import (
-	"relayer/common/mysql"
+	"fmt"
+
+	"relayer/common/ledger"
 	"relayer/logging"
 )
 
-// init mysql
-// created table if the table not exist
+// init the ledger
+// the tables are created and altered by the migrations
 
 //tableName :
 //	tb_irita_crosschain_tx
 //	tb_irita_fabric_relayer
+//	tb_irita_crosschain_tx_transition
 
 const (
-	_TabName_cc_Tx = "tb_irita_crosschain_tx"
+	_TabName_cc_Tx            = "tb_irita_crosschain_tx"
+	_TabName_cc_Tx_Transition = "tb_irita_crosschain_tx_transition"
 
 	_Create_CrossChain_Tx_Sql = `CREATE TABLE tb_irita_crosschain_tx (
   funique_id bigint(20) NOT NULL AUTO_INCREMENT,
@@ -31,22 +35,105 @@ const (
   tx_createtime datetime NOT NULL DEFAULT '1999-01-01 00:00:00' COMMENT '交易创建时间',
   error text DEFAULT NULL COMMENT '异常',
   source_service int(1) NOT NULL DEFAULT '0' COMMENT '存储交易记录的来源服务0:表示relayer，1：表示provider',
+  tx_state varchar(32) NOT NULL DEFAULT '' COMMENT '请求生命周期状态',
   PRIMARY KEY (funique_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;`
+
+	_Add_Tx_State_Column_Sql = `ALTER TABLE tb_irita_crosschain_tx ADD COLUMN tx_state varchar(32) NOT NULL DEFAULT '' COMMENT '请求生命周期状态';`
+
+	_Create_CrossChain_Tx_Transition_Sql = `CREATE TABLE tb_irita_crosschain_tx_transition (
+  funique_id bigint(20) NOT NULL AUTO_INCREMENT,
+  request_id varchar(255) NOT NULL DEFAULT '' COMMENT '请求唯一id',
+  tx_state varchar(32) NOT NULL DEFAULT '' COMMENT '迁移后的状态',
+  tx_hash varchar(255) NOT NULL DEFAULT '' COMMENT '相关交易ID',
+  error text DEFAULT NULL COMMENT '异常',
+  transition_time datetime NOT NULL DEFAULT '1999-01-01 00:00:00' COMMENT '状态迁移时间',
+  source_service int(1) NOT NULL DEFAULT '0' COMMENT '存储交易记录的来源服务0:表示relayer，1：表示provider',
+  PRIMARY KEY (funique_id),
+  KEY idx_request_id (request_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;`
+
+	// DDL of the tables in the SQL dialects other than MySQL
+	// the placeholders are the auto-increment primary key and the datetime type
+	_Create_CrossChain_Tx_Sql_Generic = `CREATE TABLE tb_irita_crosschain_tx (
+  funique_id %[1]s,
+  request_id varchar(255) NOT NULL DEFAULT '',
+  from_chainid varchar(255) NOT NULL DEFAULT '',
+  from_tx varchar(255) NOT NULL DEFAULT '',
+  hub_req_tx varchar(255) NOT NULL DEFAULT '',
+  ic_request_id varchar(255) NOT NULL DEFAULT '',
+  to_chainid varchar(255) NOT NULL DEFAULT '',
+  to_tx varchar(255) DEFAULT NULL,
+  hub_res_tx varchar(255) NOT NULL DEFAULT '',
+  from_res_tx varchar(255) DEFAULT NULL,
+  tx_status int NOT NULL DEFAULT 0,
+  tx_time %[2]s DEFAULT NULL,
+  tx_createtime %[2]s NOT NULL DEFAULT '1999-01-01 00:00:00',
+  error text DEFAULT NULL,
+  source_service int NOT NULL DEFAULT 0,
+  tx_state varchar(32) NOT NULL DEFAULT ''
+)`
+
+	_Add_Tx_State_Column_Sql_Generic = `ALTER TABLE tb_irita_crosschain_tx ADD COLUMN tx_state varchar(32) NOT NULL DEFAULT ''`
+
+	_Create_CrossChain_Tx_Transition_Sql_Generic = `CREATE TABLE tb_irita_crosschain_tx_transition (
+  funique_id %[1]s,
+  request_id varchar(255) NOT NULL DEFAULT '',
+  tx_state varchar(32) NOT NULL DEFAULT '',
+  tx_hash varchar(255) NOT NULL DEFAULT '',
+  error text DEFAULT NULL,
+  transition_time %[2]s NOT NULL DEFAULT '1999-01-01 00:00:00',
+  source_service int NOT NULL DEFAULT 0
+)`
+
+	_Create_Transition_Index_Sql_Generic = `CREATE INDEX idx_transition_request_id ON tb_irita_crosschain_tx_transition (request_id)`
 )
 
-func InitMysql(conn string) {
-	logging.Logger.Infof("初始化Mysql : %s", conn)
-	mysql.Init(conn)
-	checkTable(_Create_CrossChain_Tx_Sql, _TabName_cc_Tx)
+// schema defines the DDL of the ledger tables in the SQL dialect of a driver
+type schema struct {
+	createTxTable         []string
+	addTxStateColumn      string
+	createTransitionTable []string
 }
 
-func checkTable(sql, tabName string) {
-	logging.Logger.Infof("检查数据库：%s", tabName)
-	if mysql.TabIsExit(tabName) {
-		logging.Logger.Infof("数据库已存在:%s", tabName)
-	} else {
-		logging.Logger.Info("数据库不存在:", tabName)
-		mysql.CreateTable(sql, tabName)
+// schemas defines the DDL of the ledger tables per driver
+var schemas = map[string]schema{
+	ledger.DriverMySQL: {
+		createTxTable:         []string{_Create_CrossChain_Tx_Sql},
+		addTxStateColumn:      _Add_Tx_State_Column_Sql,
+		createTransitionTable: []string{_Create_CrossChain_Tx_Transition_Sql},
+	},
+	ledger.DriverPostgres: newGenericSchema("BIGSERIAL PRIMARY KEY", "timestamp"),
+	ledger.DriverSQLite:   newGenericSchema("INTEGER PRIMARY KEY AUTOINCREMENT", "datetime"),
+}
+
+func newGenericSchema(primaryKey string, datetime string) schema {
+	return schema{
+		createTxTable:    []string{fmt.Sprintf(_Create_CrossChain_Tx_Sql_Generic, primaryKey, datetime)},
+		addTxStateColumn: _Add_Tx_State_Column_Sql_Generic,
+		createTransitionTable: []string{
+			fmt.Sprintf(_Create_CrossChain_Tx_Transition_Sql_Generic, primaryKey, datetime),
+			_Create_Transition_Index_Sql_Generic,
+		},
+	}
+}
+
+// InitLedger initializes the ledger backend of the given config and applies the pending migrations
+func InitLedger(config ledger.Config) error {
+	logging.Logger.Infof("初始化账本数据库 : %s", config.Driver)
+
+	if _, ok := schemas[config.Driver]; !ok {
+		return fmt.Errorf("unsupported ledger driver: %s", config.Driver)
 	}
+
+	if err := ledger.Init(config); err != nil {
+		return fmt.Errorf("failed to init the ledger backend: %s", err)
+	}
+
+	applied, err := ledger.Migrate(Migrations(config.Driver))
+	for _, m := range applied {
+		logging.Logger.Infof("ledger migration %d applied: %s", m.Version, m.Name)
+	}
+
+	return err
 }
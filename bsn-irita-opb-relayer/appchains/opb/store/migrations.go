@@ -0,0 +1,96 @@
+package store
+
+import (
+	"fmt"
+
+	"relayer/common/ledger"
+)
+
+// indexes of the cross-chain tx table on the columns filtered by the updates
+var txIndexes = []struct {
+	name   string
+	column string
+}{
+	{"idx_tx_request_id", "request_id"},
+	{"idx_tx_ic_request_id", "ic_request_id"},
+}
+
+// Migrations returns the ordered migrations of the ledger schema in the SQL dialect of the given driver
+// Append a new migration with the next version to change the schema, never modify the released ones
+// The tables created before the migrations are tracked are left as is
+func Migrations(driver string) []ledger.Migration {
+	s := schemas[driver]
+
+	return []ledger.Migration{
+		{
+			Version: 1,
+			Name:    "create the cross-chain tx table",
+			Up:      createTable(_TabName_cc_Tx, s.createTxTable),
+		},
+		{
+			Version: 2,
+			Name:    "add the lifecycle state to the cross-chain tx",
+			Up:      addColumn(_TabName_cc_Tx, "tx_state", s.addTxStateColumn),
+		},
+		{
+			Version: 3,
+			Name:    "create the cross-chain tx transition table",
+			Up:      createTable(_TabName_cc_Tx_Transition, s.createTransitionTable),
+		},
+		{
+			Version: 4,
+			Name:    "index the request IDs of the cross-chain tx",
+			Up:      createTxIndexes,
+		},
+	}
+}
+
+// createTable creates the table by the given statements unless it exists
+func createTable(table string, sqls []string) func(b ledger.Backend) error {
+	return func(b ledger.Backend) error {
+		exists, err := b.TableExists(table)
+		if err != nil || exists {
+			return err
+		}
+
+		for _, sql := range sqls {
+			if _, _, err := b.Exec(sql); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// addColumn adds the column by the given statement unless it exists
+func addColumn(table string, column string, sql string) func(b ledger.Backend) error {
+	return func(b ledger.Backend) error {
+		exists, err := b.ColumnExists(table, column)
+		if err != nil || exists {
+			return err
+		}
+
+		_, _, err = b.Exec(sql)
+		return err
+	}
+}
+
+func createTxIndexes(b ledger.Backend) error {
+	for _, index := range txIndexes {
+		exists, err := b.IndexExists(_TabName_cc_Tx, index.name)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			continue
+		}
+
+		if _, _, err := b.Exec(fmt.Sprintf("CREATE INDEX %s ON %s (%s)", index.name, _TabName_cc_Tx, index.column)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
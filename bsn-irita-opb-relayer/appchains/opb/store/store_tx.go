@@ -2,7 +2,7 @@ package store
 
 import (
 	"fmt"
-	"relayer/common/mysql"
+	"relayer/common/ledger"
 	"relayer/logging"
 	"time"
 )
@@ -56,10 +56,11 @@ func InitRelayerTransRecord(
 		"tx_createtime, "+
 		"tx_status, "+
 		"error, "+
-		"source_service ) "+
-		"VALUES ( ?, ?, ?,?, ?, ?, ?,?,?,?);", _TabName_cc_Tx)
+		"source_service, "+
+		"tx_state ) "+
+		"VALUES ( ?, ?, ?,?, ?, ?, ?,?,?,?,?);", _TabName_cc_Tx)
 
-	lastId, rows, err := mysql.Exec(insertsql,
+	lastId, rows, err := ledger.Exec(insertsql,
 		requestId,
 		fromChainId,
 		fromTxId,
@@ -69,12 +70,14 @@ func InitRelayerTransRecord(
 		NowTime(),
 		txStatus,
 		errMsg,
-		source_service)
+		source_service,
+		TxState_Detected)
 
 	if err != nil {
 		logging.Logger.Errorf("Init Relayer trans record Failed :%s", err.Error())
 	} else {
 		logging.Logger.Infof("Init Relayer trans record  lastId:%d ;rows:%d ", lastId, rows)
+		insertTxTransition(requestId, TxState_Detected, fromTxId, errMsg)
 	}
 
 	return
@@ -95,7 +98,7 @@ func RelayerResponeRecord(data *RelayerResInfo) {
 	logging.Logger.Infof("set relayer callback record , requestId is %s,tx status is %d", data.RequestId, data.TxStatus)
 	sql := fmt.Sprintf("update %s set from_res_tx = ? ,error = ? ,tx_time =? ,tx_status = ? where request_id = ? and source_service = %d", _TabName_cc_Tx, source_service)
 
-	lastId, rows, err := mysql.Exec(sql,
+	lastId, rows, err := ledger.Exec(sql,
 		data.FromResTxId,
 		data.ErrMsg,
 		NowTime(),
@@ -110,9 +113,21 @@ func RelayerResponeRecord(data *RelayerResInfo) {
 
 }
 
+// RelayerSubmitErrorRecord records the last error to submit the interchain request to the Hub
+// The tx status is left unknown as the submission is retried
+func RelayerSubmitErrorRecord(requestId string, errMsg string) {
+	logging.Logger.Infof("set relayer submit error record , requestId is %s", requestId)
+	sql := fmt.Sprintf("update %s set error = ? ,tx_time = ? where request_id = ? and source_service = %d", _TabName_cc_Tx, source_service)
+
+	if _, _, err := ledger.Exec(sql, errMsg, NowTime(), requestId); err != nil {
+		logging.Logger.Errorf("set relayer submit error record Failed :%s", err.Error())
+	}
+}
+
+
 //requestId ,to_chainid,ic_request_id ,to_tx,hub_res_tx ,tx_status,error,source_service
 
-//InitProviderTransRecord
+// InitProviderTransRecord
 func InitProviderTransRecord(requestId, to_chainid, ic_request_id, to_tx, error string, tx_status int) {
 
 	logging.Logger.Infof("Init Provider trans record , requestId is %s,tx status is %d", requestId, tx_status)
@@ -127,7 +142,7 @@ func InitProviderTransRecord(requestId, to_chainid, ic_request_id, to_tx, error
 		"source_service ) "+
 		"VALUES ( ?, ?, ?,?, ?, ?,?,?);", _TabName_cc_Tx)
 
-	lastId, rows, err := mysql.Exec(insertsql,
+	lastId, rows, err := ledger.Exec(insertsql,
 		requestId,
 		ic_request_id,
 		to_chainid,
@@ -152,7 +167,7 @@ type ProviderResInfo struct {
 	ErrMsg      string
 }
 
-//ic_request_id ,hub_res_tx
+// ic_request_id ,hub_res_tx
 func ProviderCallBackTransRecord(data *ProviderResInfo) {
 	logging.Logger.Infof("set provider callback record , ic_requestId is %s,tx status is %d", data.IcRequestId, data.TxStatus)
 	if data.IcRequestId == "" {
@@ -160,7 +175,7 @@ func ProviderCallBackTransRecord(data *ProviderResInfo) {
 	}
 	sql := fmt.Sprintf("update %s set hub_res_tx = ? ,error = ? ,tx_time =? ,tx_status = ? where ic_request_id = ? and source_service = %d", _TabName_cc_Tx, source_service)
 
-	lastId, rows, err := mysql.Exec(sql,
+	lastId, rows, err := ledger.Exec(sql,
 		data.HUBResTxId,
 		data.ErrMsg,
 		NowTime(),
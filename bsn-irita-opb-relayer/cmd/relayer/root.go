@@ -20,6 +20,8 @@ func main() {
 
 	rootCmd.AddCommand(StartCmd())
 	rootCmd.AddCommand(HubCmd)
+	rootCmd.AddCommand(SecretsCmd)
+	rootCmd.AddCommand(DBCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
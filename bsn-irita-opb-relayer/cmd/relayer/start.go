@@ -5,12 +5,13 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"relayer/appchains"
+	"relayer/appchains/opb"
 	txStore "relayer/appchains/opb/store"
+	"relayer/common/ledger"
 	cfg "relayer/config"
 	"relayer/core"
 	"relayer/hub"
 	"relayer/logging"
-	"relayer/mysql"
 	"relayer/server"
 	"relayer/store"
 )
@@ -46,15 +47,18 @@ func StartCmd() *cobra.Command {
 				return err
 			}
 
-			mysqlConfig := mysql.NewConfig(config)
-
-			txStore.InitMysql(mysqlConfig.DSN())
-			//mysql.NewDB(mysqlConfig)
-			//defer mysql.Close()
+			if err := txStore.InitLedger(ledger.NewConfig(config)); err != nil {
+				return err
+			}
+			defer ledger.Close()
 
 			appChainFactory := appchains.NewAppChainFactory(store)
 			hubChain := hub.BuildIritaHubChain(hub.NewConfig(config))
-			relayerInstance := core.NewRelayer(appChainType, hubChain, appChainFactory, logging.Logger)
+			hubChain.Endpoints.StartHealthCheck()
+			relayerInstance := core.NewRelayer(appChainType, hubChain, appChainFactory, store, logging.Logger)
+			if retention := cfg.GetRetention(config); retention != 0 {
+				relayerInstance.Retention = retention
+			}
 
 			baseConfigFactory := appchains.NewBaseConfigFactory(config)
 			BaseConfig, err := baseConfigFactory.NewBaseConfig(appChainType)
@@ -94,6 +98,20 @@ func StartCmd() *cobra.Command {
 				}
 			}
 
+			// resume the response subscriptions interrupted by the last shutdown
+			relayerInstance.RecoverSubscriptions()
+
+			// resubmit the interchain requests and responses left in the queues
+			relayerInstance.StartQueues()
+
+			// serve the opb chains to the relayers hosting the opb type through the adapter protocol
+			adapterConfig := opb.NewAdapterConfig(config)
+			if len(adapterConfig.ListenAddr) > 0 {
+				if err := opb.NewAdapterServer(store).Serve(adapterConfig); err != nil {
+					return err
+				}
+			}
+
 			chainManager := server.NewChainManager(relayerInstance)
 
 			httpPort := config.GetInt(_HttpPort)
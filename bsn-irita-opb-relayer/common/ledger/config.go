@@ -0,0 +1,38 @@
+package ledger
+
+import (
+	"github.com/spf13/viper"
+
+	cfg "relayer/config"
+	"relayer/mysql"
+)
+
+const (
+	Prefix    = "ledger"
+	DriverKey = "driver"
+	DSNKey    = "dsn"
+)
+
+// Config represents the ledger backend config
+type Config struct {
+	Driver string `yaml:"driver"` // one of mysql, postgres and sqlite
+	DSN    string `yaml:"dsn"`    // data source name of the driver
+}
+
+// NewConfig constructs a new Config from viper
+// The mysql config is used if the ledger driver is not configured
+func NewConfig(v *viper.Viper) Config {
+	config := Config{
+		Driver: v.GetString(cfg.GetConfigKey(Prefix, DriverKey)),
+		DSN:    v.GetString(cfg.GetConfigKey(Prefix, DSNKey)),
+	}
+
+	if len(config.Driver) == 0 {
+		mysqlConfig := mysql.NewConfig(v)
+
+		config.Driver = DriverMySQL
+		config.DSN = mysqlConfig.DSN()
+	}
+
+	return config
+}
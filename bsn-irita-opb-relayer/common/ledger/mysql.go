@@ -0,0 +1,61 @@
+package ledger
+
+import (
+	"database/sql"
+
+	"relayer/common/mysql"
+)
+
+// mysqlBackend is the MySQL backend built on the common mysql helpers
+type mysqlBackend struct{}
+
+// NewMySQLBackend creates a MySQL backend with the given DSN
+func NewMySQLBackend(dsn string) (Backend, error) {
+	mysql.Init(dsn)
+
+	return mysqlBackend{}, nil
+}
+
+// Driver implements Backend
+func (mysqlBackend) Driver() string {
+	return DriverMySQL
+}
+
+// Exec implements Backend
+func (mysqlBackend) Exec(query string, args ...interface{}) (int64, int64, error) {
+	return mysql.Exec(query, args...)
+}
+
+// Query implements Backend
+func (mysqlBackend) Query(option QueryOption, query string, args ...interface{}) ([]interface{}, error) {
+	return mysql.Query(mysql.QueryOption(option), query, args...)
+}
+
+// TableExists implements Backend
+func (mysqlBackend) TableExists(table string) (bool, error) {
+	return mysql.TabIsExit(table), nil
+}
+
+// ColumnExists implements Backend
+func (mysqlBackend) ColumnExists(table string, column string) (bool, error) {
+	return mysql.ColumnIsExist(table, column), nil
+}
+
+// IndexExists implements Backend
+func (mysqlBackend) IndexExists(table string, index string) (bool, error) {
+	list, err := mysql.Query(func(rows *sql.Rows) (interface{}, error) {
+		var count int64
+		err := rows.Scan(&count)
+		return count, err
+	}, "SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?", table, index)
+	if err != nil {
+		return false, err
+	}
+
+	return len(list) > 0 && list[0].(int64) > 0, nil
+}
+
+// Close implements Backend
+func (mysqlBackend) Close() error {
+	return nil
+}
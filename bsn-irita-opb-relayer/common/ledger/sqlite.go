@@ -0,0 +1,28 @@
+package ledger
+
+import (
+	"database/sql"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// NewSQLiteBackend creates an embedded SQLite backend with the given DSN
+// e.g. ./relayer.db or file::memory:?cache=shared
+func NewSQLiteBackend(dsn string) (Backend, error) {
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows a single writer
+	db.SetMaxOpenConns(1)
+
+	return &sqlBackend{
+		driver:          DriverSQLite,
+		db:              db,
+		rebind:          func(query string) string { return query },
+		tableExistsSql:  "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?",
+		columnExistsSql: "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?",
+		indexExistsSql:  "SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND name = ?",
+	}, nil
+}
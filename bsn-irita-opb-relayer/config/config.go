@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
+
+	"relayer/secrets"
 )
 
 const (
@@ -11,6 +14,7 @@ const (
 
 	ConfigKeyAppChainType = "base.app_chain_type"
 	ConfigKeyStorePath    = "base.store_path"
+	ConfigKeyRetention    = "base.retention_days"
 
 	DefaultStorePath = ".db"
 )
@@ -31,9 +35,21 @@ func LoadYAMLConfig(configFileName string) (*viper.Viper, error) {
 		return nil, fmt.Errorf("failed to read the config file: %s", err)
 	}
 
+	// decrypt the encrypted secrets transparently
+	err = secrets.DecryptConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
 	return v, nil
 }
 
+// GetRetention returns the period to keep the processed records in the store for
+// Zero is returned if not configured, in which case the default retention applies
+func GetRetention(v *viper.Viper) time.Duration {
+	return time.Duration(v.GetInt64(ConfigKeyRetention)) * 24 * time.Hour
+}
+
 // GetConfigKey returns the key with the given prefix
 func GetConfigKey(prefix string, key string) string {
 	return fmt.Sprintf("%s.%s", prefix, key)
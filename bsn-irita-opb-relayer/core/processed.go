@@ -0,0 +1,162 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"relayer/store"
+)
+
+const (
+	KeyPrefixProcessed  = "processed:request"
+	KeyPrefixSubmitting = "submitting:request"
+
+	DefaultRetention     = 30 * 24 * time.Hour // period to keep the processed requests for
+	DefaultPruneInterval = time.Hour           // interval to prune the records beyond the retention
+)
+
+// ProcessedRequest defines the interchain request which has been submitted to the Hub chain
+type ProcessedRequest struct {
+	SourceChainID string `json:"source_chain_id"`
+	RequestID     string `json:"request_id"`
+	TxHash        string `json:"tx_hash"`
+	HubReqTxID    string `json:"hub_req_tx_id"`
+	ReqCtxID      string `json:"req_ctx_id"`
+	IcRequestID   string `json:"ic_request_id"`
+	ProcessedTime int64  `json:"processed_time"`
+	SourceTime    int64  `json:"source_time,omitempty"` // unix time in milliseconds of the source tx
+}
+
+// ProcessedIndex persists the processed interchain requests to skip the duplicate ones
+type ProcessedIndex struct {
+	store *store.Store
+}
+
+// NewProcessedIndex constructs a new ProcessedIndex instance
+func NewProcessedIndex(store *store.Store) *ProcessedIndex {
+	return &ProcessedIndex{
+		store: store,
+	}
+}
+
+// ProcessedKey returns the key of the given request
+func ProcessedKey(sourceChainID string, requestID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s", KeyPrefixProcessed, sourceChainID, requestID))
+}
+
+// Get retrieves the given processed request
+// False is returned if the request has not been processed
+func (p *ProcessedIndex) Get(sourceChainID string, requestID string) (ProcessedRequest, bool, error) {
+	var processed ProcessedRequest
+
+	bz, err := p.store.Get(ProcessedKey(sourceChainID, requestID))
+	if err != nil {
+		if err == store.ErrNotFound {
+			return processed, false, nil
+		}
+
+		return processed, false, err
+	}
+
+	if err := json.Unmarshal(bz, &processed); err != nil {
+		return processed, false, err
+	}
+
+	return processed, true, nil
+}
+
+// Add records the given request with the info of the Hub request
+func (p *ProcessedIndex) Add(sourceChainID string, request InterchainRequest, info InterchainRequestInfo) error {
+	bz, err := json.Marshal(ProcessedRequest{
+		SourceChainID: sourceChainID,
+		RequestID:     request.ID,
+		TxHash:        request.TxHash,
+		HubReqTxID:    info.HubReqTxId,
+		ReqCtxID:      info.ReqCtxId,
+		IcRequestID:   info.IcRequestId,
+		ProcessedTime: time.Now().Unix(),
+		SourceTime:    request.Timestamp,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := p.store.Set(ProcessedKey(sourceChainID, request.ID), bz); err != nil {
+		return err
+	}
+
+	return p.store.Delete(SubmittingKey(sourceChainID, request.ID))
+}
+
+// SubmittingKey returns the key of the submission marker of the given request
+func SubmittingKey(sourceChainID string, requestID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s", KeyPrefixSubmitting, sourceChainID, requestID))
+}
+
+// MarkSubmitting records that the given request is about to be submitted to the Hub chain
+// The time of the first submission is kept
+func (p *ProcessedIndex) MarkSubmitting(sourceChainID string, requestID string) error {
+	if _, ok, err := p.Submitting(sourceChainID, requestID); err != nil || ok {
+		return err
+	}
+
+	return p.store.SetInt64(SubmittingKey(sourceChainID, requestID), time.Now().Unix())
+}
+
+// Submitting retrieves the time when the given request was first submitted
+// False is returned if the request has not been submitted
+func (p *ProcessedIndex) Submitting(sourceChainID string, requestID string) (time.Time, bool, error) {
+	unix, err := p.store.GetInt64(SubmittingKey(sourceChainID, requestID))
+	if err != nil {
+		if err == store.ErrNotFound {
+			return time.Time{}, false, nil
+		}
+
+		return time.Time{}, false, err
+	}
+
+	return time.Unix(unix, 0), true, nil
+}
+
+// Prune removes the requests processed and the submission markers recorded before the given time
+// The number of the removed records is returned
+func (p *ProcessedIndex) Prune(before time.Time) (int, error) {
+	expired := make([][]byte, 0)
+
+	err := p.store.Iterate([]byte(KeyPrefixProcessed+":"), func(key, value []byte) bool {
+		var processed ProcessedRequest
+		if err := json.Unmarshal(value, &processed); err == nil && processed.ProcessedTime < before.Unix() {
+			expired = append(expired, key)
+		}
+
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	submitting := make([][]byte, 0)
+
+	err = p.store.Iterate([]byte(KeyPrefixSubmitting+":"), func(key, value []byte) bool {
+		submitting = append(submitting, key)
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range submitting {
+		if since, err := p.store.GetInt64(key); err == nil && since < before.Unix() {
+			expired = append(expired, key)
+		}
+	}
+
+	for i, key := range expired {
+		if err := p.store.Delete(key); err != nil {
+			return i, err
+		}
+	}
+
+	return len(expired), nil
+}
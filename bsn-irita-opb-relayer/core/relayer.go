@@ -3,8 +3,12 @@ package core
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	"relayer/metrics"
+	"relayer/store"
 )
 
 // Relayer represents a relayer transmitting msgs
@@ -16,16 +20,26 @@ type Relayer struct {
 	AppChains       map[string]AppChainI
 	AppChainStates  map[string]bool
 	AppChainFactory AppChainFactoryI
+	Queue           *RequestQueue
+	ResponseQueue   *ResponseQueue
+	Subscriptions   *SubscriptionStore
+	Processed       *ProcessedIndex
+	Retention       time.Duration // period to keep the processed requests for
 	Logger          *log.Logger
-	mtx             sync.Mutex
+	mtx             sync.RWMutex // guards the app chains and their states
 }
 
 // NewRelayer constructs a new Relayer instance
-func NewRelayer(appChainType string, hub HubChainI, appChainFactory AppChainFactoryI, logger *log.Logger) *Relayer {
+func NewRelayer(appChainType string, hub HubChainI, appChainFactory AppChainFactoryI, store *store.Store, logger *log.Logger) *Relayer {
 	return &Relayer{
 		AppChainType:    appChainType,
 		HubChain:        hub,
 		AppChainFactory: appChainFactory,
+		Queue:           NewRequestQueue(store, DefaultRequestMaxAttempts),
+		ResponseQueue:   NewResponseQueue(store, DefaultResponseMaxAttempts),
+		Subscriptions:   NewSubscriptionStore(store),
+		Processed:       NewProcessedIndex(store),
+		Retention:       DefaultRetention,
 		Logger:          logger,
 		AppChains:       map[string]AppChainI{},
 		AppChainStates:  map[string]bool{},
@@ -82,6 +96,7 @@ func (r *Relayer) DeleteChain(chainID string) error {
 	}
 	delete(r.AppChains, chainID)
 	delete(r.AppChainStates, chainID)
+	metrics.RemoveChain(chainID)
 	r.AppChainFactory.DeleteChainConfig(r.AppChainType, chainID)
 
 	return nil
@@ -137,6 +152,9 @@ func (r *Relayer) StopChain(chainID string) error {
 
 // GetChain gets the specified app chain
 func (r *Relayer) GetChain(chainID string) (appChain AppChainI, err error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
 	appChain, ok := r.AppChains[chainID]
 	if !ok {
 		return nil, fmt.Errorf("chain ID %s does not exist", chainID)
@@ -149,8 +167,8 @@ func (r *Relayer) GetChain(chainID string) (appChain AppChainI, err error) {
 func (r *Relayer) GetChains() []string {
 	chains := make([]string, 0)
 
-	r.mtx.Lock()
-	defer r.mtx.Unlock()
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
 
 	for c, s := range r.AppChainStates {
 		if s {
@@ -161,14 +179,33 @@ func (r *Relayer) GetChains() []string {
 	return chains
 }
 
-// GetChainStatus gets the status of the specified app chain
-func (r *Relayer) GetChainStatus(chainID string) (state bool, height int64, err error) {
+// GetHubEndpoints gets the status of the Hub endpoints
+func (r *Relayer) GetHubEndpoints() []HubEndpointStatus {
+	return r.HubChain.GetEndpoints()
+}
+
+// GetChainStatus gets the status and the active node of the specified app chain
+func (r *Relayer) GetChainStatus(chainID string) (state bool, height int64, node string, err error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
 	state, ok := r.AppChainStates[chainID]
 	if !ok {
-		return state, height, fmt.Errorf("chain ID %s does not exist", chainID)
+		return state, height, node, fmt.Errorf("chain ID %s does not exist", chainID)
 	}
 
 	height = r.AppChains[chainID].GetHeight()
+	node = r.AppChains[chainID].GetActiveNode()
+
+	return state, height, node, nil
+}
+
+// GetDeadLetters retrieves the responses failed to be delivered after the maximum attempts
+func (r *Relayer) GetDeadLetters() ([]*ResponseEntry, error) {
+	return r.ResponseQueue.DeadLetters()
+}
 
-	return state, height, nil
+// RedriveDeadLetter requeues the dead lettered response of the specified request
+func (r *Relayer) RedriveDeadLetter(chainID string, requestID string) error {
+	return r.ResponseQueue.Redrive(chainID, requestID)
 }
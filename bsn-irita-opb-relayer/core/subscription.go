@@ -0,0 +1,73 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"relayer/store"
+)
+
+const (
+	KeyPrefixSubscription = "subscription:response"
+)
+
+// Subscription defines the outstanding Hub request waiting for the response
+type Subscription struct {
+	ReqCtxID      string `json:"req_ctx_id"`
+	HubRequestID  string `json:"hub_request_id"`
+	SourceChainID string `json:"source_chain_id"`
+	RequestID     string `json:"request_id"`
+}
+
+// SubscriptionStore persists the outstanding Hub requests to recover the response subscriptions
+type SubscriptionStore struct {
+	store *store.Store
+}
+
+// NewSubscriptionStore constructs a new SubscriptionStore instance
+func NewSubscriptionStore(store *store.Store) *SubscriptionStore {
+	return &SubscriptionStore{
+		store: store,
+	}
+}
+
+// SubscriptionKey returns the key of the given Hub request
+func SubscriptionKey(hubRequestID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s", KeyPrefixSubscription, hubRequestID))
+}
+
+// Add persists the given subscription
+func (s *SubscriptionStore) Add(sub Subscription) error {
+	bz, err := json.Marshal(sub)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Set(SubscriptionKey(sub.HubRequestID), bz)
+}
+
+// Remove deletes the subscription of the given Hub request
+func (s *SubscriptionStore) Remove(hubRequestID string) error {
+	return s.store.Delete(SubscriptionKey(hubRequestID))
+}
+
+// List returns all the outstanding subscriptions
+func (s *SubscriptionStore) List() ([]Subscription, error) {
+	subs := make([]Subscription, 0)
+
+	err := s.store.Iterate([]byte(KeyPrefixSubscription+":"), func(key, value []byte) bool {
+		var sub Subscription
+		if err := json.Unmarshal(value, &sub); err != nil {
+			return true
+		}
+
+		subs = append(subs, sub)
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subs, nil
+}
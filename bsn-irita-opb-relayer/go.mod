@@ -6,17 +6,25 @@ require (
 	github.com/OneOfOne/xxhash v1.2.5 // indirect
 	github.com/bianjieai/iritamod-sdk-go v0.0.0-20211119065750-d3edd49ebbe1
 	github.com/cockroachdb/pebble v0.0.0-20201118202804-75ede898b66c
+	github.com/ethereum/go-ethereum v1.9.18
 	github.com/dvsekhvalnov/jose2go v1.5.0 // indirect
 	github.com/gin-gonic/gin v1.4.0
 	github.com/go-sql-driver/mysql v1.4.0
 	github.com/irisnet/core-sdk-go v0.0.0-20211118114422-2efa1178f1e2
+	github.com/lib/pq v1.10.0
+	github.com/mattn/go-sqlite3 v1.14.6
+	github.com/miekg/pkcs11 v1.0.3
+	github.com/pborman/uuid v1.2.0
 	github.com/pelletier/go-toml v1.6.0 // indirect
+	github.com/prometheus/client_golang v1.8.0
 	github.com/sirupsen/logrus v1.6.0
 	github.com/spf13/afero v1.2.2 // indirect
 	github.com/spf13/cobra v1.1.1
 	github.com/spf13/jwalterweatherman v1.1.0 // indirect
 	github.com/spf13/viper v1.7.1
 	github.com/tendermint/tendermint v0.34.14
+	google.golang.org/grpc v1.40.0
+	relayer/appchains/adapter/adapterpb v0.0.0
 )
 
 replace (
@@ -24,4 +32,5 @@ replace (
 	github.com/keybase/go-keychain => github.com/99designs/go-keychain v0.0.0-20191008050251-8e49817e8af4
 	github.com/tendermint/tendermint => github.com/bianjieai/tendermint v0.34.1-irita-210113
 	github.com/ugorji/go => github.com/ugorji/go v1.1.2
+	relayer/appchains/adapter/adapterpb => ../bsn-irita-appchains/adapter/adapterpb
 )
@@ -1,7 +1,6 @@
 package hub
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/bianjieai/iritamod-sdk-go/service"
@@ -12,7 +11,6 @@ import (
 	"relayer/common"
 	"relayer/core"
 	"relayer/logging"
-	"time"
 )
 
 type ServiceInfo struct {
@@ -20,26 +18,26 @@ type ServiceInfo struct {
 	Schemas     string
 	Provider    string
 	ServiceFee  string
+	Timeout     int64
 	QoS         uint64
 }
 
 // IritaHubChain defines the Irita-Hub chain
 type IritaHubChain struct {
-	ChainID     string
-	NodeRPCAddr string
+	ChainID   string
+	Endpoints *EndpointPool // service clients of the Hub endpoints
 
 	KeyName    string
 	Passphrase string
 
 	ServiceInfo ServiceInfo
-	IritaClient *ServiceClient
+	Dispatcher  *ResponseDispatcher
 }
 
 // NewIritaHubChain constructs a new Irita-Hub chain
 func NewIritaHubChain(
 	chainID string,
-	nodeRPCAddr string,
-	nodeGRPCAddr string,
+	endpoints []Endpoint,
 	keyMode string,
 	keyPath string,
 	keyName string,
@@ -57,12 +55,18 @@ func NewIritaHubChain(
 		chainID = defaultChainID
 	}
 
-	if len(nodeRPCAddr) == 0 {
-		nodeRPCAddr = defaultNodeRPCAddr
+	if len(endpoints) == 0 {
+		endpoints = []Endpoint{{}}
 	}
 
-	if len(nodeGRPCAddr) == 0 {
-		nodeGRPCAddr = defaultNodeGRPCAddr
+	for i := range endpoints {
+		if len(endpoints[i].RPCAddr) == 0 {
+			endpoints[i].RPCAddr = defaultNodeRPCAddr
+		}
+
+		if len(endpoints[i].GRPCAddr) == 0 {
+			endpoints[i].GRPCAddr = defaultNodeGRPCAddr
+		}
 	}
 
 	if len(serviceName) == 0 {
@@ -104,32 +108,44 @@ func NewIritaHubChain(
 		keyDAO = storetypes.NewFileDAO(keyPath)
 	}
 
-	config, err := sdk.NewClientConfig(
-		nodeRPCAddr,
-		nodeGRPCAddr,
-		chainID,
-		sdk.FeeOption(fee),
-		sdk.GasOption(defaultGas),
-		sdk.ModeOption(defaultBroadcastMode),
-		sdk.AlgoOption(defaultKeyAlgorithm),
-		sdk.KeyDAOOption(keyDAO),
-		sdk.TimeoutOption(5),
-	)
+	// the clients of all the endpoints share the key DAO
+	buildClient := func(endpoint Endpoint) *ServiceClient {
+		config, err := sdk.NewClientConfig(
+			endpoint.RPCAddr,
+			endpoint.GRPCAddr,
+			chainID,
+			sdk.FeeOption(fee),
+			sdk.GasOption(defaultGas),
+			sdk.ModeOption(defaultBroadcastMode),
+			sdk.AlgoOption(defaultKeyAlgorithm),
+			sdk.KeyDAOOption(keyDAO),
+			sdk.TimeoutOption(5),
+		)
+		if err != nil {
+			panic(err)
+		}
+
+		return NewServiceClient(config)
+	}
+
 	hub := IritaHubChain{
-		ChainID:     chainID,
-		NodeRPCAddr: nodeRPCAddr,
-		KeyName:     keyName,
-		Passphrase:  passphrase,
+		ChainID:    chainID,
+		Endpoints:  NewEndpointPool(chainID, endpoints, buildClient),
+		KeyName:    keyName,
+		Passphrase: passphrase,
 		ServiceInfo: ServiceInfo{
 			ServiceName: serviceName,
 			Schemas:     schemas,
 			Provider:    provider,
 			ServiceFee:  serviceFee,
+			Timeout:     int64(timeout),
 			QoS:         qos,
 		},
-		IritaClient: NewServiceClient(config),
 	}
 
+	hub.Dispatcher = NewResponseDispatcher(chainID, hub.Endpoints.Client())
+	hub.Endpoints.OnSwitch(hub.Dispatcher.SetServiceClient)
+
 	// import key
 	if keyMode == "mem" {
 		log.WithField("keyName", keyName).Info("use memory key dao, importing key...")
@@ -143,11 +159,16 @@ func NewIritaHubChain(
 }
 
 // BuildIritaHubChain builds an Irita-Hub instance from the given config
+// The single node address is used unless the endpoints are specified
 func BuildIritaHubChain(config Config) IritaHubChain {
+	endpoints := config.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = []Endpoint{{RPCAddr: config.NodeRPCAddr, GRPCAddr: config.NodeGRPCAddr}}
+	}
+
 	return NewIritaHubChain(
 		config.ChainID,
-		config.NodeRPCAddr,
-		config.NodeGRPCAddr,
+		endpoints,
 		config.KeyMode,
 		config.KeyPath,
 		config.KeyName,
@@ -168,6 +189,11 @@ func (ic IritaHubChain) GetChainID() string {
 	return ic.ChainID
 }
 
+// GetEndpoints implements IritaHubChainI
+func (ic IritaHubChain) GetEndpoints() []core.HubEndpointStatus {
+	return ic.Endpoints.Endpoints()
+}
+
 // SendInterchainRequest implements IritaHubChainI
 func (ic IritaHubChain) SendInterchainRequest(
 	request core.InterchainRequest,
@@ -181,16 +207,22 @@ func (ic IritaHubChain) SendInterchainRequest(
 		return info, err
 	}
 
-	reqCtxID, resTx, err := ic.IritaClient.Service.InvokeService(invokeServiceReq, ic.BuildBaseTx())
+	endpoint := ic.Endpoints.ActiveEndpoint()
+	iritaClient := ic.Endpoints.Client()
+
+	reqCtxID, resTx, err := iritaClient.Service.InvokeService(invokeServiceReq, ic.BuildBaseTx())
 	if err != nil {
+		ic.Endpoints.ReportError(endpoint, err)
 		//mysql.TxErrCollection(request.ID, err.Error())
 		return info, err
 	}
 	info.HubReqTxId = resTx.Hash.String()
+	info.ReqCtxId = reqCtxID
 	logging.Logger.Infof("request context created on %s: %s", ic.ChainID, reqCtxID)
 
-	requests, err := ic.IritaClient.Service.QueryRequestsByReqCtx(reqCtxID, 1, nil)
+	requests, err := iritaClient.Service.QueryRequestsByReqCtx(reqCtxID, 1, nil)
 	if err != nil {
+		ic.Endpoints.ReportError(endpoint, err)
 		return info, err
 	}
 
@@ -214,6 +246,11 @@ func (ic IritaHubChain) BuildServiceInvocationRequest(
 	serviceFeeCap, err := types.ParseDecCoins(ic.ServiceInfo.ServiceFee)
 	destID := common.GetDestID(request.DestChainType, request.DestSubChainID, request.DestChainID)
 
+	timeout := ic.ServiceInfo.Timeout
+	if request.Timeout > 0 {
+		timeout = request.Timeout
+	}
+
 	input := ServiceInput{
 		Header: Header{
 			ReqSequence: request.ID,
@@ -246,56 +283,29 @@ func (ic IritaHubChain) BuildServiceInvocationRequest(
 		ServiceName:   ic.ServiceInfo.ServiceName,
 		Providers:     []string{ic.ServiceInfo.Provider},
 		Input:         string(serviceInput),
-		Timeout:       100,
+		Timeout:       timeout,
 		ServiceFeeCap: serviceFeeCap,
 	}, nil
 }
 
-// ResponseListener gets and handles the response of the given request context ID by event subscription
+// ResponseListener gets and handles the response of the given request by the response dispatcher
 func (ic IritaHubChain) ResponseListener(reqCtxID string, requestID string, cb core.ResponseCallback) error {
-	response, err := ic.IritaClient.Service.QueryServiceResponse(requestID)
-	if response.RequestContextID == reqCtxID {
-		resp := core.ResponseAdaptor{
-			StatusCode: 200,
-			Result:     response.Result,
-			Output:     response.Output,
-		}
-
-		cb(requestID, resp)
+	logging.Logger.Infof("waiting for the service response on %s", ic.ChainID)
 
-		return nil
+	if err := ic.Dispatcher.Register(reqCtxID, requestID, cb); err != nil {
+		return err
 	}
 
-	callbackWrapper := func(reqCtxID, requestID, response string) {
-		resp := core.ResponseAdaptor{
+	// the response may be available before the request is registered
+	response, err := ic.Endpoints.Client().Service.QueryServiceResponse(requestID)
+	if err == nil && response.RequestContextID == reqCtxID {
+		ic.Dispatcher.Dispatch(requestID, core.ResponseAdaptor{
 			StatusCode: 200,
-			//Result:     result,
-			Output: response,
-		}
-
-		cb(requestID, resp)
-	}
-
-	logging.Logger.Infof("waiting for the service response on %s", ic.ChainID)
-
-	subscription, err := ic.IritaClient.Service.SubscribeServiceResponse(reqCtxID, callbackWrapper)
-	if err != nil {
-		return err
+			Result:     response.Result,
+			Output:     response.Output,
+		})
 	}
 
-	go func() {
-		for {
-			reqCtx, err := ic.IritaClient.Service.QueryRequestContext(reqCtxID)
-			status, err2 := ic.IritaClient.Status(context.Background())
-			req, err3 := ic.IritaClient.Service.QueryServiceRequest(requestID)
-			if err != nil || err2 != nil || err3 != nil || reqCtx.BatchState == "BATCH_COMPLETED" || status.SyncInfo.LatestBlockHeight > req.ExpirationHeight {
-				logging.Logger.Infof("HUB Unsubscribe RequestID is %s", requestID)
-				_ = ic.IritaClient.Unsubscribe(subscription)
-				break
-			}
-			time.Sleep(time.Second)
-		}
-	}()
 	return nil
 }
 
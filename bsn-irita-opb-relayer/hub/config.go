@@ -6,6 +6,7 @@ import (
 	"github.com/irisnet/core-sdk-go/types"
 
 	cfg "relayer/config"
+	"relayer/logging"
 )
 
 // default config variables
@@ -31,6 +32,7 @@ const (
 	ChainID       = "chain_id"
 	NodeRPCAddr   = "node_rpc_addr"
 	NodeGRPCAddr  = "node_grpc_addr"
+	Endpoints     = "endpoints"
 	KeyMode       = "key_mode"
 	KeyPath       = "key_path"
 	KeyName       = "key_name"
@@ -48,15 +50,16 @@ const (
 // Config is a config struct for IRITA-HUB
 type Config struct {
 	// chain cfg -> "hub.*"
-	ChainID      string `yaml:"chain_id"`
-	NodeRPCAddr  string `yaml:"node_rpc_addr"`
-	NodeGRPCAddr string `yaml:"node_grpc_addr"`
-	KeyMode      string `yaml:"key_mode"`
-	KeyPath      string `yaml:"key_path"`
-	KeyName      string `yaml:"key_name"`
-	Passphrase   string `yaml:"passphrase"`
-	KeyArmor     string `yaml:"key_armor" mapstructure:"key_armor"`
-	Fee          string `yaml:"fee"`
+	ChainID      string     `yaml:"chain_id"`
+	NodeRPCAddr  string     `yaml:"node_rpc_addr"`
+	NodeGRPCAddr string     `yaml:"node_grpc_addr"`
+	Endpoints    []Endpoint `yaml:"endpoints"` // RPC/gRPC address pairs to fail over among, in place of the single node
+	KeyMode      string     `yaml:"key_mode"`
+	KeyPath      string     `yaml:"key_path"`
+	KeyName      string     `yaml:"key_name"`
+	Passphrase   string     `yaml:"passphrase"`
+	KeyArmor     string     `yaml:"key_armor" mapstructure:"key_armor"`
+	Fee          string     `yaml:"fee"`
 
 	// service cfg -> "service.*"
 	ServiceName string `yaml:"service_name"` // service name
@@ -69,10 +72,16 @@ type Config struct {
 
 // NewConfig constructs a new Config from viper
 func NewConfig(v *viper.Viper) Config {
+	var endpoints []Endpoint
+	if err := v.UnmarshalKey(cfg.GetConfigKey(Prefix, Endpoints), &endpoints); err != nil {
+		logging.Logger.Errorf("failed to parse the hub endpoints: %s", err)
+	}
+
 	return Config{
 		ChainID:      v.GetString(cfg.GetConfigKey(Prefix, ChainID)),
 		NodeRPCAddr:  v.GetString(cfg.GetConfigKey(Prefix, NodeRPCAddr)),
 		NodeGRPCAddr: v.GetString(cfg.GetConfigKey(Prefix, NodeGRPCAddr)),
+		Endpoints:    endpoints,
 		KeyMode:      v.GetString(cfg.GetConfigKey(Prefix, KeyMode)),
 		KeyPath:      v.GetString(cfg.GetConfigKey(Prefix, KeyPath)),
 		KeyName:      v.GetString(cfg.GetConfigKey(Prefix, KeyName)),
@@ -0,0 +1,311 @@
+package hub
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bianjieai/iritamod-sdk-go/service"
+	"github.com/irisnet/core-sdk-go/types"
+
+	"relayer/core"
+	"relayer/logging"
+)
+
+const (
+	msgTypeRespondService = "respond_service" // action of the service response msg
+
+	DefaultSubscriptionTimeout       = 30 * time.Second // maximum interval without new block headers before resubscribing
+	DefaultSubscriptionCheckInterval = 5 * time.Second  // interval to check the subscriptions
+)
+
+// pendingRequest defines the service request waiting for the response
+type pendingRequest struct {
+	reqCtxID         string
+	requestID        string
+	expirationHeight int64
+	cb               core.ResponseCallback
+}
+
+// ResponseDispatcher routes the service responses on the Hub to the callbacks of the pending requests
+// The responses and new blocks are subscribed only once for all the requests
+// The subscriptions are watched and resubscribed once no new block header arrives within the timeout
+type ResponseDispatcher struct {
+	chainID     string
+	iritaClient *ServiceClient
+	timeout     time.Duration
+
+	pending    map[string]*pendingRequest
+	subscribed bool
+	txSub      types.Subscription
+	blockSub   types.Subscription
+	lastHeader time.Time // time of the last new block header received
+	attempts   int       // failed attempts to resubscribe
+	nextRetry  time.Time // time of the next attempt to resubscribe
+	watchOnce  sync.Once
+	mtx        sync.Mutex
+}
+
+// NewResponseDispatcher constructs a new ResponseDispatcher instance
+func NewResponseDispatcher(chainID string, iritaClient *ServiceClient) *ResponseDispatcher {
+	return &ResponseDispatcher{
+		chainID:     chainID,
+		iritaClient: iritaClient,
+		timeout:     DefaultSubscriptionTimeout,
+		pending:     map[string]*pendingRequest{},
+	}
+}
+
+// Register tracks the given request until it is responded or expires
+func (d *ResponseDispatcher) Register(reqCtxID string, requestID string, cb core.ResponseCallback) error {
+	if err := d.subscribe(); err != nil {
+		return err
+	}
+
+	d.watchOnce.Do(func() {
+		go d.watch()
+	})
+
+	request := &pendingRequest{
+		reqCtxID:  reqCtxID,
+		requestID: requestID,
+		cb:        cb,
+	}
+
+	if req, err := d.client().Service.QueryServiceRequest(requestID); err == nil {
+		request.expirationHeight = req.ExpirationHeight
+	}
+
+	d.mtx.Lock()
+	d.pending[strings.ToUpper(requestID)] = request
+	d.mtx.Unlock()
+
+	return nil
+}
+
+// Dispatch hands the response over to the callback of the given request if it is pending
+func (d *ResponseDispatcher) Dispatch(requestID string, response core.ResponseAdaptor) {
+	request := d.remove(requestID)
+	if request == nil {
+		return
+	}
+
+	request.cb(request.requestID, response)
+}
+
+// Pending returns the number of the pending requests
+func (d *ResponseDispatcher) Pending() int {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	return len(d.pending)
+}
+
+// SetServiceClient switches the dispatcher to the given client
+// The subscriptions are moved to the new client if subscribed
+func (d *ResponseDispatcher) SetServiceClient(iritaClient *ServiceClient) {
+	d.mtx.Lock()
+
+	if d.subscribed {
+		_ = d.iritaClient.Unsubscribe(d.txSub)
+		_ = d.iritaClient.Unsubscribe(d.blockSub)
+	}
+
+	resubscribe := d.subscribed
+	d.iritaClient = iritaClient
+	d.subscribed = false
+
+	d.mtx.Unlock()
+
+	if resubscribe {
+		if err := d.subscribe(); err != nil {
+			logging.Logger.Errorf("failed to resubscribe to the service responses on %s: %s", d.chainID, err)
+		}
+	}
+}
+
+// subscribe subscribes the service responses and new blocks if not subscribed
+func (d *ResponseDispatcher) subscribe() error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if d.subscribed {
+		return nil
+	}
+
+	builder := types.NewEventQueryBuilder().AddCondition(
+		types.NewCond(types.EventTypeMessage, "action").EQ(types.EventValue(msgTypeRespondService)),
+	)
+
+	txSub, err := d.iritaClient.SubscribeTx(builder, d.onTx)
+	if err != nil {
+		return err
+	}
+
+	blockSub, err := d.iritaClient.SubscribeNewBlockHeader(d.onNewBlockHeader)
+	if err != nil {
+		_ = d.iritaClient.Unsubscribe(txSub)
+		return err
+	}
+
+	d.txSub = txSub
+	d.blockSub = blockSub
+	d.subscribed = true
+	d.lastHeader = time.Now()
+
+	logging.Logger.Infof("subscribed to the service responses on %s", d.chainID)
+
+	return nil
+}
+
+// watch checks the subscriptions periodically
+func (d *ResponseDispatcher) watch() {
+	ticker := time.NewTicker(DefaultSubscriptionCheckInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		d.checkSubscription(now)
+	}
+}
+
+// checkSubscription resubscribes if the subscriptions are lost or no new block header arrives within the timeout
+// The failed resubscriptions are retried with exponential backoff
+func (d *ResponseDispatcher) checkSubscription(now time.Time) {
+	d.mtx.Lock()
+
+	stale := d.subscribed && now.Sub(d.lastHeader) > d.timeout
+	if (d.subscribed && !stale) || now.Before(d.nextRetry) {
+		d.mtx.Unlock()
+		return
+	}
+
+	if stale {
+		logging.Logger.Warnf("no new block header on %s for %s, resubscribing", d.chainID, now.Sub(d.lastHeader))
+
+		_ = d.iritaClient.Unsubscribe(d.txSub)
+		_ = d.iritaClient.Unsubscribe(d.blockSub)
+		d.subscribed = false
+	}
+
+	d.mtx.Unlock()
+
+	err := d.subscribe()
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if err != nil {
+		d.attempts++
+		d.nextRetry = now.Add(core.Backoff(d.attempts))
+
+		logging.Logger.Errorf("failed to resubscribe to the service responses on %s, retrying in %s: %s", d.chainID, core.Backoff(d.attempts), err)
+
+		return
+	}
+
+	d.attempts = 0
+	d.nextRetry = time.Time{}
+}
+
+// onTx dispatches the responses in the given tx
+func (d *ResponseDispatcher) onTx(tx types.EventDataTx) {
+	for _, msg := range tx.Tx.GetMsgs() {
+		msg, ok := msg.(*service.MsgRespondService)
+		if !ok {
+			continue
+		}
+
+		d.Dispatch(msg.RequestId, core.ResponseAdaptor{
+			StatusCode: 200,
+			Result:     msg.Result,
+			Output:     msg.Output,
+		})
+	}
+}
+
+// onNewBlockHeader expires the pending requests beyond the expiration height
+func (d *ResponseDispatcher) onNewBlockHeader(header types.EventDataNewBlockHeader) {
+	height := header.Header.Height
+
+	expired := make([]*pendingRequest, 0)
+
+	d.mtx.Lock()
+	d.lastHeader = time.Now()
+	for key, request := range d.pending {
+		if request.expirationHeight > 0 && height > request.expirationHeight {
+			expired = append(expired, request)
+			delete(d.pending, key)
+		}
+	}
+	d.mtx.Unlock()
+
+	for _, request := range expired {
+		d.expire(request)
+	}
+
+	d.refreshExpiration()
+}
+
+// expire sends the timeout response for the given request unless the response is found
+func (d *ResponseDispatcher) expire(request *pendingRequest) {
+	response, err := d.client().Service.QueryServiceResponse(request.requestID)
+	if err == nil && response.RequestContextID == request.reqCtxID {
+		request.cb(request.requestID, core.ResponseAdaptor{
+			StatusCode: 200,
+			Result:     response.Result,
+			Output:     response.Output,
+		})
+
+		return
+	}
+
+	logging.Logger.Warnf("service request %s expired on %s without response", request.requestID, d.chainID)
+
+	request.cb(request.requestID, core.NewTimeoutResponse())
+}
+
+// refreshExpiration retrieves the expiration height of the requests failed to be queried on registration
+func (d *ResponseDispatcher) refreshExpiration() {
+	d.mtx.Lock()
+	unknown := make([]*pendingRequest, 0)
+	for _, request := range d.pending {
+		if request.expirationHeight == 0 {
+			unknown = append(unknown, request)
+		}
+	}
+	d.mtx.Unlock()
+
+	for _, request := range unknown {
+		req, err := d.client().Service.QueryServiceRequest(request.requestID)
+		if err != nil {
+			continue
+		}
+
+		d.mtx.Lock()
+		request.expirationHeight = req.ExpirationHeight
+		d.mtx.Unlock()
+	}
+}
+
+func (d *ResponseDispatcher) client() *ServiceClient {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	return d.iritaClient
+}
+
+func (d *ResponseDispatcher) remove(requestID string) *pendingRequest {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	key := strings.ToUpper(requestID)
+
+	request, ok := d.pending[key]
+	if !ok {
+		return nil
+	}
+
+	delete(d.pending, key)
+
+	return request
+}
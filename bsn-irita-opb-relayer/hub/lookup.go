@@ -0,0 +1,133 @@
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/irisnet/core-sdk-go/types"
+
+	"relayer/core"
+)
+
+const (
+	actionCallService = "call_service"
+
+	eventTypeCreateContext   = "create_context"
+	eventTypeResponseService = "respond_service"
+
+	attributeKeySender           = "sender"
+	attributeKeyRequestContextID = "request_context_id"
+	attributeKeyRequestID        = "request_id"
+
+	lookupPageSize = 50 // number of the txs per page to search
+	lookupMaxPages = 20 // maximum pages to search back
+)
+
+// FindInterchainRequest implements HubChainI
+// The call_service txs of the relayer are searched back from the latest one to the given time,
+// for the request context whose input carries the sequence of the given request
+func (ic IritaHubChain) FindInterchainRequest(request core.InterchainRequest, since time.Time) (core.InterchainRequestInfo, error) {
+	info := core.InterchainRequestInfo{}
+
+	consumer, err := ic.ShowKey(ic.KeyName, ic.Passphrase)
+	if err != nil {
+		return info, fmt.Errorf("failed to get the key address: %s", err)
+	}
+
+	client := ic.Endpoints.Client()
+
+	builder := types.NewEventQueryBuilder().
+		AddCondition(types.NewCond(types.EventTypeMessage, "action").EQ(types.EventValue(actionCallService))).
+		AddCondition(types.NewCond(types.EventTypeMessage, attributeKeySender).EQ(types.EventValue(consumer)))
+
+	page, size := 1, lookupPageSize
+
+	res, err := client.QueryTxs(builder, &page, &size)
+	if err != nil {
+		return info, fmt.Errorf("failed to search the service calls on %s: %s", ic.ChainID, err)
+	}
+
+	pages := (res.Total + lookupPageSize - 1) / lookupPageSize
+
+	for page = pages; page >= 1 && page > pages-lookupMaxPages; page-- {
+		if page > 1 {
+			res, err = client.QueryTxs(builder, &page, &size)
+			if err != nil {
+				return info, fmt.Errorf("failed to search the service calls on %s: %s", ic.ChainID, err)
+			}
+		}
+
+		for i := len(res.Txs) - 1; i >= 0; i-- {
+			tx := res.Txs[i]
+
+			if txTime, err := time.Parse(time.RFC3339, tx.Timestamp); err == nil && txTime.Before(since) {
+				return info, fmt.Errorf("service request of interchain request %s not found on %s", request.ID, ic.ChainID)
+			}
+
+			reqCtxID, err := tx.TxResult.Events.GetValue(eventTypeCreateContext, attributeKeyRequestContextID)
+			if err != nil {
+				continue
+			}
+
+			reqCtx, err := client.Service.QueryRequestContext(reqCtxID)
+			if err != nil || !MatchServiceInput(reqCtx.Input, request) {
+				continue
+			}
+
+			info.HubReqTxId = tx.Hash
+			info.ReqCtxId = reqCtxID
+
+			info.IcRequestId, err = ic.findRequestID(reqCtxID)
+			if err != nil {
+				return info, err
+			}
+
+			return info, nil
+		}
+
+		if page == 1 {
+			break
+		}
+	}
+
+	return info, fmt.Errorf("service request of interchain request %s not found on %s", request.ID, ic.ChainID)
+}
+
+// findRequestID retrieves the ID of the service request initiated by the given request context
+// The request which has been responded is found by the response tx
+func (ic IritaHubChain) findRequestID(reqCtxID string) (string, error) {
+	client := ic.Endpoints.Client()
+
+	requests, qErr := client.Service.QueryRequestsByReqCtx(reqCtxID, 1, nil)
+	if qErr == nil && len(requests) > 0 {
+		return requests[0].ID, nil
+	}
+
+	builder := types.NewEventQueryBuilder().AddCondition(
+		types.NewCond(eventTypeResponseService, attributeKeyRequestContextID).EQ(types.EventValue(reqCtxID)),
+	)
+
+	page, size := 1, 1
+
+	res, err := client.QueryTxs(builder, &page, &size)
+	if err != nil {
+		return "", fmt.Errorf("failed to search the response of request context %s: %s", reqCtxID, err)
+	}
+
+	if len(res.Txs) == 0 {
+		return "", fmt.Errorf("no service request of request context %s found on %s", reqCtxID, ic.ChainID)
+	}
+
+	return res.Txs[0].TxResult.Events.GetValue(eventTypeResponseService, attributeKeyRequestID)
+}
+
+// MatchServiceInput returns true if the given service input is built from the given request
+func MatchServiceInput(input string, request core.InterchainRequest) bool {
+	var serviceInput ServiceInput
+	if err := json.Unmarshal([]byte(input), &serviceInput); err != nil {
+		return false
+	}
+
+	return serviceInput.Header.ReqSequence == request.ID && serviceInput.Header.ChainID == request.SourceChainID
+}
@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	Namespace = "relayer"
+
+	labelChainID = "chain_id"
+	labelResult  = "result"
+
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+var (
+	requestsDetected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "requests_detected_total",
+			Help:      "Number of the interchain requests detected on the app chain",
+		},
+		[]string{labelChainID},
+	)
+
+	hubSubmissions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "hub_submissions_total",
+			Help:      "Number of the interchain requests submitted to the Hub by result",
+		},
+		[]string{labelChainID, labelResult},
+	)
+
+	responsesDelivered = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "responses_delivered_total",
+			Help:      "Number of the responses delivered to the app chain by result",
+		},
+		[]string{labelChainID, labelResult},
+	)
+
+	relayLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "relay_latency_seconds",
+			Help:      "Latency from the source tx to the receipt of the response tx",
+			Buckets:   []float64{5, 10, 30, 60, 120, 300, 600, 1800, 3600},
+		},
+		[]string{labelChainID},
+	)
+
+	scannedHeight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "scanned_height",
+			Help:      "Height scanned on the app chain",
+		},
+		[]string{labelChainID},
+	)
+
+	headHeight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "head_height",
+			Help:      "Latest height of the app chain",
+		},
+		[]string{labelChainID},
+	)
+
+	pendingRequests = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "pending_requests",
+			Help:      "Number of the Hub requests waiting for the response",
+		},
+		[]string{labelChainID},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		requestsDetected,
+		hubSubmissions,
+		responsesDelivered,
+		relayLatency,
+		scannedHeight,
+		headHeight,
+		pendingRequests,
+	)
+}
+
+// Handler returns the HTTP handler exposing the metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RequestDetected counts the interchain request detected on the given chain
+func RequestDetected(chainID string) {
+	requestsDetected.WithLabelValues(chainID).Inc()
+}
+
+// HubSubmitted counts the submission of the request from the given chain to the Hub
+func HubSubmitted(chainID string, err error) {
+	hubSubmissions.WithLabelValues(chainID, result(err)).Inc()
+}
+
+// ResponseDelivered counts the delivery of the response to the given chain
+func ResponseDelivered(chainID string, err error) {
+	responsesDelivered.WithLabelValues(chainID, result(err)).Inc()
+}
+
+// ObserveRelayLatency observes the latency since the given source time
+func ObserveRelayLatency(chainID string, since time.Time) {
+	relayLatency.WithLabelValues(chainID).Observe(time.Since(since).Seconds())
+}
+
+// SetScannedHeight sets the height scanned on the given chain
+func SetScannedHeight(chainID string, height int64) {
+	scannedHeight.WithLabelValues(chainID).Set(float64(height))
+}
+
+// SetHeadHeight sets the latest height of the given chain
+func SetHeadHeight(chainID string, height int64) {
+	headHeight.WithLabelValues(chainID).Set(float64(height))
+}
+
+// AddPendingRequests adds the given delta to the pending requests of the given chain
+func AddPendingRequests(chainID string, delta int) {
+	pendingRequests.WithLabelValues(chainID).Add(float64(delta))
+}
+
+// RemoveChain removes the metrics of the given chain
+func RemoveChain(chainID string) {
+	scannedHeight.DeleteLabelValues(chainID)
+	headHeight.DeleteLabelValues(chainID)
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultFailure
+	}
+
+	return resultSuccess
+}
@@ -0,0 +1,291 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	EnvMasterKey     = "RELAYER_MASTER_KEY"      // env holding the master key
+	EnvMasterKeyFile = "RELAYER_MASTER_KEY_FILE" // env holding the path of the master key file
+
+	EncryptedPrefix       = "enc:"        // prefix of the encrypted values
+	EncryptedScryptPrefix = "enc:scrypt:" // prefix of the values encrypted with the key derived by scrypt
+
+	RawKeySize = 32 // size of the raw AES-256 key material, which is used as the key as is
+
+	scryptN       = 1 << 15 // scrypt CPU/memory cost
+	scryptR       = 8       // scrypt block size
+	scryptP       = 1       // scrypt parallelization
+	scryptSaltLen = 16      // length of the salt stored in the encrypted value
+)
+
+// derivedKeys caches the keys derived by scrypt by the material and salt
+var derivedKeys sync.Map
+
+// ErrNoMasterKey is returned when the master key is not configured
+var ErrNoMasterKey = errors.New("master key not configured, set " + EnvMasterKey + " or " + EnvMasterKeyFile)
+
+// SensitiveKeys defines the config fields which hold secrets
+var SensitiveKeys = []string{
+	"passphrase",
+	"password",
+	"key",
+	"key_armor",
+	"db_user_passphrase",
+	"dsn",
+	"pin",
+	"token",
+}
+
+// LoadMasterKey loads the master key material from the env or the file specified by the env
+// A raw 32-byte material is used as the AES-256 key as is, otherwise the key is derived from
+// the material with scrypt and the random salt stored in each encrypted value
+func LoadMasterKey() ([]byte, error) {
+	material := os.Getenv(EnvMasterKey)
+
+	if len(material) == 0 {
+		keyFile := os.Getenv(EnvMasterKeyFile)
+		if len(keyFile) == 0 {
+			return nil, ErrNoMasterKey
+		}
+
+		bz, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the master key file: %s", err)
+		}
+
+		material = strings.TrimSpace(string(bz))
+	}
+
+	if len(material) == 0 {
+		return nil, ErrNoMasterKey
+	}
+
+	return []byte(material), nil
+}
+
+// IsEncrypted returns true if the given value is encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EncryptedPrefix)
+}
+
+// IsSensitive returns true if the given config key holds a secret
+// The last segment of the dotted key is matched
+func IsSensitive(key string) bool {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		key = key[i+1:]
+	}
+
+	for _, k := range SensitiveKeys {
+		if strings.EqualFold(key, k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Encrypt encrypts the given plaintext with AES-GCM and returns the prefixed base64 value
+// The key is derived with scrypt unless the master key is a raw 32-byte key, in which case the salt is prepended to the value
+func Encrypt(masterKey []byte, plaintext string) (string, error) {
+	prefix := EncryptedPrefix
+	key := masterKey
+	var salt []byte
+
+	if len(masterKey) != RawKeySize {
+		salt = make([]byte, scryptSaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+
+		var err error
+		if key, err = deriveKey(masterKey, salt); err != nil {
+			return "", err
+		}
+
+		prefix = EncryptedScryptPrefix
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(append(salt, nonce...), nonce, []byte(plaintext), nil)
+
+	return prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts the given value produced by Encrypt
+// The value is returned as is if not encrypted
+func Decrypt(masterKey []byte, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	if strings.HasPrefix(value, EncryptedScryptPrefix) {
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedScryptPrefix))
+		if err != nil {
+			return "", fmt.Errorf("invalid encrypted value: %s", err)
+		}
+
+		if len(sealed) < scryptSaltLen {
+			return "", fmt.Errorf("invalid encrypted value")
+		}
+
+		key, err := deriveKey(masterKey, sealed[:scryptSaltLen])
+		if err != nil {
+			return "", err
+		}
+
+		return open(key, sealed[scryptSaltLen:])
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %s", err)
+	}
+
+	if len(masterKey) == RawKeySize {
+		if plaintext, err := open(masterKey, sealed); err == nil {
+			return plaintext, nil
+		}
+	}
+
+	// the values encrypted before the key derivation use the SHA256 digest of the material as the key
+	legacyKey := sha256.Sum256(masterKey)
+
+	return open(legacyKey[:], sealed)
+}
+
+// DecryptConfig decrypts the encrypted values of the given viper in place
+// The master key is only required if any value is encrypted
+func DecryptConfig(v *viper.Viper) error {
+	var masterKey []byte
+
+	for _, key := range v.AllKeys() {
+		value, ok := v.Get(key).(string)
+		if !ok || !IsEncrypted(value) {
+			continue
+		}
+
+		if masterKey == nil {
+			var err error
+			if masterKey, err = LoadMasterKey(); err != nil {
+				return err
+			}
+		}
+
+		plaintext, err := Decrypt(masterKey, value)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %s", key, err)
+		}
+
+		v.Set(key, plaintext)
+	}
+
+	return nil
+}
+
+// Seal encrypts the given bytes to be stored if the master key is configured
+// The bytes are returned as is otherwise
+func Seal(bz []byte) ([]byte, error) {
+	masterKey, err := LoadMasterKey()
+	if err == ErrNoMasterKey {
+		return bz, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := Encrypt(masterKey, string(bz))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(value), nil
+}
+
+// Open decrypts the bytes sealed by Seal
+// The bytes are returned as is if not encrypted
+func Open(bz []byte) ([]byte, error) {
+	if !IsEncrypted(string(bz)) {
+		return bz, nil
+	}
+
+	masterKey, err := LoadMasterKey()
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := Decrypt(masterKey, string(bz))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(plaintext), nil
+}
+
+// deriveKey derives the AES-256 key from the given material and salt with scrypt
+func deriveKey(material []byte, salt []byte) ([]byte, error) {
+	cacheKey := string(material) + ":" + string(salt)
+	if key, ok := derivedKeys.Load(cacheKey); ok {
+		return key.([]byte), nil
+	}
+
+	key, err := scrypt.Key(material, salt, scryptN, scryptR, scryptP, RawKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the master key: %s", err)
+	}
+
+	derivedKeys.Store(cacheKey, key)
+
+	return key, nil
+}
+
+// open decrypts the given nonce-prefixed sealed bytes with the given key
+func open(key []byte, sealed []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("invalid encrypted value")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the value, the master key may be wrong: %s", err)
+	}
+
+	return string(plaintext), nil
+}
+
+func newGCM(masterKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
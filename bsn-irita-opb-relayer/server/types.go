@@ -19,8 +19,9 @@ type AddChainResult struct {
 
 // ChainStatus defines the chain status
 type ChainStatus struct {
-	State  bool  `json:"state"`
-	Height int64 `json:"height,omitempty"`
+	State  bool   `json:"state"`
+	Height int64  `json:"height,omitempty"`
+	Node   string `json:"node,omitempty"` // node currently connected
 }
 
 // SuccessResponse defines the response on success
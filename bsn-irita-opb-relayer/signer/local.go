@@ -0,0 +1,63 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KeySigner signs with the private key in memory
+type KeySigner struct {
+	privKey *ecdsa.PrivateKey
+}
+
+// NewKeySigner constructs a new KeySigner instance
+func NewKeySigner(privKey *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{
+		privKey: privKey,
+	}
+}
+
+// NewHexKeySigner constructs a new KeySigner from the hex encoded private key
+func NewHexKeySigner(hexKey string) (*KeySigner, error) {
+	privKey, err := crypto.HexToECDSA(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key: %s", err)
+	}
+
+	return NewKeySigner(privKey), nil
+}
+
+// NewKeystoreSigner constructs a new KeySigner from the encrypted keystore file
+func NewKeystoreSigner(keystoreFile string, passphrase string) (*KeySigner, error) {
+	keyJSON, err := ioutil.ReadFile(keystoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the keystore file: %s", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the keystore file: %s", err)
+	}
+
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+// Address implements Signer
+func (s *KeySigner) Address() ethcmn.Address {
+	return crypto.PubkeyToAddress(s.privKey.PublicKey)
+}
+
+// PublicKey implements Signer
+func (s *KeySigner) PublicKey() *ecdsa.PublicKey {
+	return &s.privKey.PublicKey
+}
+
+// Sign implements Signer
+func (s *KeySigner) Sign(digest []byte) ([]byte, error) {
+	return crypto.Sign(digest, s.privKey)
+}
@@ -0,0 +1,309 @@
+package signer
+
+import (
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/miekg/pkcs11"
+	"github.com/pborman/uuid"
+)
+
+// envSoftHSMModule is the env var of the SoftHSM library path, the PKCS#11 tests are skipped if not set
+const envSoftHSMModule = "SOFTHSM2_MODULE"
+
+func TestKeystoreSigner(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := &keystore.Key{
+		Id:         uuid.NewRandom(),
+		Address:    crypto.PubkeyToAddress(privKey.PublicKey),
+		PrivateKey: privKey,
+	}
+
+	keyJSON, err := keystore.EncryptKey(key, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keystoreFile := filepath.Join(t.TempDir(), "key.json")
+	if err := ioutil.WriteFile(keystoreFile, keyJSON, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewSigner(Config{Backend: BackendKeystore, KeystoreFile: keystoreFile, Passphrase: "wrong"}); err == nil {
+		t.Fatal("expected the wrong passphrase to be rejected")
+	}
+
+	s, err := NewSigner(Config{Backend: BackendKeystore, KeystoreFile: keystoreFile, Passphrase: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Address() != key.Address {
+		t.Fatalf("unexpected address %s", s.Address().Hex())
+	}
+
+	digest := crypto.Keccak256([]byte("digest"))
+	sig, err := s.Sign(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey, err := crypto.SigToPub(digest, sig)
+	if err != nil || crypto.PubkeyToAddress(*pubKey) != key.Address {
+		t.Fatal("unexpected signer of the signature")
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/public_key":
+			if r.URL.Query().Get("key_id") != "relayer" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(remotePublicKeyResponse{
+				PublicKey: hex.EncodeToString(crypto.FromECDSAPub(&privKey.PublicKey)),
+			})
+
+		case "/sign":
+			var req remoteSignRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+
+			digest, _ := hex.DecodeString(req.Digest)
+			sig, _ := crypto.Sign(digest, privKey)
+
+			// return the [R || S] signature only
+			_ = json.NewEncoder(w).Encode(remoteSignResponse{Signature: hex.EncodeToString(sig[:64])})
+		}
+	}))
+	defer server.Close()
+
+	if _, err := NewRemoteSigner(server.URL, "relayer", "invalid"); err == nil {
+		t.Fatal("expected the unauthorized request to fail")
+	}
+
+	s, err := NewSigner(Config{Backend: BackendRemote, URL: server.URL, KeyID: "relayer", Token: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Address() != crypto.PubkeyToAddress(privKey.PublicKey) {
+		t.Fatalf("unexpected address %s", s.Address().Hex())
+	}
+
+	digest := crypto.Keccak256([]byte("digest"))
+	sig, err := s.Sign(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey, err := crypto.SigToPub(digest, sig)
+	if err != nil || crypto.PubkeyToAddress(*pubKey) != s.Address() {
+		t.Fatal("unexpected signer of the signature")
+	}
+}
+
+func TestPKCS11Signer(t *testing.T) {
+	library := os.Getenv(envSoftHSMModule)
+	if library == "" {
+		t.Skipf("%s not set", envSoftHSMModule)
+	}
+
+	// initialize a token in a temporary SoftHSM store
+	dir := t.TempDir()
+	conf := filepath.Join(dir, "softhsm2.conf")
+	tokenDir := filepath.Join(dir, "tokens")
+
+	if err := os.Mkdir(tokenDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(conf, []byte(fmt.Sprintf("directories.tokendir = %s\nobjectstore.backend = file\n", tokenDir)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	prevConf, hasConf := os.LookupEnv("SOFTHSM2_CONF")
+	_ = os.Setenv("SOFTHSM2_CONF", conf)
+	defer func() {
+		if hasConf {
+			_ = os.Setenv("SOFTHSM2_CONF", prevConf)
+		} else {
+			_ = os.Unsetenv("SOFTHSM2_CONF")
+		}
+	}()
+
+	if err := initSoftHSMToken(library, "relayer-test", "1234", "relayer"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewPKCS11Signer(library, "relayer-test", "1234", "unknown"); err == nil {
+		t.Fatal("expected the unknown key to be rejected")
+	}
+
+	s, err := NewSigner(Config{Backend: BackendPKCS11, Library: library, TokenLabel: "relayer-test", Pin: "1234", KeyLabel: "relayer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.(*PKCS11Signer).Close()
+
+	for i := 0; i < 10; i++ {
+		digest := crypto.Keccak256([]byte(fmt.Sprintf("digest%d", i)))
+
+		sig, err := s.Sign(digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pubKey, err := crypto.SigToPub(digest, sig)
+		if err != nil || crypto.PubkeyToAddress(*pubKey) != s.Address() {
+			t.Fatal("unexpected signer of the signature")
+		}
+
+		// the S value is normalized to the lower half
+		if new(big.Int).SetBytes(sig[32:64]).Cmp(new(big.Int).Rsh(crypto.S256().Params().N, 1)) > 0 {
+			t.Fatal("expected the S value to be normalized")
+		}
+	}
+}
+
+func TestToRecoverableHighS(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := crypto.Keccak256([]byte("digest"))
+	sig, err := crypto.Sign(digest, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// flip S to the upper half of the curve order as some HSMs produce
+	n := crypto.S256().Params().N
+	s := new(big.Int).Sub(n, new(big.Int).SetBytes(sig[32:64]))
+
+	highS := make([]byte, 64)
+	copy(highS, sig[:32])
+	s.FillBytes(highS[32:])
+
+	recoverable, err := ToRecoverable(digest, highS, &privKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(recoverable) != hex.EncodeToString(sig) {
+		t.Fatal("expected the normalized signature")
+	}
+
+	other, _ := crypto.GenerateKey()
+	if _, err := ToRecoverable(digest, sig[:64], &other.PublicKey); err == nil {
+		t.Fatal("expected the signature of another key to be rejected")
+	}
+
+	if _, err := NewSigner(Config{Backend: "unknown"}); err == nil {
+		t.Fatal("expected the unknown backend to be rejected")
+	}
+}
+
+// initSoftHSMToken initializes a SoftHSM token with the given label and pin, and generates a secp256k1 key pair on it
+func initSoftHSMToken(library string, tokenLabel string, pin string, keyLabel string) error {
+	ctx := pkcs11.New(library)
+	if ctx == nil {
+		return fmt.Errorf("failed to load the PKCS#11 library %s", library)
+	}
+	defer ctx.Destroy()
+
+	if err := ctx.Initialize(); err != nil {
+		return err
+	}
+	defer ctx.Finalize()
+
+	slots, err := ctx.GetSlotList(false)
+	if err != nil || len(slots) == 0 {
+		return fmt.Errorf("no free slot: %v", err)
+	}
+
+	if err := ctx.InitToken(slots[0], pin, tokenLabel); err != nil {
+		return err
+	}
+
+	// the initialized token is reassigned to another slot by SoftHSM
+	slot, err := findSlot(ctx, tokenLabel)
+	if err != nil {
+		return err
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		return err
+	}
+	defer ctx.CloseSession(session)
+
+	if err := ctx.Login(session, pkcs11.CKU_SO, pin); err != nil {
+		return err
+	}
+	if err := ctx.InitPIN(session, pin); err != nil {
+		return err
+	}
+	if err := ctx.Logout(session); err != nil {
+		return err
+	}
+
+	if err := ctx.Login(session, pkcs11.CKU_USER, pin); err != nil {
+		return err
+	}
+	defer ctx.Logout(session)
+
+	// OID of secp256k1
+	ecParams, err := asn1.Marshal(asn1.ObjectIdentifier{1, 3, 132, 0, 10})
+	if err != nil {
+		return err
+	}
+
+	_, _, err = ctx.GenerateKeyPair(
+		session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+			pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, ecParams),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, keyLabel),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+			pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, keyLabel),
+		},
+	)
+
+	return err
+}
@@ -12,8 +12,7 @@ var svcClient hub.IritaHubChain
 func init() {
 	svcClient = hub.NewIritaHubChain(
 		"wenchangchain",
-		"http://10.1.4.149:36657",
-		"10.1.4.149:39090",
+		[]hub.Endpoint{{RPCAddr: "http://10.1.4.149:36657", GRPCAddr: "10.1.4.149:39090"}},
 		"file",
 		".keys",
 		"node0",
@@ -30,7 +29,7 @@ func init() {
 }
 
 func TestExecuteAppContract(t *testing.T) {
-	resultTx, err := svcClient.IritaClient.WASM.Execute(
+	resultTx, err := svcClient.Endpoints.Client().WASM.Execute(
 		"iaa1ghd753shjuwexxywmgs4xz7x2q732vcnednxe6",
 		wasm.NewContractABI().WithMethod("hello").WithArgs("words", "test111"),
 		sdk.NewCoins(sdk.NewCoin("uirita", sdk.NewInt(1000000))),
@@ -47,7 +46,7 @@ func TestExecuteAppContract(t *testing.T) {
 }
 
 func TestQueryAppContractResult(t *testing.T) {
-	result, err := svcClient.IritaClient.WASM.QueryContract("iaa1ghd753shjuwexxywmgs4xz7x2q732vcnednxe6", nil)
+	result, err := svcClient.Endpoints.Client().WASM.QueryContract("iaa1ghd753shjuwexxywmgs4xz7x2q732vcnednxe6", nil)
 	if err != nil {
 		t.Error(err)
 		return